@@ -8,15 +8,32 @@ import (
 	"doc-translate-go/gen/go/proto/documentprocessor"
 	"doc-translate-go/pkg/config"
 	"doc-translate-go/pkg/file/queue"
+	"doc-translate-go/pkg/file/repository"
+	"doc-translate-go/pkg/format"
+	"doc-translate-go/pkg/mask"
+	"doc-translate-go/pkg/ooxml"
+	"doc-translate-go/pkg/subtitle"
 	"doc-translate-go/pkg/tracker"
 	"doc-translate-go/pkg/translator"
 	"doc-translate-go/rest/v1/handler"
+	"errors"
 	"fmt"
 	"log"
-
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	fileFS "doc-translate-go/pkg/file/repository/filesystem"
 	filePG "doc-translate-go/pkg/file/repository/postgresql"
 	fileS3 "doc-translate-go/pkg/file/repository/s3"
 	fileUC "doc-translate-go/pkg/file/usecase"
+	glossaryPG "doc-translate-go/pkg/glossary/repository/postgresql"
+	glossaryUC "doc-translate-go/pkg/glossary/usecase"
+	memoryPG "doc-translate-go/pkg/memory/repository/postgresql"
+	memoryUC "doc-translate-go/pkg/memory/usecase"
 
 	userPG "doc-translate-go/pkg/user/repository/postgresql"
 	userUC "doc-translate-go/pkg/user/usecase"
@@ -42,6 +59,13 @@ var (
 
 	db *sql.DB
 
+	redisClient *redis.ClusterClient
+
+	translatorChain *translator.FallbackTranslator
+
+	// Only set when FILE_STORE is filesystem, it serves its own signed urls
+	fsFileRepo *fileFS.FilesystemFileRepository
+
 	userUseCase           *userUC.UserUseCase
 	authUseCase           *userUC.AuthUseCase
 	fileUseCase           *fileUC.FileUseCase
@@ -49,12 +73,35 @@ var (
 	translFileMetaUseCase *fileUC.TranslatedFileMetadataUseCase
 	translateUseCase      *fileUC.TranslateUseCase
 	progressUseCase       *fileUC.ProgressUseCase
+	deadLetterUseCase     *fileUC.DeadLetterUseCase
+	quoteUseCase          *fileUC.QuoteUseCase
+	glossaryUseCase       *glossaryUC.GlossaryUseCase
+	memoryUseCase         *memoryUC.TranslationMemoryUseCase
 )
 
 func init() {
+	if !runsApi() && !runsWorker() {
+		log.Fatalf("unknown %s %q, expected api, worker or all", config.ENV_MODE, conf.App.Mode)
+	}
+
 	initDb()
 	initUseCases()
-	initSwagger()
+
+	if runsApi() {
+		initSwagger()
+	}
+}
+
+// runsApi tells whether the process serves the REST API.
+func runsApi() bool {
+	return conf.App.Mode == "api" || conf.App.Mode == "all"
+}
+
+// runsWorker tells whether the process translates files off the queue.
+// Workers serve nothing of their own, their status routes are only served
+// alongside the API, which authenticates the admins they're for.
+func runsWorker() bool {
+	return conf.App.Mode == "worker" || conf.App.Mode == "all"
 }
 
 // @title DocsTranslateBackend
@@ -67,17 +114,60 @@ func main() {
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 
-	e.GET("/swagger/*", echoSwagger.WrapHandler)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if runsApi() {
+		e.GET("/swagger/*", echoSwagger.WrapHandler)
+		addRoutes(ctx, e)
+	}
+
+	// Translations already taken outlive ctx, until the shutdown deadline
+	workCtx, cancelWork := context.WithCancel(context.Background())
+	defer cancelWork()
+
+	workersDone := make(chan struct{})
+
+	if runsWorker() {
+		if runsApi() {
+			addWorkerRoutes(e)
+		}
+
+		go func() {
+			defer close(workersDone)
+			translateUseCase.ListenAndExecute(ctx, workCtx)
+		}()
+
+		go translateUseCase.WatchStale(ctx)
+	} else {
+		close(workersDone)
+	}
+
+	if runsApi() {
+		go func() {
+			if err := e.Start(conf.App.Addr); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("unable to start server: %v", err)
+			}
+		}()
+	}
 
-	addRoutes(e)
+	<-ctx.Done()
+	log.Println("shutting down")
 
-	ctx, cancel := context.WithCancel(context.Background())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(conf.App.ShutdownTimeoutSeconds)*time.Second)
 	defer cancel()
-	go translateUseCase.ListenAndExecute(ctx)
 
-	err := e.Start(conf.App.Addr)
-	if err != nil {
-		panic(err)
+	// Stops accepting requests and waits for the open ones
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		log.Printf("unable to shut down server: %v", err)
+	}
+
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		// Interrupted tasks are handed back to the queue
+		cancelWork()
+		<-workersDone
 	}
 }
 
@@ -113,24 +203,43 @@ func initUseCases() {
 	if err != nil {
 		log.Fatalf("unable to get aws session: %v", err)
 	}
-	s3Uploader := s3manager.NewUploader(awsSession)
-	s3Downloader := s3manager.NewDownloader(awsSession)
-	s3Service := s3.New(awsSession)
 
-	fileRepo := fileS3.NewS3FileRepository(s3Uploader, s3Downloader, s3Service, conf.Aws.S3BucketName)
+	fileRepo := getFileRepository(awsSession)
 	fileUseCase = fileUC.NewFileUseCase(fileRepo)
 
-	// User
-	userRepo := userPG.NewPostgresqlUserRepository(db)
-	userUseCase = userUC.NewUserUseCase(userRepo)
-
-	// Auth
-	authUseCase = userUC.NewAuthUseCase(conf.Auth)
-
 	// Translate
 	fileTracker := getFileTracker()
 	translateQueue := getTranslateQueue(awsSession)
-	translr := getTranslator()
+
+	// The API only queues files, translating is up to the workers
+	var translr translator.Translator
+	if runsWorker() {
+		translr = getTranslator(fileRepo)
+	}
+
+	formats := getFormats()
+	ooxml.MaxPartSize = int64(conf.Translate.MaxPartBytes)
+
+	// Quotes estimate with it and workers bill with it
+	priceTable := fileUC.NewPriceTable(
+		conf.Translate.Prices,
+		conf.Translate.Currency,
+		float64(conf.Translate.TokensPerSecond),
+	)
+
+	// Dead Letter
+	deadLetterRepo := filePG.NewPostgresqlDeadLetterRepository(db)
+	deadLetterUseCase = fileUC.NewDeadLetterUseCase(deadLetterRepo, translateQueue, fileTracker)
+
+	// Glossary, the API checks the one a file is queued with and workers translate with it
+	userRepo := userPG.NewPostgresqlUserRepository(db)
+	glossaryRepo := glossaryPG.NewPostgresqlGlossaryRepository(db)
+	glossaryUseCase = glossaryUC.NewGlossaryUseCase(glossaryRepo, userRepo)
+
+	// Translation memory, admins import and export it and workers look segments up in it
+	memoryRepo := memoryPG.NewPostgresqlTranslationMemoryRepository(db)
+	memoryUseCase = memoryUC.NewTranslationMemoryUseCase(memoryRepo, conf.Translate.MemoryFuzzyThreshold)
+
 	translateUseCase = fileUC.NewTranslateUseCase(
 		translr,
 		origFileMetaUseCase,
@@ -138,37 +247,174 @@ func initUseCases() {
 		fileUseCase,
 		fileTracker,
 		translateQueue,
+		fileUC.NewWorkerPool(conf.App.TranslateWorkers),
+		fileUC.NewRetryPolicy(
+			conf.App.TranslateMaxAttempts,
+			time.Duration(conf.App.TranslateRetryBaseSeconds)*time.Second,
+			time.Duration(conf.App.TranslateRetryMaxSeconds)*time.Second,
+		),
+		fileUC.ReuseScope(conf.App.TranslateReuseScope),
+		formats,
+		fileUC.TranslateOptions{
+			DeadLetterUC: deadLetterUseCase,
+			PriceTable:   priceTable,
+			LineLengths:  subtitle.LineLengths(conf.Translate.SubtitleLineLengths),
+			GlossaryUC:   glossaryUseCase,
+			MemoryUC:     memoryUseCase,
+			StaleAfter:   time.Duration(conf.App.TranslateStaleSeconds) * time.Second,
+		},
 	)
 
+	if !runsApi() {
+		return
+	}
+
+	// User and auth
+	userUseCase = userUC.NewUserUseCase(userRepo)
+	authUseCase = userUC.NewAuthUseCase(conf.Auth)
+
 	// Progress
 	progressUseCase = fileUC.NewProgressUseCase(fileTracker)
+
+	// Quote
+	quoteUseCase = fileUC.NewQuoteUseCase(priceTable, formats)
 }
 
-func getTranslator() translator.Translator {
-	var translr translator.Translator
+// getFormats returns the MIME types of the formats TRANSLATE_FORMATS allows.
+func getFormats() []string {
+	var formats []string
 
-	switch conf.App.Translator {
-	case "echo":
-		translr = translator.NewEchoTranslator()
-	default:
-		grpcConn, err := grpc.Dial(conf.Translate.GrpcServer, grpc.WithTransportCredentials(insecure.NewCredentials()))
-		if err != nil {
-			log.Fatalf("unable to dial translate grpc server: %v", err)
+	for _, name := range conf.App.TranslateFormats {
+		mimeType, ok := format.Lookup(name)
+		if !ok {
+			log.Fatalf("unknown format %q in %s, expected docx, pptx, xlsx, txt, md, html, srt or vtt", name, config.ENV_TRANSLATE_FORMATS)
 		}
-		grpcClient := documentprocessor.NewDocumentProcessorClient(grpcConn)
-		translr = translator.NewGrpcTranslator(grpcClient)
+		formats = append(formats, mimeType)
 	}
 
+	return formats
+}
+
+func getTranslator(fileRepo repository.FileRepository) translator.Translator {
+	// TRANSLATOR lists backends in the order they are tried, e.g. grpc,echo
+	var backends []*translator.Backend
+
+	for _, name := range strings.Split(conf.App.Translator, ",") {
+		name = strings.TrimSpace(name)
+		breaker := translator.NewCircuitBreaker(conf.Translate.BreakerFailures, time.Duration(conf.Translate.BreakerOpenSeconds)*time.Second)
+
+		switch name {
+		case "echo":
+			backends = append(backends, translator.NewBackend(name, translator.NewEchoTranslator(), 0, breaker))
+		default:
+			grpcConn, err := grpc.Dial(conf.Translate.GrpcServer, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				log.Fatalf("unable to dial translate grpc server: %v", err)
+			}
+			grpcClient := documentprocessor.NewDocumentProcessorClient(grpcConn)
+
+			grpcTranslator := translator.NewGrpcTranslator(grpcClient, conf.Translate.GrpcStreamThreshold, conf.Translate.GrpcChunkSize, conf.Translate.GrpcProgress)
+			timeout := time.Duration(conf.Translate.GrpcTimeoutSeconds) * time.Second
+			backends = append(backends, translator.NewBackend(name, grpcTranslator, timeout, breaker))
+		}
+	}
+
+	translatorChain = translator.NewFallbackTranslator(backends, fileUC.IsTransient)
+
+	var translr translator.Translator = translatorChain
+
+	cacheTtl := time.Duration(conf.Translate.CacheTtlSeconds) * time.Second
+
+	switch conf.App.TranslateCache {
+	case "memory":
+		cache := translator.NewLRUCache(conf.Translate.CacheMaxBytes, cacheTtl)
+		translr = translator.NewCachingTranslator(translr, cache, conf.Translate.CacheMaxItemBytes)
+	case "redis":
+		cache := translator.NewRedisCache(getRedisClient(), cacheTtl)
+		translr = translator.NewCachingTranslator(translr, cache, conf.Translate.CacheMaxItemBytes)
+	case "file":
+		cache := translator.NewFileCache(fileRepo, "translation-cache", cacheTtl)
+		translr = translator.NewCachingTranslator(translr, cache, conf.Translate.CacheMaxItemBytes)
+	}
+
+	// Masking goes outside the cache so cached translations are restored too
+	rules, err := mask.NewRules(getProductCodePattern(), conf.Translate.ProtectPattern)
+	if err != nil {
+		log.Fatalf("invalid protected text pattern: %v", err)
+	}
+	translr = translator.NewMaskingTranslator(translr, rules)
+
 	return translr
 }
 
+// getProductCodePattern returns the pattern of product codes to protect, the
+// default one unless TRANSLATE_PRODUCT_CODE_PATTERN replaces it, or none when
+// it's none.
+func getProductCodePattern() string {
+	switch conf.Translate.ProductCodePattern {
+	case "":
+		return mask.DefaultProductCodes
+	case "none":
+		return ""
+	}
+
+	return conf.Translate.ProductCodePattern
+}
+
+func getFileRepository(awsSession *session.Session) repository.FileRepository {
+	var fileRepo repository.FileRepository
+
+	switch conf.App.FileStore {
+	case "filesystem":
+		if conf.Filesystem.SigningSecret == "" {
+			log.Fatalf("%s is required for the filesystem file store", config.ENV_FILESYSTEM_SIGNING_SECRET)
+		}
+
+		fsFileRepo = fileFS.NewFilesystemFileRepository(
+			conf.Filesystem.Root,
+			[]byte(conf.Filesystem.SigningSecret),
+			conf.Filesystem.BaseUrl,
+			time.Duration(conf.Filesystem.UrlExpirySeconds)*time.Second,
+		)
+		fileRepo = fsFileRepo
+	default:
+		s3Uploader := s3manager.NewUploader(awsSession)
+		s3Downloader := s3manager.NewDownloader(awsSession)
+		s3Service := s3.New(awsSession)
+		fileRepo = fileS3.NewS3FileRepository(s3Uploader, s3Downloader, s3Service, conf.Aws.S3BucketName)
+	}
+
+	return fileRepo
+}
+
 func getTranslateQueue(awsSession *session.Session) queue.TranslateQueue {
 	var translateQueue queue.TranslateQueue
 
 	switch conf.App.TranslateQueue {
 	case "chan":
-		c := make(chan *queue.TranslateTask, 1<<32)
+		if conf.App.Mode != "all" {
+			log.Fatalf("the chan translate queue can't be shared between processes, use %s all", config.ENV_MODE)
+		}
+
+		// Add blocks once the buffer is full, a 1<<32 buffer of pointers needs 32 GB up front
+		c := make(chan *queue.TranslateTask, 1<<16)
 		translateQueue = queue.NewChannelTranslateQueue(c)
+	case "redis":
+		redisQueue := queue.NewRedisStreamTranslateQueue(
+			getRedisClient(),
+			conf.Redis.Stream,
+			conf.Redis.StreamGroup,
+			conf.Redis.StreamConsumer,
+			time.Duration(conf.Redis.StreamClaimIdleSeconds)*time.Second,
+		)
+
+		if err := redisQueue.CreateGroup(); err != nil {
+			log.Fatalf("failed to create redis stream consumer group: %v", err)
+		}
+
+		translateQueue = redisQueue
+	case "postgresql":
+		translateQueue = queue.NewPostgresqlTranslateQueue(db, time.Duration(conf.App.TranslateQueueLeaseSeconds)*time.Second)
 	default:
 		sqsClient := sqs.New(awsSession)
 		translateQueue = queue.NewSqsTranslateQueue(sqsClient, conf.Aws.SqsQueueUrl, conf.Aws.SqsGroupId)
@@ -181,32 +427,54 @@ func getFileTracker() tracker.FileTracker {
 	var fileTracker tracker.FileTracker
 
 	switch conf.App.FileTracker {
+	case "memory":
+		if conf.App.Mode != "all" {
+			log.Fatalf("the memory file tracker can't be shared between processes, use %s all", config.ENV_MODE)
+		}
+
+		fileTracker = tracker.NewMemoryFileTracker(conf.Redis.ExpirySeconds)
 	default:
-		redisClient := redis.NewClusterClient(&redis.ClusterOptions{
-			Addrs:    conf.Redis.Addrs,
-			Password: conf.Redis.Password,
-			TLSConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		})
+		fileTracker = tracker.NewRedisFileTracker(getRedisClient(), conf.Redis.ExpirySeconds)
+	}
 
-		fileTracker = tracker.NewRedisFileTracker(redisClient, conf.Redis.ExpirySeconds)
+	return fileTracker
+}
 
-		if err := redisClient.Ping(context.Background()).Err(); err != nil {
-			log.Fatalf("failed to ping redis: %v", err)
-		}
+// getRedisClient connects to the redis cluster on first use so the tracker
+// and the queue share one client.
+func getRedisClient() *redis.ClusterClient {
+	if redisClient != nil {
+		return redisClient
 	}
 
-	return fileTracker
+	redisClient = redis.NewClusterClient(&redis.ClusterOptions{
+		Addrs:    conf.Redis.Addrs,
+		Password: conf.Redis.Password,
+		TLSConfig: &tls.Config{
+			InsecureSkipVerify: true,
+		},
+	})
+
+	if err := redisClient.Ping(context.Background()).Err(); err != nil {
+		log.Fatalf("failed to ping redis: %v", err)
+	}
+
+	return redisClient
 }
 
-func addRoutes(e *echo.Echo) {
+func addRoutes(ctx context.Context, e *echo.Echo) {
 	e.POST(
 		"/translate-docx",
 		func(c echo.Context) error { return handler.TranslateDocx(c, translateUseCase) },
 		myMiddleware.AuthMiddleware(userUseCase, authUseCase),
 	)
 
+	e.POST(
+		"/quote",
+		func(c echo.Context) error { return handler.Quote(c, quoteUseCase) },
+		myMiddleware.AuthMiddleware(userUseCase, authUseCase),
+	)
+
 	e.DELETE(
 		"/delete-translated-files",
 		func(c echo.Context) error {
@@ -236,9 +504,132 @@ func addRoutes(e *echo.Echo) {
 		func(c echo.Context) error {
 			return handler.UploadProgress(c, progressUseCase)
 		},
+		myMiddleware.AuthMiddleware(userUseCase, authUseCase),
 	)
 
+	e.GET(
+		"/upload-progress/stream",
+		func(c echo.Context) error {
+			return handler.UploadProgressStream(c, progressUseCase)
+		},
+		myMiddleware.ShutdownMiddleware(ctx),
+		myMiddleware.AuthMiddleware(userUseCase, authUseCase),
+	)
+
+	e.GET(
+		"/dead-letters",
+		func(c echo.Context) error {
+			return handler.ListDeadLetters(c, deadLetterUseCase)
+		},
+		myMiddleware.AuthMiddleware(userUseCase, authUseCase),
+		myMiddleware.AdminMiddleware(userUseCase),
+	)
+
+	e.POST(
+		"/dead-letters/requeue",
+		func(c echo.Context) error {
+			return handler.RequeueDeadLetters(c, deadLetterUseCase)
+		},
+		myMiddleware.AuthMiddleware(userUseCase, authUseCase),
+		myMiddleware.AdminMiddleware(userUseCase),
+	)
+
+	e.GET(
+		"/glossaries",
+		func(c echo.Context) error {
+			return handler.ListGlossaries(c, glossaryUseCase)
+		},
+		myMiddleware.AuthMiddleware(userUseCase, authUseCase),
+	)
+
+	e.POST(
+		"/glossaries",
+		func(c echo.Context) error {
+			return handler.CreateGlossary(c, glossaryUseCase)
+		},
+		myMiddleware.AuthMiddleware(userUseCase, authUseCase),
+	)
+
+	e.GET(
+		"/glossaries/:id",
+		func(c echo.Context) error {
+			return handler.GetGlossary(c, glossaryUseCase)
+		},
+		myMiddleware.AuthMiddleware(userUseCase, authUseCase),
+	)
+
+	e.PUT(
+		"/glossaries/:id",
+		func(c echo.Context) error {
+			return handler.UpdateGlossary(c, glossaryUseCase)
+		},
+		myMiddleware.AuthMiddleware(userUseCase, authUseCase),
+	)
+
+	e.DELETE(
+		"/glossaries/:id",
+		func(c echo.Context) error {
+			return handler.DeleteGlossary(c, glossaryUseCase)
+		},
+		myMiddleware.AuthMiddleware(userUseCase, authUseCase),
+	)
+
+	e.POST(
+		"/glossaries/:id/import",
+		func(c echo.Context) error {
+			return handler.ImportGlossary(c, glossaryUseCase)
+		},
+		myMiddleware.AuthMiddleware(userUseCase, authUseCase),
+	)
+
+	e.POST(
+		"/translation-memory/import",
+		func(c echo.Context) error {
+			return handler.ImportTranslationMemory(c, memoryUseCase)
+		},
+		myMiddleware.AuthMiddleware(userUseCase, authUseCase),
+		myMiddleware.AdminMiddleware(userUseCase),
+	)
+
+	e.GET(
+		"/translation-memory/export",
+		func(c echo.Context) error {
+			return handler.ExportTranslationMemory(c, memoryUseCase)
+		},
+		myMiddleware.AuthMiddleware(userUseCase, authUseCase),
+		myMiddleware.AdminMiddleware(userUseCase),
+	)
+
+	if fsFileRepo != nil {
+		e.GET(
+			"/files",
+			func(c echo.Context) error {
+				return handler.DownloadFile(c, fsFileRepo)
+			},
+		)
+	}
+
 	e.GET("/authorize", func(c echo.Context) error { return handler.Authorize(c, authUseCase) })
 
 	e.GET("/token", func(c echo.Context) error { return handler.Token(c, authUseCase) })
 }
+
+func addWorkerRoutes(e *echo.Echo) {
+	e.GET(
+		"/worker-status",
+		func(c echo.Context) error {
+			return handler.WorkerStatus(c, translateUseCase)
+		},
+		myMiddleware.AuthMiddleware(userUseCase, authUseCase),
+		myMiddleware.AdminMiddleware(userUseCase),
+	)
+
+	e.GET(
+		"/translator-status",
+		func(c echo.Context) error {
+			return handler.TranslatorStatus(c, translatorChain)
+		},
+		myMiddleware.AuthMiddleware(userUseCase, authUseCase),
+		myMiddleware.AdminMiddleware(userUseCase),
+	)
+}
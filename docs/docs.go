@@ -52,6 +52,107 @@ const docTemplate = `{
                 }
             }
         },
+        "/dead-letters": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List translate tasks that failed permanently or ran out of attempts. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List dead-lettered translate tasks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.DeadLetter"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/dead-letters/requeue": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Put dead-lettered translate tasks back on the translate queue. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Requeue dead-lettered translate tasks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Requeue Request",
+                        "name": "requeue_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RequeueDeadLettersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/delete-translated-files": {
             "delete": {
                 "description": "Delete Files",
@@ -159,24 +260,85 @@ const docTemplate = `{
                 }
             }
         },
-        "/show-translated-files": {
+        "/files": {
+            "get": {
+                "description": "Serves a file from the local filesystem store through a signed, expiring url returned by the file store. Only available when FILE_STORE is filesystem.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Files"
+                ],
+                "summary": "Download a file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File path",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expiry as unix seconds",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Url signature",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Invalid or expired url",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "File not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/glossaries": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List out all translated files user has",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List the glossaries the user created or shares with their team.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Files"
+                    "Glossaries"
                 ],
-                "summary": "Show all translated files",
+                "summary": "List glossaries",
                 "parameters": [
                     {
                         "type": "string",
@@ -192,22 +354,19 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/entity.TranslatedFileMetadata"
+                                "$ref": "#/definitions/entity.Glossary"
                             }
                         }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
                     }
                 }
-            }
-        },
-        "/token": {
+            },
             "post": {
-                "description": "Retrieves access token based on the provided grant type and token.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a glossary of terms for one language pair, personal or shared with the user's team. Terms are required unless set otherwise.",
                 "consumes": [
                     "application/json"
                 ],
@@ -215,45 +374,58 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Glossaries"
                 ],
+                "summary": "Create a glossary",
                 "parameters": [
                     {
-                        "description": "Token Request",
-                        "name": "request",
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Glossary",
+                        "name": "glossary_request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.TokenRequest"
+                            "$ref": "#/definitions/handler.CreateGlossaryRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Access Token Response",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/entity.Glossary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/translate-docx": {
-            "post": {
+        "/glossaries/{id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Send multiple files to the gRPC server for translation along with source and target language selections.",
-                "consumes": [
-                    "multipart/form-data"
+                "description": "Get a glossary the user created or shares with their team, with its terms.",
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
-                    "Files"
+                    "Glossaries"
                 ],
-                "summary": "Translate multiple DOCX files",
+                "summary": "Get a glossary",
                 "parameters": [
                     {
                         "type": "string",
@@ -263,49 +435,35 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "array",
-                        "items": {
-                            "type": "file"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "Upload files",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Source Language",
-                        "name": "sourceLang",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Target Language",
-                        "name": "targetLang",
-                        "in": "formData",
+                        "type": "integer",
+                        "description": "Glossary id",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Files sent successfully",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Glossary"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/upload-progress": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieve the file upload progress based on isid",
+                "description": "Rename a glossary and replace its terms. Its languages and team stay as they are.",
                 "consumes": [
                     "application/json"
                 ],
@@ -313,54 +471,843 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Files"
+                    "Glossaries"
                 ],
-                "summary": "Get File upload progress",
+                "summary": "Update a glossary",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Authorization",
                         "name": "Authorization",
-                        "in": "header"
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Glossary id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Glossary",
+                        "name": "glossary_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateGlossaryRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Glossary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "entity.TranslatedFileMetadata": {
-            "type": "object",
-            "properties": {
-                "cost": {
-                    "type": "number"
-                },
-                "createdAt": {
-                    "type": "string"
-                },
-                "createdBy": {
-                    "type": "string"
-                },
-                "filename": {
-                    "type": "string"
-                },
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a glossary. Files already translated with it keep their terminology flags.",
+                "tags": [
+                    "Glossaries"
+                ],
+                "summary": "Delete a glossary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Glossary id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/glossaries/{id}/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add the terms of a CSV or TBX file to a glossary, replacing those with the same source. CSV rows are source, target and, optionally, whether the term is required. TBX terms are all required.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Glossaries"
+                ],
+                "summary": "Import terms into a glossary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Glossary id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV or TBX file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Glossary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/quote": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Count the words, characters and tokens of a file and estimate the cost and duration of translating it into each target language. Nothing is stored or queued.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Files"
+                ],
+                "summary": "Estimate the cost of translating a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Upload file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Source Language",
+                        "name": "sourceLang",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Target Languages, repeated or comma separated",
+                        "name": "targetLang",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.FileQuote"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Document decompresses past TRANSLATE_MAX_PART_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/show-translated-files": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List out all translated files user has",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Files"
+                ],
+                "summary": "Show all translated files",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.TranslatedFileMetadata"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/token": {
+            "post": {
+                "description": "Retrieves access token based on the provided grant type and token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "parameters": [
+                    {
+                        "description": "Token Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.TokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Access Token Response",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/translate-docx": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Send multiple files to the gRPC server for translation along with source and target language selections. DOCX, PPTX, XLSX, plain text, Markdown, HTML, SRT and WebVTT files are detected by content and must be enabled by TRANSLATE_FORMATS.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "Files"
+                ],
+                "summary": "Translate multiple documents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "file"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Upload files",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Source Language",
+                        "name": "sourceLang",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Target Languages, repeated or comma separated",
+                        "name": "targetLang",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Glossary to translate with, for the source and every target language",
+                        "name": "glossaryId",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Files sent successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "No target language, file extension doesn't match its content, or glossary for other languages",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Glossary not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Document decompresses past TRANSLATE_MAX_PART_BYTES",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported or disallowed format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/translation-memory/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Download the translations from one language into another as a TMX 1.4 file. Admin only.",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Export the translation memory as a TMX file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Source language",
+                        "name": "source_lang",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target language",
+                        "name": "target_lang",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/translation-memory/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Store the translations of a TMX 1.4 file from one language into another as approved, replacing those stored for the same segments. Regional variants of the languages, such as en-US for en, are imported too. Admin only.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Import a TMX file into the translation memory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "TMX file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Source language",
+                        "name": "source_lang",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target language",
+                        "name": "target_lang",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ImportTranslationMemoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/translator-status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the circuit breaker of every translator backend in the order they are tried. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Monitoring"
+                ],
+                "summary": "Show translator circuit breaker states",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/translator.BreakerState"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/upload-progress": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve the file upload progress based on isid",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Files"
+                ],
+                "summary": "Get File upload progress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/upload-progress/stream": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Push the user's file statuses as Server-Sent Events. The current statuses are sent first, then a \"status\" event whenever a file is in progress, failed or done, and a \"removed\" event when its status is cleared.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Files"
+                ],
+                "summary": "Stream file upload progress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Status"
+                        }
+                    }
+                }
+            }
+        },
+        "/worker-status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List whether each translate worker is busy translating or idle waiting for a task. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Monitoring"
+                ],
+                "summary": "Show translate worker states",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/usecase.WorkerState"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "entity.DeadLetter": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "stage": {
+                    "type": "string"
+                },
+                "task": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Flag": {
+            "type": "object",
+            "properties": {
+                "paragraph": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Glossary": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "source_lang": {
+                    "type": "string"
+                },
+                "target_lang": {
+                    "type": "string"
+                },
+                "team": {
+                    "type": "string"
+                },
+                "terms": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Term"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Term": {
+            "type": "object",
+            "properties": {
+                "required": {
+                    "type": "boolean"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.TranslatedFileMetadata": {
+            "type": "object",
+            "properties": {
+                "billedCharacters": {
+                    "type": "integer"
+                },
+                "billedTokens": {
+                    "type": "integer"
+                },
+                "cost": {
+                    "type": "number"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "glossaryId": {
+                    "description": "GlossaryId is the glossary the file was translated with, 0 for none,\nand TermFlags the paragraphs whose translation misses one of its\nrequired terms",
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "memoryFuzzyHits": {
+                    "type": "integer"
+                },
+                "memoryHits": {
+                    "description": "MemoryHits are the segments of a file translated from an exact\ntranslation memory match, MemoryFuzzyHits those sent for translation\nwith a close match and MemoryMisses those sent without any. They're nil\nfor files whose segments weren't looked up, such as reused translations",
+                    "type": "integer"
+                },
+                "memoryMisses": {
+                    "type": "integer"
+                },
                 "originalFileId": {
                     "type": "integer"
                 },
+                "queueTime": {
+                    "type": "integer"
+                },
                 "targetLanguage": {
                     "type": "string"
                 },
+                "termFlags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Flag"
+                    }
+                },
                 "timeTaken": {
+                    "description": "TimeTaken is how long translating took and QueueTime how long the file\nwaited for a worker before that, both in milliseconds",
                     "type": "integer"
                 },
                 "updatedAt": {
@@ -368,6 +1315,30 @@ const docTemplate = `{
                 }
             }
         },
+        "handler.CreateGlossaryRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "source_lang": {
+                    "type": "string"
+                },
+                "target_lang": {
+                    "type": "string"
+                },
+                "team": {
+                    "description": "Team shares the glossary with the members of the user's team",
+                    "type": "boolean"
+                },
+                "terms": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Term"
+                    }
+                }
+            }
+        },
         "handler.DeleteFilesRequest": {
             "type": "object",
             "properties": {
@@ -402,6 +1373,64 @@ const docTemplate = `{
                 }
             }
         },
+        "handler.ImportTranslationMemoryResponse": {
+            "type": "object",
+            "properties": {
+                "imported": {
+                    "description": "Imported is how many translations were stored",
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.RequeueDeadLettersRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handler.Status": {
+            "type": "object",
+            "properties": {
+                "file": {
+                    "type": "string"
+                },
+                "progress": {
+                    "type": "integer"
+                },
+                "source_lang": {
+                    "type": "string"
+                },
+                "stage": {
+                    "enum": [
+                        "queued",
+                        "downloading",
+                        "translating",
+                        "uploading",
+                        "done",
+                        "failed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tracker.Stage"
+                        }
+                    ]
+                },
+                "status": {
+                    "type": "string"
+                },
+                "target_lang": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
         "handler.TokenRequest": {
             "type": "object",
             "properties": {
@@ -412,6 +1441,122 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "handler.UpdateGlossaryRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Name is left as it is when empty",
+                    "type": "string"
+                },
+                "terms": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Term"
+                    }
+                }
+            }
+        },
+        "tracker.Stage": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "downloading",
+                "translating",
+                "uploading",
+                "done",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "StageQueued",
+                "StageDownloading",
+                "StageTranslating",
+                "StageUploading",
+                "StageDone",
+                "StageFailed"
+            ]
+        },
+        "translator.BreakerState": {
+            "type": "object",
+            "properties": {
+                "failures": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "closed",
+                        "open",
+                        "half-open"
+                    ]
+                }
+            }
+        },
+        "usecase.FileQuote": {
+            "type": "object",
+            "properties": {
+                "characters": {
+                    "type": "integer"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "mime_type": {
+                    "type": "string"
+                },
+                "quotes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usecase.Quote"
+                    }
+                },
+                "source_lang": {
+                    "type": "string"
+                },
+                "tokens": {
+                    "type": "integer"
+                },
+                "words": {
+                    "type": "integer"
+                }
+            }
+        },
+        "usecase.Quote": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "duration_seconds": {
+                    "type": "integer"
+                },
+                "target_lang": {
+                    "type": "string"
+                }
+            }
+        },
+        "usecase.WorkerState": {
+            "type": "object",
+            "properties": {
+                "busy": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "since": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
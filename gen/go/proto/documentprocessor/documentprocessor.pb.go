@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.28.1
+// 	protoc-gen-go v1.32.0
 // 	protoc        v4.25.3
 // source: proto/documentprocessor/documentprocessor.proto
 
@@ -28,6 +28,19 @@ type DocumentRequest struct {
 	Document   []byte `protobuf:"bytes,1,opt,name=document,proto3,oneof" json:"document,omitempty"`
 	SourceLang string `protobuf:"bytes,2,opt,name=sourceLang,proto3" json:"sourceLang,omitempty"`
 	TargetLang string `protobuf:"bytes,3,opt,name=targetLang,proto3" json:"targetLang,omitempty"`
+	// MIME type of the document, DOCX when empty. text/plain documents are
+	// segments of a larger file and may hold numbered <gN>...</gN> and <xN/>
+	// tags standing for its markup, which must be kept in the translation.
+	// Text that must not be translated is masked as <xN/> tags too, in
+	// text/plain segments and in the text of DOCX, PPTX and XLSX documents,
+	// and the translation fails if any of them is lost.
+	MimeType string `protobuf:"bytes,4,opt,name=mimeType,proto3" json:"mimeType,omitempty"`
+	// Terms that must be translated as given.
+	Glossary []*GlossaryTerm `protobuf:"bytes,5,rep,name=glossary,proto3" json:"glossary,omitempty"`
+	// Approved translations of text close to a text/plain segment, or to
+	// paragraphs of an office document, to translate it alike. Paragraphs
+	// with an approved translation of their exact text arrive translated.
+	Memory []*MemoryMatch `protobuf:"bytes,6,rep,name=memory,proto3" json:"memory,omitempty"`
 }
 
 func (x *DocumentRequest) Reset() {
@@ -83,18 +96,170 @@ func (x *DocumentRequest) GetTargetLang() string {
 	return ""
 }
 
+func (x *DocumentRequest) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *DocumentRequest) GetGlossary() []*GlossaryTerm {
+	if x != nil {
+		return x.Glossary
+	}
+	return nil
+}
+
+func (x *DocumentRequest) GetMemory() []*MemoryMatch {
+	if x != nil {
+		return x.Memory
+	}
+	return nil
+}
+
+type GlossaryTerm struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Source string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Target string `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	// Whether translations missing the term are flagged once back.
+	Required bool `protobuf:"varint,3,opt,name=required,proto3" json:"required,omitempty"`
+}
+
+func (x *GlossaryTerm) Reset() {
+	*x = GlossaryTerm{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_documentprocessor_documentprocessor_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GlossaryTerm) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GlossaryTerm) ProtoMessage() {}
+
+func (x *GlossaryTerm) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_documentprocessor_documentprocessor_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GlossaryTerm.ProtoReflect.Descriptor instead.
+func (*GlossaryTerm) Descriptor() ([]byte, []int) {
+	return file_proto_documentprocessor_documentprocessor_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GlossaryTerm) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *GlossaryTerm) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *GlossaryTerm) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+type MemoryMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Source string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Target string `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	// How similar source is to the segment, from 0 to 1.
+	Score float64 `protobuf:"fixed64,3,opt,name=score,proto3" json:"score,omitempty"`
+}
+
+func (x *MemoryMatch) Reset() {
+	*x = MemoryMatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_documentprocessor_documentprocessor_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MemoryMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MemoryMatch) ProtoMessage() {}
+
+func (x *MemoryMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_documentprocessor_documentprocessor_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MemoryMatch.ProtoReflect.Descriptor instead.
+func (*MemoryMatch) Descriptor() ([]byte, []int) {
+	return file_proto_documentprocessor_documentprocessor_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MemoryMatch) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *MemoryMatch) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *MemoryMatch) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
 type DocumentResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	Document []byte `protobuf:"bytes,1,opt,name=document,proto3,oneof" json:"document,omitempty"`
+	// What the translation was billed for, 0 when the processor doesn't meter it.
+	BilledCharacters int64 `protobuf:"varint,2,opt,name=billedCharacters,proto3" json:"billedCharacters,omitempty"`
+	BilledTokens     int64 `protobuf:"varint,3,opt,name=billedTokens,proto3" json:"billedTokens,omitempty"`
 }
 
 func (x *DocumentResponse) Reset() {
 	*x = DocumentResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_documentprocessor_documentprocessor_proto_msgTypes[1]
+		mi := &file_proto_documentprocessor_documentprocessor_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -107,7 +272,7 @@ func (x *DocumentResponse) String() string {
 func (*DocumentResponse) ProtoMessage() {}
 
 func (x *DocumentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_documentprocessor_documentprocessor_proto_msgTypes[1]
+	mi := &file_proto_documentprocessor_documentprocessor_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -120,7 +285,7 @@ func (x *DocumentResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DocumentResponse.ProtoReflect.Descriptor instead.
 func (*DocumentResponse) Descriptor() ([]byte, []int) {
-	return file_proto_documentprocessor_documentprocessor_proto_rawDescGZIP(), []int{1}
+	return file_proto_documentprocessor_documentprocessor_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *DocumentResponse) GetDocument() []byte {
@@ -130,6 +295,252 @@ func (x *DocumentResponse) GetDocument() []byte {
 	return nil
 }
 
+func (x *DocumentResponse) GetBilledCharacters() int64 {
+	if x != nil {
+		return x.BilledCharacters
+	}
+	return 0
+}
+
+func (x *DocumentResponse) GetBilledTokens() int64 {
+	if x != nil {
+		return x.BilledTokens
+	}
+	return 0
+}
+
+type DocumentChunkRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Chunk      []byte          `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	SourceLang string          `protobuf:"bytes,2,opt,name=sourceLang,proto3" json:"sourceLang,omitempty"`
+	TargetLang string          `protobuf:"bytes,3,opt,name=targetLang,proto3" json:"targetLang,omitempty"`
+	MimeType   string          `protobuf:"bytes,4,opt,name=mimeType,proto3" json:"mimeType,omitempty"`
+	Glossary   []*GlossaryTerm `protobuf:"bytes,5,rep,name=glossary,proto3" json:"glossary,omitempty"`
+	Memory     []*MemoryMatch  `protobuf:"bytes,6,rep,name=memory,proto3" json:"memory,omitempty"`
+}
+
+func (x *DocumentChunkRequest) Reset() {
+	*x = DocumentChunkRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_documentprocessor_documentprocessor_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DocumentChunkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocumentChunkRequest) ProtoMessage() {}
+
+func (x *DocumentChunkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_documentprocessor_documentprocessor_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocumentChunkRequest.ProtoReflect.Descriptor instead.
+func (*DocumentChunkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_documentprocessor_documentprocessor_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DocumentChunkRequest) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+func (x *DocumentChunkRequest) GetSourceLang() string {
+	if x != nil {
+		return x.SourceLang
+	}
+	return ""
+}
+
+func (x *DocumentChunkRequest) GetTargetLang() string {
+	if x != nil {
+		return x.TargetLang
+	}
+	return ""
+}
+
+func (x *DocumentChunkRequest) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *DocumentChunkRequest) GetGlossary() []*GlossaryTerm {
+	if x != nil {
+		return x.Glossary
+	}
+	return nil
+}
+
+func (x *DocumentChunkRequest) GetMemory() []*MemoryMatch {
+	if x != nil {
+		return x.Memory
+	}
+	return nil
+}
+
+type DocumentChunkResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Chunk []byte `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	// Responses without a chunk may be sent ahead of the document to report progress.
+	Percent int32 `protobuf:"varint,2,opt,name=percent,proto3" json:"percent,omitempty"`
+	// Billed usage, sent once along with the last chunk.
+	BilledCharacters int64 `protobuf:"varint,3,opt,name=billedCharacters,proto3" json:"billedCharacters,omitempty"`
+	BilledTokens     int64 `protobuf:"varint,4,opt,name=billedTokens,proto3" json:"billedTokens,omitempty"`
+}
+
+func (x *DocumentChunkResponse) Reset() {
+	*x = DocumentChunkResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_documentprocessor_documentprocessor_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DocumentChunkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocumentChunkResponse) ProtoMessage() {}
+
+func (x *DocumentChunkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_documentprocessor_documentprocessor_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocumentChunkResponse.ProtoReflect.Descriptor instead.
+func (*DocumentChunkResponse) Descriptor() ([]byte, []int) {
+	return file_proto_documentprocessor_documentprocessor_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DocumentChunkResponse) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+func (x *DocumentChunkResponse) GetPercent() int32 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+func (x *DocumentChunkResponse) GetBilledCharacters() int64 {
+	if x != nil {
+		return x.BilledCharacters
+	}
+	return 0
+}
+
+func (x *DocumentChunkResponse) GetBilledTokens() int64 {
+	if x != nil {
+		return x.BilledTokens
+	}
+	return 0
+}
+
+type DocumentProgressResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Percent  int32  `protobuf:"varint,1,opt,name=percent,proto3" json:"percent,omitempty"`
+	Document []byte `protobuf:"bytes,2,opt,name=document,proto3,oneof" json:"document,omitempty"`
+	// Billed usage, sent along with the document.
+	BilledCharacters int64 `protobuf:"varint,3,opt,name=billedCharacters,proto3" json:"billedCharacters,omitempty"`
+	BilledTokens     int64 `protobuf:"varint,4,opt,name=billedTokens,proto3" json:"billedTokens,omitempty"`
+}
+
+func (x *DocumentProgressResponse) Reset() {
+	*x = DocumentProgressResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_documentprocessor_documentprocessor_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DocumentProgressResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocumentProgressResponse) ProtoMessage() {}
+
+func (x *DocumentProgressResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_documentprocessor_documentprocessor_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocumentProgressResponse.ProtoReflect.Descriptor instead.
+func (*DocumentProgressResponse) Descriptor() ([]byte, []int) {
+	return file_proto_documentprocessor_documentprocessor_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DocumentProgressResponse) GetPercent() int32 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+func (x *DocumentProgressResponse) GetDocument() []byte {
+	if x != nil {
+		return x.Document
+	}
+	return nil
+}
+
+func (x *DocumentProgressResponse) GetBilledCharacters() int64 {
+	if x != nil {
+		return x.BilledCharacters
+	}
+	return 0
+}
+
+func (x *DocumentProgressResponse) GetBilledTokens() int64 {
+	if x != nil {
+		return x.BilledTokens
+	}
+	return 0
+}
+
 var File_proto_documentprocessor_documentprocessor_proto protoreflect.FileDescriptor
 
 var file_proto_documentprocessor_documentprocessor_proto_rawDesc = []byte{
@@ -137,30 +548,107 @@ var file_proto_documentprocessor_documentprocessor_proto_rawDesc = []byte{
 	0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x2f, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65,
 	0x6e, 0x74, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x12, 0x11, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x72, 0x6f, 0x63, 0x65,
-	0x73, 0x73, 0x6f, 0x72, 0x22, 0x7f, 0x0a, 0x0f, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d,
-	0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x08, 0x64, 0x6f, 0x63,
-	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x6f, 0x75, 0x72,
-	0x63, 0x65, 0x4c, 0x61, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x6f,
-	0x75, 0x72, 0x63, 0x65, 0x4c, 0x61, 0x6e, 0x67, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x61, 0x72, 0x67,
-	0x65, 0x74, 0x4c, 0x61, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x74, 0x61,
-	0x72, 0x67, 0x65, 0x74, 0x4c, 0x61, 0x6e, 0x67, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x64, 0x6f, 0x63,
-	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x40, 0x0a, 0x10, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
-	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x08, 0x64, 0x6f, 0x63,
-	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x08, 0x64,
-	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x64,
-	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x32, 0x6f, 0x0a, 0x11, 0x44, 0x6f, 0x63, 0x75, 0x6d,
-	0x65, 0x6e, 0x74, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x12, 0x5a, 0x0a, 0x0f,
-	0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12,
-	0x22, 0x2e, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73,
-	0x73, 0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x72,
+	0x73, 0x73, 0x6f, 0x72, 0x22, 0x90, 0x02, 0x0a, 0x0f, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x08, 0x64, 0x6f, 0x63, 0x75,
+	0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x08, 0x64, 0x6f,
+	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x4c, 0x61, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x61, 0x6e, 0x67, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x4c, 0x61, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x4c, 0x61, 0x6e, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x69, 0x6d,
+	0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x69, 0x6d,
+	0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x3b, 0x0a, 0x08, 0x67, 0x6c, 0x6f, 0x73, 0x73, 0x61, 0x72,
+	0x79, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65,
+	0x6e, 0x74, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x2e, 0x47, 0x6c, 0x6f, 0x73,
+	0x73, 0x61, 0x72, 0x79, 0x54, 0x65, 0x72, 0x6d, 0x52, 0x08, 0x67, 0x6c, 0x6f, 0x73, 0x73, 0x61,
+	0x72, 0x79, 0x12, 0x36, 0x0a, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x18, 0x06, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x72, 0x6f,
+	0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x2e, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x64,
+	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x5a, 0x0a, 0x0c, 0x47, 0x6c, 0x6f, 0x73, 0x73,
+	0x61, 0x72, 0x79, 0x54, 0x65, 0x72, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69,
+	0x72, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69,
+	0x72, 0x65, 0x64, 0x22, 0x53, 0x0a, 0x0b, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x22, 0x90, 0x01, 0x0a, 0x10, 0x44, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a,
+	0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48,
+	0x00, 0x52, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x2a,
+	0x0a, 0x10, 0x62, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x43, 0x68, 0x61, 0x72, 0x61, 0x63, 0x74, 0x65,
+	0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x62, 0x69, 0x6c, 0x6c, 0x65, 0x64,
+	0x43, 0x68, 0x61, 0x72, 0x61, 0x63, 0x74, 0x65, 0x72, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x62, 0x69,
+	0x6c, 0x6c, 0x65, 0x64, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0c, 0x62, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x42, 0x0b,
+	0x0a, 0x09, 0x5f, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0xfd, 0x01, 0x0a, 0x14,
+	0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x4c, 0x61, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x61, 0x6e, 0x67, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x4c, 0x61, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x4c, 0x61, 0x6e, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x69,
+	0x6d, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x69,
+	0x6d, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x3b, 0x0a, 0x08, 0x67, 0x6c, 0x6f, 0x73, 0x73, 0x61,
+	0x72, 0x79, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x64, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x2e, 0x47, 0x6c, 0x6f,
+	0x73, 0x73, 0x61, 0x72, 0x79, 0x54, 0x65, 0x72, 0x6d, 0x52, 0x08, 0x67, 0x6c, 0x6f, 0x73, 0x73,
+	0x61, 0x72, 0x79, 0x12, 0x36, 0x0a, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x18, 0x06, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x72,
+	0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x2e, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x22, 0x97, 0x01, 0x0a, 0x15,
+	0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x70,
+	0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x70, 0x65,
+	0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x2a, 0x0a, 0x10, 0x62, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x43,
+	0x68, 0x61, 0x72, 0x61, 0x63, 0x74, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x10, 0x62, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x43, 0x68, 0x61, 0x72, 0x61, 0x63, 0x74, 0x65, 0x72,
+	0x73, 0x12, 0x22, 0x0a, 0x0c, 0x62, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x54, 0x6f, 0x6b, 0x65, 0x6e,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x62, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x22, 0xb2, 0x01, 0x0a, 0x18, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65,
+	0x6e, 0x74, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x07, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x08,
+	0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00,
+	0x52, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a,
+	0x10, 0x62, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x43, 0x68, 0x61, 0x72, 0x61, 0x63, 0x74, 0x65, 0x72,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x62, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x43,
+	0x68, 0x61, 0x72, 0x61, 0x63, 0x74, 0x65, 0x72, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x62, 0x69, 0x6c,
+	0x6c, 0x65, 0x64, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0c, 0x62, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x42, 0x0b, 0x0a,
+	0x09, 0x5f, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x32, 0xd1, 0x02, 0x0a, 0x11, 0x44,
+	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72,
+	0x12, 0x5a, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x44, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x12, 0x22, 0x2e, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x72,
 	0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x3d, 0x5a, 0x3b, 0x64, 0x6f, 0x63, 0x2d,
-	0x74, 0x72, 0x61, 0x6e, 0x73, 0x6c, 0x61, 0x74, 0x65, 0x2d, 0x67, 0x6f, 0x2f, 0x67, 0x65, 0x6e,
-	0x2f, 0x67, 0x6f, 0x2f, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x72, 0x6f, 0x63,
-	0x65, 0x73, 0x73, 0x6f, 0x72, 0x3b, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x72,
-	0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65,
+	0x6e, 0x74, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63, 0x75,
+	0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6e, 0x0a, 0x15,
+	0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x27, 0x2e, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74,
+	0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65,
+	0x6e, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28,
+	0x2e, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73,
+	0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01, 0x12, 0x70, 0x0a, 0x1b,
+	0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x57,
+	0x69, 0x74, 0x68, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x22, 0x2e, 0x64, 0x6f,
+	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x2e,
+	0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x2b, 0x2e, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x72, 0x6f, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x3d,
+	0x5a, 0x3b, 0x64, 0x6f, 0x63, 0x2d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x6c, 0x61, 0x74, 0x65, 0x2d,
+	0x67, 0x6f, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x67, 0x6f, 0x2f, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65,
+	0x6e, 0x74, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x3b, 0x64, 0x6f, 0x63, 0x75,
+	0x6d, 0x65, 0x6e, 0x74, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -175,19 +663,32 @@ func file_proto_documentprocessor_documentprocessor_proto_rawDescGZIP() []byte {
 	return file_proto_documentprocessor_documentprocessor_proto_rawDescData
 }
 
-var file_proto_documentprocessor_documentprocessor_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_documentprocessor_documentprocessor_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_proto_documentprocessor_documentprocessor_proto_goTypes = []interface{}{
-	(*DocumentRequest)(nil),  // 0: documentprocessor.DocumentRequest
-	(*DocumentResponse)(nil), // 1: documentprocessor.DocumentResponse
+	(*DocumentRequest)(nil),          // 0: documentprocessor.DocumentRequest
+	(*GlossaryTerm)(nil),             // 1: documentprocessor.GlossaryTerm
+	(*MemoryMatch)(nil),              // 2: documentprocessor.MemoryMatch
+	(*DocumentResponse)(nil),         // 3: documentprocessor.DocumentResponse
+	(*DocumentChunkRequest)(nil),     // 4: documentprocessor.DocumentChunkRequest
+	(*DocumentChunkResponse)(nil),    // 5: documentprocessor.DocumentChunkResponse
+	(*DocumentProgressResponse)(nil), // 6: documentprocessor.DocumentProgressResponse
 }
 var file_proto_documentprocessor_documentprocessor_proto_depIdxs = []int32{
-	0, // 0: documentprocessor.DocumentProcessor.ProcessDocument:input_type -> documentprocessor.DocumentRequest
-	1, // 1: documentprocessor.DocumentProcessor.ProcessDocument:output_type -> documentprocessor.DocumentResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1, // 0: documentprocessor.DocumentRequest.glossary:type_name -> documentprocessor.GlossaryTerm
+	2, // 1: documentprocessor.DocumentRequest.memory:type_name -> documentprocessor.MemoryMatch
+	1, // 2: documentprocessor.DocumentChunkRequest.glossary:type_name -> documentprocessor.GlossaryTerm
+	2, // 3: documentprocessor.DocumentChunkRequest.memory:type_name -> documentprocessor.MemoryMatch
+	0, // 4: documentprocessor.DocumentProcessor.ProcessDocument:input_type -> documentprocessor.DocumentRequest
+	4, // 5: documentprocessor.DocumentProcessor.ProcessDocumentStream:input_type -> documentprocessor.DocumentChunkRequest
+	0, // 6: documentprocessor.DocumentProcessor.ProcessDocumentWithProgress:input_type -> documentprocessor.DocumentRequest
+	3, // 7: documentprocessor.DocumentProcessor.ProcessDocument:output_type -> documentprocessor.DocumentResponse
+	5, // 8: documentprocessor.DocumentProcessor.ProcessDocumentStream:output_type -> documentprocessor.DocumentChunkResponse
+	6, // 9: documentprocessor.DocumentProcessor.ProcessDocumentWithProgress:output_type -> documentprocessor.DocumentProgressResponse
+	7, // [7:10] is the sub-list for method output_type
+	4, // [4:7] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_proto_documentprocessor_documentprocessor_proto_init() }
@@ -209,6 +710,30 @@ func file_proto_documentprocessor_documentprocessor_proto_init() {
 			}
 		}
 		file_proto_documentprocessor_documentprocessor_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GlossaryTerm); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_documentprocessor_documentprocessor_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MemoryMatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_documentprocessor_documentprocessor_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DocumentResponse); i {
 			case 0:
 				return &v.state
@@ -220,16 +745,53 @@ func file_proto_documentprocessor_documentprocessor_proto_init() {
 				return nil
 			}
 		}
+		file_proto_documentprocessor_documentprocessor_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DocumentChunkRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_documentprocessor_documentprocessor_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DocumentChunkResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_documentprocessor_documentprocessor_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DocumentProgressResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_proto_documentprocessor_documentprocessor_proto_msgTypes[0].OneofWrappers = []interface{}{}
-	file_proto_documentprocessor_documentprocessor_proto_msgTypes[1].OneofWrappers = []interface{}{}
+	file_proto_documentprocessor_documentprocessor_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	file_proto_documentprocessor_documentprocessor_proto_msgTypes[6].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_documentprocessor_documentprocessor_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -23,6 +23,12 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type DocumentProcessorClient interface {
 	ProcessDocument(ctx context.Context, in *DocumentRequest, opts ...grpc.CallOption) (*DocumentResponse, error)
+	// ProcessDocumentStream translates documents too large for a single message.
+	// Languages, the MIME type and the glossary are only read from the first request chunk.
+	ProcessDocumentStream(ctx context.Context, opts ...grpc.CallOption) (DocumentProcessor_ProcessDocumentStreamClient, error)
+	// ProcessDocumentWithProgress translates like ProcessDocument while reporting progress.
+	// Every response but the last only carries percent, the last carries the document.
+	ProcessDocumentWithProgress(ctx context.Context, in *DocumentRequest, opts ...grpc.CallOption) (DocumentProcessor_ProcessDocumentWithProgressClient, error)
 }
 
 type documentProcessorClient struct {
@@ -42,11 +48,80 @@ func (c *documentProcessorClient) ProcessDocument(ctx context.Context, in *Docum
 	return out, nil
 }
 
+func (c *documentProcessorClient) ProcessDocumentStream(ctx context.Context, opts ...grpc.CallOption) (DocumentProcessor_ProcessDocumentStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DocumentProcessor_ServiceDesc.Streams[0], "/documentprocessor.DocumentProcessor/ProcessDocumentStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &documentProcessorProcessDocumentStreamClient{stream}
+	return x, nil
+}
+
+type DocumentProcessor_ProcessDocumentStreamClient interface {
+	Send(*DocumentChunkRequest) error
+	Recv() (*DocumentChunkResponse, error)
+	grpc.ClientStream
+}
+
+type documentProcessorProcessDocumentStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *documentProcessorProcessDocumentStreamClient) Send(m *DocumentChunkRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *documentProcessorProcessDocumentStreamClient) Recv() (*DocumentChunkResponse, error) {
+	m := new(DocumentChunkResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *documentProcessorClient) ProcessDocumentWithProgress(ctx context.Context, in *DocumentRequest, opts ...grpc.CallOption) (DocumentProcessor_ProcessDocumentWithProgressClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DocumentProcessor_ServiceDesc.Streams[1], "/documentprocessor.DocumentProcessor/ProcessDocumentWithProgress", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &documentProcessorProcessDocumentWithProgressClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DocumentProcessor_ProcessDocumentWithProgressClient interface {
+	Recv() (*DocumentProgressResponse, error)
+	grpc.ClientStream
+}
+
+type documentProcessorProcessDocumentWithProgressClient struct {
+	grpc.ClientStream
+}
+
+func (x *documentProcessorProcessDocumentWithProgressClient) Recv() (*DocumentProgressResponse, error) {
+	m := new(DocumentProgressResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // DocumentProcessorServer is the server API for DocumentProcessor service.
 // All implementations must embed UnimplementedDocumentProcessorServer
 // for forward compatibility
 type DocumentProcessorServer interface {
 	ProcessDocument(context.Context, *DocumentRequest) (*DocumentResponse, error)
+	// ProcessDocumentStream translates documents too large for a single message.
+	// Languages, the MIME type and the glossary are only read from the first request chunk.
+	ProcessDocumentStream(DocumentProcessor_ProcessDocumentStreamServer) error
+	// ProcessDocumentWithProgress translates like ProcessDocument while reporting progress.
+	// Every response but the last only carries percent, the last carries the document.
+	ProcessDocumentWithProgress(*DocumentRequest, DocumentProcessor_ProcessDocumentWithProgressServer) error
 	mustEmbedUnimplementedDocumentProcessorServer()
 }
 
@@ -57,6 +132,12 @@ type UnimplementedDocumentProcessorServer struct {
 func (UnimplementedDocumentProcessorServer) ProcessDocument(context.Context, *DocumentRequest) (*DocumentResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ProcessDocument not implemented")
 }
+func (UnimplementedDocumentProcessorServer) ProcessDocumentStream(DocumentProcessor_ProcessDocumentStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ProcessDocumentStream not implemented")
+}
+func (UnimplementedDocumentProcessorServer) ProcessDocumentWithProgress(*DocumentRequest, DocumentProcessor_ProcessDocumentWithProgressServer) error {
+	return status.Errorf(codes.Unimplemented, "method ProcessDocumentWithProgress not implemented")
+}
 func (UnimplementedDocumentProcessorServer) mustEmbedUnimplementedDocumentProcessorServer() {}
 
 // UnsafeDocumentProcessorServer may be embedded to opt out of forward compatibility for this service.
@@ -88,6 +169,53 @@ func _DocumentProcessor_ProcessDocument_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DocumentProcessor_ProcessDocumentStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DocumentProcessorServer).ProcessDocumentStream(&documentProcessorProcessDocumentStreamServer{stream})
+}
+
+type DocumentProcessor_ProcessDocumentStreamServer interface {
+	Send(*DocumentChunkResponse) error
+	Recv() (*DocumentChunkRequest, error)
+	grpc.ServerStream
+}
+
+type documentProcessorProcessDocumentStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *documentProcessorProcessDocumentStreamServer) Send(m *DocumentChunkResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *documentProcessorProcessDocumentStreamServer) Recv() (*DocumentChunkRequest, error) {
+	m := new(DocumentChunkRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _DocumentProcessor_ProcessDocumentWithProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DocumentRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DocumentProcessorServer).ProcessDocumentWithProgress(m, &documentProcessorProcessDocumentWithProgressServer{stream})
+}
+
+type DocumentProcessor_ProcessDocumentWithProgressServer interface {
+	Send(*DocumentProgressResponse) error
+	grpc.ServerStream
+}
+
+type documentProcessorProcessDocumentWithProgressServer struct {
+	grpc.ServerStream
+}
+
+func (x *documentProcessorProcessDocumentWithProgressServer) Send(m *DocumentProgressResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // DocumentProcessor_ServiceDesc is the grpc.ServiceDesc for DocumentProcessor service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -100,6 +228,18 @@ var DocumentProcessor_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _DocumentProcessor_ProcessDocument_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ProcessDocumentStream",
+			Handler:       _DocumentProcessor_ProcessDocumentStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ProcessDocumentWithProgress",
+			Handler:       _DocumentProcessor_ProcessDocumentWithProgress_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "proto/documentprocessor/documentprocessor.proto",
 }
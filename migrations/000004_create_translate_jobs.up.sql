@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS translate_jobs (
+        id SERIAL PRIMARY KEY,
+        task JSONB NOT NULL,
+        leased_until TIMESTAMP,
+        created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS translate_jobs_leased_until_idx ON translate_jobs (leased_until);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS dead_letters (
+        id SERIAL PRIMARY KEY,
+        task JSONB NOT NULL,
+        stage VARCHAR(255) NOT NULL,
+        error TEXT NOT NULL,
+        attempts INT NOT NULL,
+        created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        created_by VARCHAR(255) NOT NULL
+);
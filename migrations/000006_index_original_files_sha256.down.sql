@@ -0,0 +1 @@
+DROP INDEX IF EXISTS original_files_sha256_idx;
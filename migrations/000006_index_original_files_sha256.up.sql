@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS original_files_sha256_idx ON original_files (sha256, source_language);
@@ -0,0 +1,4 @@
+ALTER TABLE translated_files DROP COLUMN IF EXISTS billed_tokens;
+ALTER TABLE translated_files DROP COLUMN IF EXISTS billed_characters;
+ALTER TABLE translated_files DROP COLUMN IF EXISTS queue_time;
+ALTER TABLE translated_files ALTER COLUMN cost TYPE DECIMAL(10, 2);
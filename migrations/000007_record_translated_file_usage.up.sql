@@ -0,0 +1,5 @@
+-- time_taken and queue_time are in milliseconds
+ALTER TABLE translated_files ALTER COLUMN cost TYPE DECIMAL(14, 6);
+ALTER TABLE translated_files ADD COLUMN IF NOT EXISTS queue_time INT NOT NULL DEFAULT 0;
+ALTER TABLE translated_files ADD COLUMN IF NOT EXISTS billed_characters BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE translated_files ADD COLUMN IF NOT EXISTS billed_tokens BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+ALTER TABLE translated_files DROP COLUMN IF EXISTS term_flags;
+ALTER TABLE translated_files DROP COLUMN IF EXISTS glossary_id;
+DROP TABLE IF EXISTS glossaries;
+ALTER TABLE users DROP COLUMN IF EXISTS team;
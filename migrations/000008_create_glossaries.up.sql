@@ -0,0 +1,20 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS team VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS glossaries (
+        id SERIAL PRIMARY KEY,
+        name VARCHAR(255) NOT NULL,
+        source_language VARCHAR(255) NOT NULL,
+        target_language VARCHAR(255) NOT NULL,
+        team VARCHAR(255) NOT NULL DEFAULT '',
+        terms JSONB NOT NULL DEFAULT '[]',
+        created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        created_by VARCHAR(255) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS glossaries_created_by_idx ON glossaries (created_by);
+CREATE INDEX IF NOT EXISTS glossaries_team_idx ON glossaries (team) WHERE team <> '';
+
+-- term_flags are the paragraphs whose translation misses a required term
+ALTER TABLE translated_files ADD COLUMN IF NOT EXISTS glossary_id INT REFERENCES glossaries(id) ON DELETE SET NULL;
+ALTER TABLE translated_files ADD COLUMN IF NOT EXISTS term_flags JSONB NOT NULL DEFAULT '[]';
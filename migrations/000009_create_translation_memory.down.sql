@@ -0,0 +1,4 @@
+ALTER TABLE translated_files DROP COLUMN IF EXISTS memory_misses;
+ALTER TABLE translated_files DROP COLUMN IF EXISTS memory_fuzzy_hits;
+ALTER TABLE translated_files DROP COLUMN IF EXISTS memory_hits;
+DROP TABLE IF EXISTS translation_units;
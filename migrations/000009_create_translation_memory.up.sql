@@ -0,0 +1,26 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE IF NOT EXISTS translation_units (
+        id SERIAL PRIMARY KEY,
+        source_language VARCHAR(255) NOT NULL,
+        target_language VARCHAR(255) NOT NULL,
+        source_text TEXT NOT NULL,
+        target_text TEXT NOT NULL,
+        created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        created_by VARCHAR(255) NOT NULL
+);
+
+-- A segment has one translation per languages. Its text is hashed, as a long
+-- one would overflow a btree index entry
+CREATE UNIQUE INDEX IF NOT EXISTS translation_units_source_text_md5_idx ON translation_units (source_language, target_language, md5(source_text));
+
+-- Fuzzy matches are found by trigram similarity
+CREATE INDEX IF NOT EXISTS translation_units_source_text_trgm_idx ON translation_units USING GIN (source_text gin_trgm_ops);
+
+-- memory_hits are segments translated from an exact match, memory_fuzzy_hits
+-- segments sent with a close match and memory_misses segments sent without any.
+-- They're null for files whose segments weren't looked up
+ALTER TABLE translated_files ADD COLUMN IF NOT EXISTS memory_hits INT;
+ALTER TABLE translated_files ADD COLUMN IF NOT EXISTS memory_fuzzy_hits INT;
+ALTER TABLE translated_files ADD COLUMN IF NOT EXISTS memory_misses INT;
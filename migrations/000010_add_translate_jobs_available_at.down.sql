@@ -0,0 +1 @@
+ALTER TABLE translate_jobs DROP COLUMN IF EXISTS available_at;
@@ -0,0 +1 @@
+ALTER TABLE translate_jobs ADD COLUMN IF NOT EXISTS available_at TIMESTAMP;
@@ -60,3 +60,43 @@ func (mr *MockDocumentProcessorClientMockRecorder) ProcessDocument(arg0, arg1 an
 	varargs := append([]any{arg0, arg1}, arg2...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessDocument", reflect.TypeOf((*MockDocumentProcessorClient)(nil).ProcessDocument), varargs...)
 }
+
+// ProcessDocumentStream mocks base method.
+func (m *MockDocumentProcessorClient) ProcessDocumentStream(arg0 context.Context, arg1 ...grpc.CallOption) (documentprocessor.DocumentProcessor_ProcessDocumentStreamClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ProcessDocumentStream", varargs...)
+	ret0, _ := ret[0].(documentprocessor.DocumentProcessor_ProcessDocumentStreamClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessDocumentStream indicates an expected call of ProcessDocumentStream.
+func (mr *MockDocumentProcessorClientMockRecorder) ProcessDocumentStream(arg0 any, arg1 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessDocumentStream", reflect.TypeOf((*MockDocumentProcessorClient)(nil).ProcessDocumentStream), varargs...)
+}
+
+// ProcessDocumentWithProgress mocks base method.
+func (m *MockDocumentProcessorClient) ProcessDocumentWithProgress(arg0 context.Context, arg1 *documentprocessor.DocumentRequest, arg2 ...grpc.CallOption) (documentprocessor.DocumentProcessor_ProcessDocumentWithProgressClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ProcessDocumentWithProgress", varargs...)
+	ret0, _ := ret[0].(documentprocessor.DocumentProcessor_ProcessDocumentWithProgressClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessDocumentWithProgress indicates an expected call of ProcessDocumentWithProgress.
+func (mr *MockDocumentProcessorClientMockRecorder) ProcessDocumentWithProgress(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessDocumentWithProgress", reflect.TypeOf((*MockDocumentProcessorClient)(nil).ProcessDocumentWithProgress), varargs...)
+}
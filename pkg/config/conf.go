@@ -7,10 +7,23 @@ import (
 )
 
 const (
+	ENV_MODE            = "MODE"
 	ENV_ADDR            = "ADDR"
 	ENV_TRANSLATOR      = "TRANSLATOR"
 	ENV_TRANSLATE_QUEUE = "TRANSLATE_QUEUE"
 	ENV_FILE_TRACKER    = "FILE_TRACKER"
+	ENV_FILE_STORE      = "FILE_STORE"
+	ENV_TRANSLATE_CACHE = "TRANSLATE_CACHE"
+
+	ENV_TRANSLATE_QUEUE_LEASE_SECONDS = "TRANSLATE_QUEUE_LEASE_SECONDS"
+	ENV_TRANSLATE_WORKERS             = "TRANSLATE_WORKERS"
+	ENV_TRANSLATE_MAX_ATTEMPTS        = "TRANSLATE_MAX_ATTEMPTS"
+	ENV_TRANSLATE_RETRY_BASE_SECONDS  = "TRANSLATE_RETRY_BASE_SECONDS"
+	ENV_TRANSLATE_RETRY_MAX_SECONDS   = "TRANSLATE_RETRY_MAX_SECONDS"
+	ENV_TRANSLATE_REUSE_SCOPE         = "TRANSLATE_REUSE_SCOPE"
+	ENV_TRANSLATE_STALE_SECONDS       = "TRANSLATE_STALE_SECONDS"
+	ENV_TRANSLATE_FORMATS             = "TRANSLATE_FORMATS"
+	ENV_SHUTDOWN_TIMEOUT_SECONDS      = "SHUTDOWN_TIMEOUT_SECONDS"
 
 	ENV_DB_USERNAME = "DB_USERNAME"
 	ENV_DB_PASSWORD = "DB_PASSWORD"
@@ -26,12 +39,46 @@ const (
 	ENV_AWS_SQS_QUEUE_URL = "AWS_SQS_QUEUE_URL"
 	ENV_AWS_SQS_GROUP_ID  = "AWS_SQS_GROUP_ID"
 
-	ENV_TRANSLATE_GRPC_SERVER = "TRANSLATE_GRPC_SERVER"
+	ENV_TRANSLATE_GRPC_SERVER           = "TRANSLATE_GRPC_SERVER"
+	ENV_TRANSLATE_GRPC_STREAM_THRESHOLD = "TRANSLATE_GRPC_STREAM_THRESHOLD"
+	ENV_TRANSLATE_GRPC_CHUNK_SIZE       = "TRANSLATE_GRPC_CHUNK_SIZE"
+	ENV_TRANSLATE_GRPC_PROGRESS         = "TRANSLATE_GRPC_PROGRESS"
+	ENV_TRANSLATE_GRPC_TIMEOUT_SECONDS  = "TRANSLATE_GRPC_TIMEOUT_SECONDS"
+
+	ENV_TRANSLATE_BREAKER_FAILURES     = "TRANSLATE_BREAKER_FAILURES"
+	ENV_TRANSLATE_BREAKER_OPEN_SECONDS = "TRANSLATE_BREAKER_OPEN_SECONDS"
+
+	ENV_TRANSLATE_CACHE_TTL_SECONDS    = "TRANSLATE_CACHE_TTL_SECONDS"
+	ENV_TRANSLATE_CACHE_MAX_BYTES      = "TRANSLATE_CACHE_MAX_BYTES"
+	ENV_TRANSLATE_CACHE_MAX_ITEM_BYTES = "TRANSLATE_CACHE_MAX_ITEM_BYTES"
+
+	ENV_TRANSLATE_PRICES            = "TRANSLATE_PRICES"
+	ENV_TRANSLATE_CURRENCY          = "TRANSLATE_CURRENCY"
+	ENV_TRANSLATE_TOKENS_PER_SECOND = "TRANSLATE_TOKENS_PER_SECOND"
+
+	ENV_TRANSLATE_SUBTITLE_LINE_LENGTHS = "TRANSLATE_SUBTITLE_LINE_LENGTHS"
+
+	ENV_TRANSLATE_PROTECT_PATTERN      = "TRANSLATE_PROTECT_PATTERN"
+	ENV_TRANSLATE_PRODUCT_CODE_PATTERN = "TRANSLATE_PRODUCT_CODE_PATTERN"
+
+	ENV_TRANSLATE_MEMORY_FUZZY_THRESHOLD = "TRANSLATE_MEMORY_FUZZY_THRESHOLD"
+
+	ENV_TRANSLATE_MAX_PART_BYTES = "TRANSLATE_MAX_PART_BYTES"
+
+	ENV_FILESYSTEM_ROOT               = "FILESYSTEM_ROOT"
+	ENV_FILESYSTEM_SIGNING_SECRET     = "FILESYSTEM_SIGNING_SECRET"
+	ENV_FILESYSTEM_BASE_URL           = "FILESYSTEM_BASE_URL"
+	ENV_FILESYSTEM_URL_EXPIRY_SECONDS = "FILESYSTEM_URL_EXPIRY_SECONDS"
 
 	ENV_REDIS_ADDRS          = "REDIS_ADDRS"
 	ENV_REDIS_PASSWORD       = "REDIS_PASSWORD"
 	ENV_REDIS_EXPIRY_SECONDS = "REDIS_EXPIRY_SECONDS"
 
+	ENV_REDIS_STREAM                    = "REDIS_STREAM"
+	ENV_REDIS_STREAM_GROUP              = "REDIS_STREAM_GROUP"
+	ENV_REDIS_STREAM_CONSUMER           = "REDIS_STREAM_CONSUMER"
+	ENV_REDIS_STREAM_CLAIM_IDLE_SECONDS = "REDIS_STREAM_CLAIM_IDLE_SECONDS"
+
 	ENV_AUTH_TOKEN_ENDPOINT             = "AUTH_TOKEN_ENDPOINT"
 	ENV_AUTH_ENDPOINT                   = "AUTH_ENDPOINT"
 	ENV_AUTH_INTROSPECT_ENDPOINT        = "AUTH_INTROSPECT_ENDPOINT"
@@ -45,35 +92,61 @@ const (
 )
 
 type Config struct {
-	App       *AppConfig
-	Aws       *AwsConfig
-	Translate *TranslateConfig
-	Redis     *RedisConfig
-	Auth      *AuthConfig
-	Db        *DbConfig
-	Swagger   *SwaggerConfig
+	App        *AppConfig
+	Aws        *AwsConfig
+	Filesystem *FilesystemConfig
+	Translate  *TranslateConfig
+	Redis      *RedisConfig
+	Auth       *AuthConfig
+	Db         *DbConfig
+	Swagger    *SwaggerConfig
 }
 
 func NewConfig() *Config {
-	return &Config{
-		App:       NewAppConfig(),
-		Aws:       NewAwsConfig(),
-		Translate: NewTranslateConfig(),
-		Redis:     NewRedisConfig(),
-		Auth:      NewAuthConfig(),
-		Db:        NewDbconfig(),
-		Swagger:   NewSwaggerConfig(),
+	conf := &Config{
+		App:        NewAppConfig(),
+		Aws:        NewAwsConfig(),
+		Filesystem: NewFilesystemConfig(),
+		Translate:  NewTranslateConfig(),
+		Redis:      NewRedisConfig(),
+		Db:         NewDbconfig(),
+		Swagger:    NewSwaggerConfig(),
+	}
+
+	// Workers serve no authenticated routes, Auth is nil for them
+	if conf.App.Mode != "worker" {
+		conf.Auth = NewAuthConfig()
 	}
+
+	return conf
 }
 
 type AppConfig struct {
-	Addr           string
-	Translator     string
-	FileTracker    string
-	TranslateQueue string
+	Mode                       string
+	Addr                       string
+	Translator                 string
+	FileTracker                string
+	FileStore                  string
+	TranslateCache             string
+	TranslateQueue             string
+	TranslateQueueLeaseSeconds int
+	TranslateWorkers           int
+	TranslateMaxAttempts       int
+	TranslateRetryBaseSeconds  int
+	TranslateRetryMaxSeconds   int
+	TranslateReuseScope        string
+	TranslateStaleSeconds      int
+	TranslateFormats           []string
+	ShutdownTimeoutSeconds     int
 }
 
 func NewAppConfig() *AppConfig {
+	// Which roles the process runs: api, worker or all
+	mode := os.Getenv(ENV_MODE)
+	if mode == "" {
+		mode = "all"
+	}
+
 	addr := os.Getenv(ENV_ADDR)
 	if addr == "" {
 		addr = ":8080"
@@ -94,11 +167,83 @@ func NewAppConfig() *AppConfig {
 		fileTracker = "redis"
 	}
 
+	fileStore := os.Getenv(ENV_FILE_STORE)
+	if fileStore == "" {
+		fileStore = "s3"
+	}
+
+	translateCache := os.Getenv(ENV_TRANSLATE_CACHE)
+	if translateCache == "" {
+		translateCache = "none"
+	}
+
+	leaseSeconds, err := strconv.Atoi(os.Getenv(ENV_TRANSLATE_QUEUE_LEASE_SECONDS))
+	if err != nil {
+		leaseSeconds = 900
+	}
+
+	translateWorkers, err := strconv.Atoi(os.Getenv(ENV_TRANSLATE_WORKERS))
+	if err != nil || translateWorkers < 1 {
+		translateWorkers = 4
+	}
+
+	maxAttempts, err := strconv.Atoi(os.Getenv(ENV_TRANSLATE_MAX_ATTEMPTS))
+	if err != nil || maxAttempts < 1 {
+		maxAttempts = 5
+	}
+
+	retryBase, err := strconv.Atoi(os.Getenv(ENV_TRANSLATE_RETRY_BASE_SECONDS))
+	if err != nil {
+		retryBase = 2
+	}
+
+	retryMax, err := strconv.Atoi(os.Getenv(ENV_TRANSLATE_RETRY_MAX_SECONDS))
+	if err != nil {
+		retryMax = 60
+	}
+
+	// Whose earlier translations of identical files are reused: none, user or all
+	reuseScope := os.Getenv(ENV_TRANSLATE_REUSE_SCOPE)
+	if reuseScope == "" {
+		reuseScope = "user"
+	}
+
+	// How long an in-progress status may go without a heartbeat before it's
+	// interrupted. Workers refresh theirs every third of it, so it only has to
+	// outlast a missed heartbeat or two, not a whole translation
+	staleSeconds, err := strconv.Atoi(os.Getenv(ENV_TRANSLATE_STALE_SECONDS))
+	if err != nil || staleSeconds < 1 {
+		staleSeconds = 3600
+	}
+
+	// Formats accepted for upload, only those the document processor can handle
+	translateFormats := os.Getenv(ENV_TRANSLATE_FORMATS)
+	if translateFormats == "" {
+		translateFormats = "docx"
+	}
+
+	shutdownTimeout, err := strconv.Atoi(os.Getenv(ENV_SHUTDOWN_TIMEOUT_SECONDS))
+	if err != nil {
+		shutdownTimeout = 30
+	}
+
 	return &AppConfig{
-		Addr:           addr,
-		Translator:     translator,
-		TranslateQueue: translateQueue,
-		FileTracker:    fileTracker,
+		Mode:                       mode,
+		Addr:                       addr,
+		Translator:                 translator,
+		TranslateQueue:             translateQueue,
+		FileTracker:                fileTracker,
+		FileStore:                  fileStore,
+		TranslateCache:             translateCache,
+		TranslateQueueLeaseSeconds: leaseSeconds,
+		TranslateWorkers:           translateWorkers,
+		TranslateMaxAttempts:       maxAttempts,
+		TranslateRetryBaseSeconds:  retryBase,
+		TranslateRetryMaxSeconds:   retryMax,
+		TranslateReuseScope:        reuseScope,
+		TranslateStaleSeconds:      staleSeconds,
+		TranslateFormats:           strings.Split(translateFormats, ","),
+		ShutdownTimeoutSeconds:     shutdownTimeout,
 	}
 }
 
@@ -120,13 +265,185 @@ func NewAwsConfig() *AwsConfig {
 	}
 }
 
+type FilesystemConfig struct {
+	Root             string
+	SigningSecret    string
+	BaseUrl          string
+	UrlExpirySeconds int
+}
+
+func NewFilesystemConfig() *FilesystemConfig {
+	root := os.Getenv(ENV_FILESYSTEM_ROOT)
+	if root == "" {
+		root = "data"
+	}
+
+	baseUrl := os.Getenv(ENV_FILESYSTEM_BASE_URL)
+	if baseUrl == "" {
+		baseUrl = "http://localhost:8080/files"
+	}
+
+	// Same lifetime as the S3 presigned urls
+	urlExpiry, err := strconv.Atoi(os.Getenv(ENV_FILESYSTEM_URL_EXPIRY_SECONDS))
+	if err != nil {
+		urlExpiry = 900
+	}
+
+	return &FilesystemConfig{
+		Root:             root,
+		SigningSecret:    os.Getenv(ENV_FILESYSTEM_SIGNING_SECRET),
+		BaseUrl:          baseUrl,
+		UrlExpirySeconds: urlExpiry,
+	}
+}
+
 type TranslateConfig struct {
-	GrpcServer string
+	GrpcServer          string
+	GrpcStreamThreshold int
+	GrpcChunkSize       int
+	GrpcProgress        bool
+	GrpcTimeoutSeconds  int
+	BreakerFailures     int
+	BreakerOpenSeconds  int
+	CacheTtlSeconds     int
+	CacheMaxBytes       int
+	CacheMaxItemBytes   int
+	Prices              map[string]float64
+	Currency            string
+	TokensPerSecond     int
+	SubtitleLineLengths map[string]int
+	ProtectPattern      string
+	// ProductCodePattern replaces the default product code pattern when set,
+	// none turns it off
+	ProductCodePattern string
+	// MemoryFuzzyThreshold is the least similarity, from 0 to 1, of the
+	// translation memory matches sent along with segments, above 1 for none
+	MemoryFuzzyThreshold float64
+	// MaxPartBytes is the most a part of an office document may decompress to
+	MaxPartBytes int
 }
 
 func NewTranslateConfig() *TranslateConfig {
+	// Stay below gRPC's default 4 MB message limit
+	streamThreshold, err := strconv.Atoi(os.Getenv(ENV_TRANSLATE_GRPC_STREAM_THRESHOLD))
+	if err != nil {
+		streamThreshold = 3 << 20
+	}
+
+	chunkSize, err := strconv.Atoi(os.Getenv(ENV_TRANSLATE_GRPC_CHUNK_SIZE))
+	if err != nil || chunkSize <= 0 {
+		chunkSize = 1 << 20
+	}
+
+	// Older document processors don't implement ProcessDocumentWithProgress
+	progress, err := strconv.ParseBool(os.Getenv(ENV_TRANSLATE_GRPC_PROGRESS))
+	if err != nil {
+		progress = false
+	}
+
+	grpcTimeout, err := strconv.Atoi(os.Getenv(ENV_TRANSLATE_GRPC_TIMEOUT_SECONDS))
+	if err != nil {
+		grpcTimeout = 1800
+	}
+
+	breakerFailures, err := strconv.Atoi(os.Getenv(ENV_TRANSLATE_BREAKER_FAILURES))
+	if err != nil || breakerFailures < 1 {
+		breakerFailures = 5
+	}
+
+	breakerOpen, err := strconv.Atoi(os.Getenv(ENV_TRANSLATE_BREAKER_OPEN_SECONDS))
+	if err != nil {
+		breakerOpen = 30
+	}
+
+	cacheTtl, err := strconv.Atoi(os.Getenv(ENV_TRANSLATE_CACHE_TTL_SECONDS))
+	if err != nil {
+		cacheTtl = 7 * 24 * 60 * 60
+	}
+
+	// Only bounds the in-memory cache, redis and the file store manage their own space
+	cacheMaxBytes, err := strconv.Atoi(os.Getenv(ENV_TRANSLATE_CACHE_MAX_BYTES))
+	if err != nil {
+		cacheMaxBytes = 256 << 20
+	}
+
+	cacheMaxItemBytes, err := strconv.Atoi(os.Getenv(ENV_TRANSLATE_CACHE_MAX_ITEM_BYTES))
+	if err != nil {
+		cacheMaxItemBytes = 16 << 20
+	}
+
+	// Price per token by language pair, e.g. en:vi=0.00002,*:*=0.00003
+	prices := make(map[string]float64)
+	for _, entry := range strings.Split(os.Getenv(ENV_TRANSLATE_PRICES), ",") {
+		pair, price, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+
+		p, err := strconv.ParseFloat(price, 64)
+		if err != nil {
+			continue
+		}
+		prices[pair] = p
+	}
+
+	currency := os.Getenv(ENV_TRANSLATE_CURRENCY)
+	if currency == "" {
+		currency = "USD"
+	}
+
+	tokensPerSecond, err := strconv.Atoi(os.Getenv(ENV_TRANSLATE_TOKENS_PER_SECOND))
+	if err != nil || tokensPerSecond < 1 {
+		tokensPerSecond = 100
+	}
+
+	// Longest subtitle line by target language, e.g. ja=16,*=42, unset to keep
+	// the translator's line breaks
+	lineLengths := make(map[string]int)
+	for _, entry := range strings.Split(os.Getenv(ENV_TRANSLATE_SUBTITLE_LINE_LENGTHS), ",") {
+		lang, length, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.Atoi(length)
+		if err != nil {
+			continue
+		}
+		lineLengths[lang] = n
+	}
+
+	memoryFuzzyThreshold, err := strconv.ParseFloat(os.Getenv(ENV_TRANSLATE_MEMORY_FUZZY_THRESHOLD), 64)
+	if err != nil || memoryFuzzyThreshold < 0 {
+		memoryFuzzyThreshold = 0.75
+	}
+
+	// Uploads are decompressed on the request path, so a zip bomb is refused
+	// before it's read whole
+	maxPartBytes, err := strconv.Atoi(os.Getenv(ENV_TRANSLATE_MAX_PART_BYTES))
+	if err != nil || maxPartBytes < 1 {
+		maxPartBytes = 64 << 20
+	}
+
 	return &TranslateConfig{
-		GrpcServer: os.Getenv(ENV_TRANSLATE_GRPC_SERVER),
+		GrpcServer:           os.Getenv(ENV_TRANSLATE_GRPC_SERVER),
+		GrpcStreamThreshold:  streamThreshold,
+		GrpcChunkSize:        chunkSize,
+		GrpcProgress:         progress,
+		GrpcTimeoutSeconds:   grpcTimeout,
+		BreakerFailures:      breakerFailures,
+		BreakerOpenSeconds:   breakerOpen,
+		CacheTtlSeconds:      cacheTtl,
+		CacheMaxBytes:        cacheMaxBytes,
+		CacheMaxItemBytes:    cacheMaxItemBytes,
+		Prices:               prices,
+		Currency:             currency,
+		TokensPerSecond:      tokensPerSecond,
+		SubtitleLineLengths:  lineLengths,
+		ProtectPattern:       os.Getenv(ENV_TRANSLATE_PROTECT_PATTERN),
+		ProductCodePattern:   os.Getenv(ENV_TRANSLATE_PRODUCT_CODE_PATTERN),
+		MemoryFuzzyThreshold: memoryFuzzyThreshold,
+		MaxPartBytes:         maxPartBytes,
 	}
 }
 
@@ -155,9 +472,13 @@ func NewAuthConfig() *AuthConfig {
 }
 
 type RedisConfig struct {
-	Addrs         []string
-	Password      string
-	ExpirySeconds int
+	Addrs                  []string
+	Password               string
+	ExpirySeconds          int
+	Stream                 string
+	StreamGroup            string
+	StreamConsumer         string
+	StreamClaimIdleSeconds int
 }
 
 func NewRedisConfig() *RedisConfig {
@@ -166,10 +487,35 @@ func NewRedisConfig() *RedisConfig {
 		expiry = 0
 	}
 
+	stream := os.Getenv(ENV_REDIS_STREAM)
+	if stream == "" {
+		stream = "translate-queue"
+	}
+
+	streamGroup := os.Getenv(ENV_REDIS_STREAM_GROUP)
+	if streamGroup == "" {
+		streamGroup = "translate-workers"
+	}
+
+	// Consumers must be unique within the group, the hostname is unique per container
+	streamConsumer := os.Getenv(ENV_REDIS_STREAM_CONSUMER)
+	if streamConsumer == "" {
+		streamConsumer, _ = os.Hostname()
+	}
+
+	claimIdle, err := strconv.Atoi(os.Getenv(ENV_REDIS_STREAM_CLAIM_IDLE_SECONDS))
+	if err != nil {
+		claimIdle = 900
+	}
+
 	return &RedisConfig{
-		Addrs:         strings.Split(strings.TrimSpace(os.Getenv(ENV_REDIS_ADDRS)), ","),
-		Password:      os.Getenv(ENV_REDIS_PASSWORD),
-		ExpirySeconds: expiry,
+		Addrs:                  strings.Split(strings.TrimSpace(os.Getenv(ENV_REDIS_ADDRS)), ","),
+		Password:               os.Getenv(ENV_REDIS_PASSWORD),
+		ExpirySeconds:          expiry,
+		Stream:                 stream,
+		StreamGroup:            streamGroup,
+		StreamConsumer:         streamConsumer,
+		StreamClaimIdleSeconds: claimIdle,
 	}
 }
 
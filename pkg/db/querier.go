@@ -1,9 +1,15 @@
 package db
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+)
 
 type Querier interface {
 	Query(cmd string, args ...any) (*sql.Rows, error)
 	QueryRow(cmd string, args ...any) *sql.Row
 	Exec(cmd string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, cmd string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, cmd string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, cmd string, args ...any) (sql.Result, error)
 }
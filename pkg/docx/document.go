@@ -0,0 +1,196 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"doc-translate-go/pkg/ooxml"
+	"doc-translate-go/pkg/wordcount"
+	"errors"
+	"path"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrNotDocx      = errors.New("not a docx document")
+	ErrSegmentCount = errors.New("segment count doesn't match the document")
+	ErrOverlapEdits = errors.New("overlapping edits")
+)
+
+const mainDocumentPart = "word/document.xml"
+
+// Document is an opened DOCX whose text can be replaced segment by segment.
+// Everything but the text of its runs is written back untouched.
+type Document struct {
+	zip        *zip.Reader
+	parts      map[string][]byte
+	paragraphs []*paragraph
+}
+
+// Open reads the DOCX in b and extracts a segment per paragraph holding text,
+// from the main document, headers, footers, footnotes, endnotes and comments.
+func Open(b []byte) (*Document, error) {
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Document{zip: r, parts: make(map[string][]byte)}
+
+	var names []string
+	for _, f := range r.File {
+		if isTextPart(f.Name) {
+			names = append(names, f.Name)
+		}
+	}
+
+	if _, err := r.Open(mainDocumentPart); err != nil {
+		return nil, ErrNotDocx
+	}
+
+	// The main document comes first, the other parts in name order
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == mainDocumentPart) != (names[j] == mainDocumentPart) {
+			return names[i] == mainDocumentPart
+		}
+		return names[i] < names[j]
+	})
+
+	for _, name := range names {
+		part, err := ooxml.ReadFile(r, name)
+		if err != nil {
+			return nil, err
+		}
+
+		paragraphs, err := parsePart(name, part)
+		if err != nil {
+			return nil, err
+		}
+
+		d.parts[name] = part
+		d.paragraphs = append(d.paragraphs, paragraphs...)
+	}
+
+	return d, nil
+}
+
+// Segments returns the document's segments in document order. Changing them
+// doesn't change the document, pass them to Assemble instead.
+func (d *Document) Segments() []Segment {
+	segments := make([]Segment, len(d.paragraphs))
+
+	for i, p := range d.paragraphs {
+		runs := make([]Run, len(p.segment.Runs))
+		copy(runs, p.segment.Runs)
+		segments[i] = Segment{Part: p.segment.Part, Style: p.segment.Style, Runs: runs}
+	}
+
+	return segments
+}
+
+// Count returns the size of the document's text.
+func (d *Document) Count() wordcount.Count {
+	var c wordcount.Count
+	for _, p := range d.paragraphs {
+		c = c.Add(wordcount.Text(p.segment.Text()))
+	}
+	return c
+}
+
+// Assemble returns the document with the text of every segment replaced by
+// segments, one per segment returned by Segments and in the same order.
+func (d *Document) Assemble(segments []Segment) ([]byte, error) {
+	if len(segments) != len(d.paragraphs) {
+		return nil, ErrSegmentCount
+	}
+
+	edits := make(map[string][]edit)
+	for i, p := range d.paragraphs {
+		edits[p.segment.Part] = append(edits[p.segment.Part], p.edits(d.parts[p.segment.Part], segments[i])...)
+	}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+
+	for _, f := range d.zip.File {
+		partEdits, ok := edits[f.Name]
+		if !ok {
+			// Unchanged files are copied without recompressing
+			if err := w.Copy(f); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		part, err := applyEdits(d.parts[f.Name], partEdits)
+		if err != nil {
+			return nil, err
+		}
+
+		header := f.FileHeader
+		fw, err := w.CreateHeader(&header)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := fw.Write(part); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// isTextPart tells whether the part named name holds translatable paragraphs.
+func isTextPart(name string) bool {
+	dir, file := path.Split(name)
+	if dir != "word/" || path.Ext(file) != ".xml" {
+		return false
+	}
+
+	base := strings.TrimSuffix(file, ".xml")
+	switch base {
+	case "document", "footnotes", "endnotes", "comments":
+		return true
+	}
+
+	// Headers and footers are numbered, header1.xml, footer2.xml and so on
+	for _, prefix := range []string{"header", "footer"} {
+		if n, ok := strings.CutPrefix(base, prefix); ok && strings.Trim(n, "0123456789") == "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// edit replaces b[start:end] with text.
+type edit struct {
+	start int
+	end   int
+	text  []byte
+}
+
+func applyEdits(b []byte, edits []edit) ([]byte, error) {
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	var out bytes.Buffer
+	prev := 0
+
+	for _, e := range edits {
+		if e.start < prev {
+			return nil, ErrOverlapEdits
+		}
+
+		out.Write(b[prev:e.start])
+		out.Write(e.text)
+		prev = e.end
+	}
+	out.Write(b[prev:])
+
+	return out.Bytes(), nil
+}
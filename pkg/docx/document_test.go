@@ -0,0 +1,241 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"doc-translate-go/pkg/ooxml"
+	"doc-translate-go/pkg/wordcount"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func openFixture(t *testing.T, name string) ([]byte, *Document) {
+	b, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := Open(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b, d
+}
+
+func texts(segments []Segment) []string {
+	var result []string
+	for _, s := range segments {
+		result = append(result, s.Part+": "+s.Text())
+	}
+	return result
+}
+
+func TestOpen(t *testing.T) {
+	_, d := openFixture(t, "sample.docx")
+
+	want := []string{
+		"word/document.xml: Hello world!",
+		"word/document.xml: Visit our site.",
+		"word/document.xml: Split text in runs",
+		"word/document.xml: A & B < C",
+		"word/document.xml: Cell",
+		"word/document.xml: Before box ",
+		"word/document.xml: Boxed",
+		"word/comments.xml: A comment",
+		"word/footer1.xml: Footer text",
+		"word/footnotes.xml:  A footnote",
+		"word/header1.xml: Header text",
+	}
+
+	segments := d.Segments()
+	if got := texts(segments); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	wantRuns := []Run{
+		{Text: "Hello "},
+		{Text: "world", Properties: "<w:rPr><w:b/></w:rPr>"},
+		{Text: "!"},
+	}
+	if !reflect.DeepEqual(wantRuns, segments[0].Runs) {
+		t.Fatalf("expected %v, got %v", wantRuns, segments[0].Runs)
+	}
+
+	// Adjacent runs formatted alike are merged
+	wantRuns = []Run{{Text: "Split text in runs", Properties: "<w:rPr><w:i/></w:rPr>"}}
+	if !reflect.DeepEqual(wantRuns, segments[2].Runs) {
+		t.Fatalf("expected %v, got %v", wantRuns, segments[2].Runs)
+	}
+}
+
+func TestDocument_Count(t *testing.T) {
+	_, d := openFixture(t, "sample.docx")
+
+	want := wordcount.Count{Words: 24, Characters: 99, Tokens: 38}
+	if got := d.Count(); got != want {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestOpen_NotDocx(t *testing.T) {
+	b, err := os.ReadFile("testdata/notdocx.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Open(b); err != ErrNotDocx {
+		t.Fatalf("expected %v, got %v", ErrNotDocx, err)
+	}
+
+	if _, err := Open([]byte("not a zip")); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestAssemble(t *testing.T) {
+	original, d := openFixture(t, "sample.docx")
+
+	segments := d.Segments()
+	for i := range segments {
+		for j := range segments[i].Runs {
+			segments[i].Runs[j].Text = strings.ToUpper(segments[i].Runs[j].Text)
+		}
+	}
+
+	b, err := d.Assemble(segments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	translated, err := Open(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := translated.Segments(); !reflect.DeepEqual(segments, got) {
+		t.Fatalf("expected %v, got %v", segments, got)
+	}
+
+	// Files without text are copied as they are
+	want := readZip(t, original)
+	got := readZip(t, b)
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "word/_rels/document.xml.rels"} {
+		if want[name] != got[name] {
+			t.Fatalf("%s: expected %s, got %s", name, want[name], got[name])
+		}
+	}
+
+	// Field instructions and the markup around runs are left alone
+	for _, s := range []string{` PAGE </w:instrText>`, `<w:hyperlink r:id="rId5">`, `<w:pStyle w:val="Title"/>`, `A &amp; B &lt; C`} {
+		if !strings.Contains(got["word/document.xml"], s) {
+			t.Fatalf("expected document to contain %s", s)
+		}
+	}
+}
+
+func TestAssemble_RunCount(t *testing.T) {
+	_, d := openFixture(t, "sample.docx")
+
+	segments := d.Segments()
+	// Fewer runs than the paragraph had, then more
+	segments[0].Runs = []Run{{Text: "Xin chào thế giới!"}}
+	segments[4].Runs = []Run{{Text: "Ô"}, {Text: " nhỏ", Properties: "<w:rPr><w:b/></w:rPr>"}}
+
+	b, err := d.Assemble(segments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	translated, err := Open(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := translated.Segments()
+	if !reflect.DeepEqual(segments[0].Runs, got[0].Runs) {
+		t.Fatalf("expected %v, got %v", segments[0].Runs, got[0].Runs)
+	}
+	if !reflect.DeepEqual(segments[4].Runs, got[4].Runs) {
+		t.Fatalf("expected %v, got %v", segments[4].Runs, got[4].Runs)
+	}
+}
+
+func TestAssemble_SegmentCount(t *testing.T) {
+	_, d := openFixture(t, "sample.docx")
+
+	if _, err := d.Assemble(d.Segments()[1:]); err != ErrSegmentCount {
+		t.Fatalf("expected %v, got %v", ErrSegmentCount, err)
+	}
+}
+
+// newDocx zips a main document part into the smallest DOCX Open accepts.
+func newDocx(t *testing.T, document string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+
+	f, err := w.Create(mainDocumentPart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` + document + `</w:body></w:document>`))
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestOpen_Styles(t *testing.T) {
+	d, err := Open(newDocx(t, `<w:p><w:pPr><w:pStyle w:val="DoNotTranslate"/></w:pPr><w:r><w:t>Acme Cloud</w:t></w:r></w:p>`+
+		`<w:p><w:r><w:t xml:space="preserve">Run </w:t></w:r><w:r><w:rPr><w:rStyle w:val="Code"/></w:rPr><w:t>ls -l</w:t></w:r></w:p>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	segments := d.Segments()
+	if segments[0].Style != "DoNotTranslate" || segments[1].Style != "" {
+		t.Fatalf("unexpected paragraph styles %q and %q", segments[0].Style, segments[1].Style)
+	}
+
+	if got := segments[1].Runs[1].Style(); got != "Code" {
+		t.Fatalf("expected %s, got %s", "Code", got)
+	}
+	if got := segments[1].Runs[0].Style(); got != "" {
+		t.Fatalf("expected no style, got %s", got)
+	}
+}
+
+func readZip(t *testing.T, b []byte) map[string]string {
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(map[string]string)
+	for _, f := range r.File {
+		content, err := ooxml.ReadFile(r, f.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = string(content)
+	}
+
+	return files
+}
+
+func TestOpen_PartTooLarge(t *testing.T) {
+	prev := ooxml.MaxPartSize
+	ooxml.MaxPartSize = 64
+	t.Cleanup(func() { ooxml.MaxPartSize = prev })
+
+	b := newDocx(t, `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>`+strings.Repeat("a", 64)+`</w:t></w:r></w:p></w:body></w:document>`)
+
+	if _, err := Open(b); !errors.Is(err, ooxml.ErrPartTooLarge) {
+		t.Fatalf("expected %v, got %v", ooxml.ErrPartTooLarge, err)
+	}
+}
@@ -0,0 +1,224 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WordprocessingML namespaces, transitional and strict
+var wordNamespaces = map[string]bool{
+	"http://schemas.openxmlformats.org/wordprocessingml/2006/main": true,
+	"http://purl.oclc.org/ooxml/wordprocessingml/main":             true,
+}
+
+func isWord(name xml.Name, local string) bool {
+	return name.Local == local && wordNamespaces[name.Space]
+}
+
+// wordAttr returns the value of an element's attribute, such as w:val.
+func wordAttr(e xml.StartElement, local string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == local && (a.Name.Space == "" || wordNamespaces[a.Name.Space]) {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// textRun locates a <w:r> holding text in its part, by byte offsets.
+type textRun struct {
+	start int
+	end   int
+	// The <w:rPr> element, or the end of the run's start tag when it has none
+	propsStart int
+	propsEnd   int
+	// The <w:t> elements directly in the run
+	texts [][2]int
+}
+
+// paragraph is a <w:p> holding text and the segment extracted from it.
+type paragraph struct {
+	start   int
+	runs    []*textRun
+	segment Segment
+}
+
+type openRun struct {
+	run   *textRun
+	props string
+	text  strings.Builder
+}
+
+// parsePart extracts the paragraphs holding text from a part. Only <w:t> is
+// text, field instructions, deleted text, tabs and breaks are left as they are.
+// Paragraphs nested in text boxes are segments of their own.
+func parsePart(name string, b []byte) ([]*paragraph, error) {
+	d := xml.NewDecoder(bytes.NewReader(b))
+
+	var result []*paragraph
+	var elements []xml.Name
+	var paragraphs []*paragraph
+	var runs []*openRun
+
+	// parentIs tells whether the element being opened or closed sits directly in a local element
+	parentIs := func(local string, depth int) bool {
+		return len(elements) >= depth && isWord(elements[len(elements)-depth], local)
+	}
+
+	for {
+		offset := int(d.InputOffset())
+
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case isWord(t.Name, "p"):
+				paragraphs = append(paragraphs, &paragraph{start: offset})
+			case isWord(t.Name, "r"):
+				end := int(d.InputOffset())
+				runs = append(runs, &openRun{run: &textRun{start: offset, propsStart: end, propsEnd: end}})
+			case isWord(t.Name, "rPr") && parentIs("r", 1) && len(runs) > 0:
+				runs[len(runs)-1].run.propsStart = offset
+			case isWord(t.Name, "t") && parentIs("r", 1) && len(runs) > 0:
+				r := runs[len(runs)-1].run
+				r.texts = append(r.texts, [2]int{offset, offset})
+			case isWord(t.Name, "pStyle") && parentIs("pPr", 1) && parentIs("p", 2) && len(paragraphs) > 0:
+				paragraphs[len(paragraphs)-1].segment.Style = wordAttr(t, "val")
+			}
+
+			elements = append(elements, t.Name)
+		case xml.EndElement:
+			elements = elements[:len(elements)-1]
+			end := int(d.InputOffset())
+
+			switch {
+			case isWord(t.Name, "p") && len(paragraphs) > 0:
+				p := paragraphs[len(paragraphs)-1]
+				paragraphs = paragraphs[:len(paragraphs)-1]
+
+				if p.finish(name) {
+					result = append(result, p)
+				}
+			case isWord(t.Name, "r") && len(runs) > 0:
+				r := runs[len(runs)-1]
+				runs = runs[:len(runs)-1]
+				r.run.end = end
+
+				if len(r.run.texts) > 0 && len(paragraphs) > 0 {
+					p := paragraphs[len(paragraphs)-1]
+					p.runs = append(p.runs, r.run)
+					p.segment.Runs = append(p.segment.Runs, Run{Text: r.text.String(), Properties: r.props})
+				}
+			case isWord(t.Name, "rPr") && parentIs("r", 1) && len(runs) > 0:
+				r := runs[len(runs)-1]
+				r.run.propsEnd = end
+				r.props = string(b[r.run.propsStart:end])
+			case isWord(t.Name, "t") && parentIs("r", 1) && len(runs) > 0:
+				r := runs[len(runs)-1].run
+				r.texts[len(r.texts)-1][1] = end
+			}
+		case xml.CharData:
+			if len(elements) > 0 && isWord(elements[len(elements)-1], "t") && parentIs("r", 2) && len(runs) > 0 {
+				runs[len(runs)-1].text.Write(t)
+			}
+		}
+	}
+
+	// Paragraphs in text boxes end before the paragraph holding them
+	sort.Slice(result, func(i, j int) bool { return result[i].start < result[j].start })
+
+	return result, nil
+}
+
+// finish merges adjacent runs formatted alike, drops empty ones and tells
+// whether the paragraph holds any text worth translating.
+func (p *paragraph) finish(part string) bool {
+	p.segment.Part = part
+
+	var runs []Run
+	for _, r := range p.segment.Runs {
+		if r.Text == "" {
+			continue
+		}
+		if n := len(runs); n > 0 && runs[n-1].Properties == r.Properties {
+			runs[n-1].Text += r.Text
+			continue
+		}
+		runs = append(runs, r)
+	}
+	p.segment.Runs = runs
+
+	return strings.TrimSpace(p.segment.Text()) != ""
+}
+
+// edits rewrites the paragraph's runs with those of s. Runs are filled in
+// order, the ones left over are emptied and extra runs are added after the last.
+func (p *paragraph) edits(b []byte, s Segment) []edit {
+	var edits []edit
+
+	for i, r := range p.runs {
+		props := string(b[r.propsStart:r.propsEnd])
+		text := ""
+		if i < len(s.Runs) {
+			props, text = s.Runs[i].Properties, s.Runs[i].Text
+		}
+
+		if props != string(b[r.propsStart:r.propsEnd]) {
+			edits = append(edits, edit{r.propsStart, r.propsEnd, []byte(props)})
+		}
+
+		// All the run's text goes in its first <w:t>
+		first := r.texts[0]
+		edits = append(edits, edit{first[0], first[1], textElement(qualifiedName(b[first[0]:]), text)})
+
+		for _, t := range r.texts[1:] {
+			edits = append(edits, edit{t[0], t[1], nil})
+		}
+	}
+
+	if len(s.Runs) > len(p.runs) {
+		last := p.runs[len(p.runs)-1]
+		runName := qualifiedName(b[last.start:])
+		textName := qualifiedName(b[last.texts[0][0]:])
+
+		var extra bytes.Buffer
+		for _, r := range s.Runs[len(p.runs):] {
+			extra.WriteString("<" + runName + ">" + r.Properties)
+			extra.Write(textElement(textName, r.Text))
+			extra.WriteString("</" + runName + ">")
+		}
+
+		edits = append(edits, edit{last.end, last.end, extra.Bytes()})
+	}
+
+	return edits
+}
+
+// qualifiedName returns the prefixed name of the element starting b, e.g. w:t.
+func qualifiedName(b []byte) string {
+	end := bytes.IndexAny(b[1:], " \t\r\n/>")
+	if end < 0 {
+		return string(b[1:])
+	}
+	return string(b[1 : end+1])
+}
+
+func textElement(name string, text string) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("<" + name + ` xml:space="preserve">`)
+	xml.EscapeText(&buf, []byte(text))
+	buf.WriteString("</" + name + ">")
+
+	return buf.Bytes()
+}
@@ -0,0 +1,127 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Run is text sharing the same formatting.
+type Run struct {
+	Text string
+	// Properties is the run's raw <w:rPr> element, empty when unformatted
+	Properties string
+}
+
+// Style returns the id of the run's character style, empty when it has none.
+func (r Run) Style() string {
+	if m := runStyle.FindStringSubmatch(r.Properties); m != nil {
+		return html.UnescapeString(m[1])
+	}
+	return ""
+}
+
+var runStyle = regexp.MustCompile(`<(?:\w+:)?rStyle\s[^>]*?(?:\w+:)?val="([^"]*)"`)
+
+// Segment is the text of a paragraph, split into differently formatted runs.
+type Segment struct {
+	// Part is the name of the part the paragraph is in, e.g. word/header1.xml
+	Part string
+	// Style is the id of the paragraph's style, empty for the default
+	Style string
+	Runs  []Run
+}
+
+// Text returns the segment's text without formatting.
+func (s Segment) Text() string {
+	var b strings.Builder
+	for _, r := range s.Runs {
+		b.WriteString(r.Text)
+	}
+	return b.String()
+}
+
+// Markup returns the segment's text with every run wrapped in a numbered tag,
+// <g1>Hello </g1><g2>world</g2>, so a translator can move formatting along
+// with the words. Text of a single run isn't tagged. Either way it's escaped.
+func (s Segment) Markup() string {
+	var b bytes.Buffer
+
+	if len(s.Runs) == 1 {
+		xml.EscapeText(&b, []byte(s.Runs[0].Text))
+		return b.String()
+	}
+
+	for i, r := range s.Runs {
+		fmt.Fprintf(&b, "<g%d>", i+1)
+		xml.EscapeText(&b, []byte(r.Text))
+		fmt.Fprintf(&b, "</g%d>", i+1)
+	}
+
+	return b.String()
+}
+
+var markupTag = regexp.MustCompile(`<(/?)g(\d+)>`)
+
+// ParseMarkup returns the segment translated into markup, as produced by
+// Markup. Tagged text takes the formatting of the run its tag numbers, text
+// outside tags that of the run before it. Unknown tags are dropped.
+func (s Segment) ParseMarkup(markup string) Segment {
+	result := Segment{Part: s.Part, Style: s.Style}
+
+	format := func(tag int) (string, bool) {
+		if tag < 1 || tag > len(s.Runs) {
+			return "", false
+		}
+		return s.Runs[tag-1].Properties, true
+	}
+
+	props, _ := format(1)
+	var open []string
+
+	add := func(text string) {
+		if text == "" {
+			return
+		}
+
+		text = html.UnescapeString(text)
+		if n := len(result.Runs); n > 0 && result.Runs[n-1].Properties == props {
+			result.Runs[n-1].Text += text
+			return
+		}
+		result.Runs = append(result.Runs, Run{Text: text, Properties: props})
+	}
+
+	prev := 0
+	for _, m := range markupTag.FindAllStringSubmatchIndex(markup, -1) {
+		add(markup[prev:m[0]])
+		prev = m[1]
+
+		tag, _ := strconv.Atoi(markup[m[4]:m[5]])
+		p, ok := format(tag)
+		if !ok {
+			continue
+		}
+
+		if m[3] > m[2] {
+			// Closing a tag goes back to the formatting around it, if nested
+			if n := len(open); n > 1 {
+				open = open[:n-1]
+				props = open[n-2]
+			} else {
+				open = nil
+			}
+			continue
+		}
+
+		open = append(open, p)
+		props = p
+	}
+	add(markup[prev:])
+
+	return result
+}
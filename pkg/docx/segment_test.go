@@ -0,0 +1,62 @@
+package docx
+
+import (
+	"reflect"
+	"testing"
+)
+
+var bold = "<w:rPr><w:b/></w:rPr>"
+
+func TestSegment_Markup(t *testing.T) {
+	s := Segment{Runs: []Run{{Text: "Hello "}, {Text: "big & bold", Properties: bold}, {Text: "!"}}}
+
+	want := "<g1>Hello </g1><g2>big &amp; bold</g2><g3>!</g3>"
+	if got := s.Markup(); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+
+	single := Segment{Runs: []Run{{Text: "a < b", Properties: bold}}}
+	if got := single.Markup(); got != "a &lt; b" {
+		t.Fatalf("expected %s, got %s", "a &lt; b", got)
+	}
+}
+
+func TestSegment_ParseMarkup(t *testing.T) {
+	s := Segment{Part: "word/document.xml", Runs: []Run{{Text: "Hello "}, {Text: "world", Properties: bold}, {Text: "!"}}}
+
+	tests := []struct {
+		markup string
+		want   []Run
+	}{
+		{
+			"<g1>Xin chào </g1><g2>thế giới</g2><g3>!</g3>",
+			[]Run{{Text: "Xin chào "}, {Text: "thế giới", Properties: bold}, {Text: "!"}},
+		},
+		{
+			// Reordered, and text outside tags formatted like the run before it
+			"<g2>Monde</g2> &amp; <g1>bonjour</g1>",
+			[]Run{{Text: "Monde & ", Properties: bold}, {Text: "bonjour"}},
+		},
+		{
+			"untagged <g9>unknown</g9>",
+			[]Run{{Text: "untagged unknown"}},
+		},
+	}
+
+	for _, tt := range tests {
+		got := s.ParseMarkup(tt.markup)
+		if got.Part != s.Part || !reflect.DeepEqual(tt.want, got.Runs) {
+			t.Fatalf("%s: expected %v, got %v", tt.markup, tt.want, got.Runs)
+		}
+	}
+}
+
+func TestSegment_MarkupRoundTrip(t *testing.T) {
+	_, d := openFixture(t, "sample.docx")
+
+	for _, s := range d.Segments() {
+		if got := s.ParseMarkup(s.Markup()); !reflect.DeepEqual(s, got) {
+			t.Fatalf("expected %v, got %v", s, got)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// DeadLetter is a translate task that failed permanently or ran out of attempts.
+type DeadLetter struct {
+	Id        int
+	Task      string
+	Stage     string
+	Error     string
+	Attempts  int
+	CreatedAt time.Time
+	CreatedBy string
+}
@@ -1,6 +1,9 @@
 package entity
 
-import "time"
+import (
+	"doc-translate-go/pkg/glossary/entity"
+	"time"
+)
 
 type TranslatedFileMetadata struct {
 	Id             int
@@ -8,8 +11,25 @@ type TranslatedFileMetadata struct {
 	Filename       string
 	TargetLanguage string
 	Cost           float64
-	TimeTaken      int
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
-	CreatedBy      string
+	// TimeTaken is how long translating took and QueueTime how long the file
+	// waited for a worker before that, both in milliseconds
+	TimeTaken        int
+	QueueTime        int
+	BilledCharacters int
+	BilledTokens     int
+	// GlossaryId is the glossary the file was translated with, 0 for none,
+	// and TermFlags the paragraphs whose translation misses one of its
+	// required terms
+	GlossaryId int
+	TermFlags  []entity.Flag
+	// MemoryHits are the segments of a file translated from an exact
+	// translation memory match, MemoryFuzzyHits those sent for translation
+	// with a close match and MemoryMisses those sent without any. They're nil
+	// for files whose segments weren't looked up, such as reused translations
+	MemoryHits      *int
+	MemoryFuzzyHits *int
+	MemoryMisses    *int
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	CreatedBy       string
 }
@@ -1,6 +1,9 @@
 package queue
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type ChannelTranslateQueue struct {
 	c chan *TranslateTask
@@ -10,21 +13,34 @@ func NewChannelTranslateQueue(c chan *TranslateTask) *ChannelTranslateQueue {
 	return &ChannelTranslateQueue{c}
 }
 
-func (q *ChannelTranslateQueue) Add(t *TranslateTask) error {
-	q.c <- t
-	return nil
+// Add sends the task to the channel. A task held back until NotBefore is sent
+// then, regardless of ctx.
+func (q *ChannelTranslateQueue) Add(ctx context.Context, t *TranslateTask) error {
+	if delay := time.Until(t.NotBefore); delay > 0 {
+		time.AfterFunc(delay, func() { q.c <- t })
+		return nil
+	}
+
+	select {
+	case q.c <- t:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
-func (q *ChannelTranslateQueue) Take() (*TranslateTask, string) {
+func (q *ChannelTranslateQueue) Take(ctx context.Context) (*TranslateTask, string) {
 	select {
 	case t := <-q.c:
 		return t, ""
+	case <-ctx.Done():
+		return nil, ""
 	case <-time.After(100 * time.Millisecond):
 		return nil, ""
 	}
 }
 
-func (q *ChannelTranslateQueue) Delete(key string) error {
+func (q *ChannelTranslateQueue) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
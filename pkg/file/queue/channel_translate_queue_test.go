@@ -1,6 +1,7 @@
 package queue
 
 import (
+	"context"
 	"reflect"
 	"testing"
 )
@@ -15,22 +16,22 @@ func TestChannelTranslateQueue(t *testing.T) {
 
 	want := &TranslateTask{}
 
-	err := q.Add(want)
+	err := q.Add(context.Background(), want)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	got, _ := q.Take()
+	got, _ := q.Take(context.Background())
 	if !reflect.DeepEqual(got, want) {
 		t.Fatalf("expected %v, got %v", want, got)
 	}
 
-	got, _ = q.Take()
+	got, _ = q.Take(context.Background())
 	if got != nil {
 		t.Fatalf("expected nil, got %v", got)
 	}
 
-	err = q.Delete("key")
+	err = q.Delete(context.Background(), "key")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,112 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"doc-translate-go/pkg/db"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// PostgresqlTranslateQueue stores tasks in the translate_jobs table.
+// A taken job is leased rather than removed, so when a worker dies before
+// calling Delete the job becomes available again once its lease expires.
+// A task's NotBefore becomes the job's available_at.
+type PostgresqlTranslateQueue struct {
+	querier      db.Querier
+	lease        time.Duration
+	wait         time.Duration
+	pollInterval time.Duration
+}
+
+func NewPostgresqlTranslateQueue(querier db.Querier, lease time.Duration) *PostgresqlTranslateQueue {
+	return &PostgresqlTranslateQueue{querier, lease, 2 * time.Second, 500 * time.Millisecond}
+}
+
+func (q *PostgresqlTranslateQueue) Add(ctx context.Context, t *TranslateTask) error {
+	taskJson, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	var availableAt sql.NullTime
+	if !t.NotBefore.IsZero() {
+		availableAt = sql.NullTime{Time: t.NotBefore, Valid: true}
+	}
+
+	cmd := `INSERT INTO translate_jobs (task, available_at) VALUES ($1, $2);`
+
+	_, err = q.querier.ExecContext(ctx, cmd, taskJson, availableAt)
+	return err
+}
+
+// Take polls for an available job for up to the queue's wait time.
+func (q *PostgresqlTranslateQueue) Take(ctx context.Context) (*TranslateTask, string) {
+	deadline := time.Now().Add(q.wait)
+
+	for {
+		task, key, found := q.take(ctx)
+		if found && task == nil {
+			// Delete malformed jobs so they are not leased over and over
+			q.Delete(ctx, key)
+			return nil, ""
+		}
+
+		if found || time.Now().Add(q.pollInterval).After(deadline) {
+			return task, key
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ""
+		case <-time.After(q.pollInterval):
+		}
+	}
+}
+
+// take leases the next available job. A job whose task can't be read is
+// found with a nil task, still keyed so that it can be deleted.
+func (q *PostgresqlTranslateQueue) take(ctx context.Context) (*TranslateTask, string, bool) {
+	cmd := `UPDATE translate_jobs SET leased_until = $1
+        WHERE id = (
+                SELECT id FROM translate_jobs
+                WHERE (leased_until IS NULL OR leased_until < $2)
+                AND (available_at IS NULL OR available_at <= $2)
+                ORDER BY id
+                LIMIT 1
+                FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id, task;`
+
+	now := time.Now()
+	row := q.querier.QueryRowContext(ctx, cmd, now.Add(q.lease), now)
+
+	var id int
+	var taskJson []byte
+	if err := row.Scan(&id, &taskJson); err != nil {
+		return nil, "", false
+	}
+
+	var task *TranslateTask
+	if err := json.Unmarshal(taskJson, &task); err != nil {
+		return nil, strconv.Itoa(id), true
+	}
+
+	return task, strconv.Itoa(id), true
+}
+
+func (q *PostgresqlTranslateQueue) Delete(ctx context.Context, key string) error {
+	id, err := strconv.Atoi(key)
+	if err != nil {
+		return err
+	}
+
+	cmd := `DELETE FROM translate_jobs WHERE id = $1;`
+
+	_, err = q.querier.ExecContext(ctx, cmd, id)
+	return err
+}
+
+// Ensure implementation
+var _ TranslateQueue = (*PostgresqlTranslateQueue)(nil)
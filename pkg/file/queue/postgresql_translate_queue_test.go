@@ -0,0 +1,220 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func newPostgresqlMock(t *testing.T) (sqlmock.Sqlmock, *PostgresqlTranslateQueue) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queue := NewPostgresqlTranslateQueue(db, time.Minute)
+	queue.wait = 0
+
+	return mock, queue
+}
+
+const takeJobCmd = `UPDATE translate_jobs SET leased_until = \$1
+        WHERE id = \(
+                SELECT id FROM translate_jobs
+                WHERE \(leased_until IS NULL OR leased_until < \$2\)
+                AND \(available_at IS NULL OR available_at <= \$2\)
+                ORDER BY id
+                LIMIT 1
+                FOR UPDATE SKIP LOCKED
+        \)
+        RETURNING id, task;`
+
+func TestPostgresqlTranslateQueue_Add(t *testing.T) {
+	mock, queue := newPostgresqlMock(t)
+
+	task := &TranslateTask{Isid: "1", Filename: "filename", OriginalFileId: 1}
+	taskJson, _ := json.Marshal(task)
+
+	mock.ExpectExec(`INSERT INTO translate_jobs \(task, available_at\) VALUES \(\$1, \$2\);`).
+		WithArgs(taskJson, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	if err := queue.Add(context.Background(), task); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlTranslateQueue_Add_NotBefore(t *testing.T) {
+	mock, queue := newPostgresqlMock(t)
+
+	notBefore := time.Now().Add(time.Minute)
+	task := &TranslateTask{Isid: "1", Filename: "filename", OriginalFileId: 1, NotBefore: notBefore}
+	taskJson, _ := json.Marshal(task)
+
+	mock.ExpectExec(`INSERT INTO translate_jobs \(task, available_at\) VALUES \(\$1, \$2\);`).
+		WithArgs(taskJson, notBefore).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	if err := queue.Add(context.Background(), task); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlTranslateQueue_Add_Err(t *testing.T) {
+	mock, queue := newPostgresqlMock(t)
+
+	e := errors.New("insert error")
+	mock.ExpectExec(`INSERT INTO translate_jobs \(task, available_at\) VALUES \(\$1, \$2\);`).WillReturnError(e)
+
+	if err := queue.Add(context.Background(), &TranslateTask{}); err != e {
+		t.Fatalf("expected %v, got %v", e, err)
+	}
+}
+
+func TestPostgresqlTranslateQueue_Take(t *testing.T) {
+	mock, queue := newPostgresqlMock(t)
+
+	want := &TranslateTask{Isid: "1", Filename: "filename", OriginalFileId: 1}
+	taskJson, _ := json.Marshal(want)
+
+	rows := sqlmock.NewRows([]string{"id", "task"}).AddRow(7, taskJson)
+	mock.ExpectQuery(takeJobCmd).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnRows(rows)
+
+	got, key := queue.Take(context.Background())
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if key != "7" {
+		t.Fatalf("expected %v, got %v", "7", key)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlTranslateQueue_Take_Empty(t *testing.T) {
+	mock, queue := newPostgresqlMock(t)
+
+	mock.ExpectQuery(takeJobCmd).WillReturnError(sql.ErrNoRows)
+
+	got, key := queue.Take(context.Background())
+	if got != nil {
+		t.Fatalf("expected nil, got %v", got)
+	}
+	if key != "" {
+		t.Fatalf("expected empty key, got %v", key)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlTranslateQueue_Take_Malformed(t *testing.T) {
+	mock, queue := newPostgresqlMock(t)
+
+	rows := sqlmock.NewRows([]string{"id", "task"}).AddRow(7, []byte("{"))
+	mock.ExpectQuery(takeJobCmd).WillReturnRows(rows)
+	mock.ExpectExec(`DELETE FROM translate_jobs WHERE id = \$1;`).
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	got, key := queue.Take(context.Background())
+	if got != nil {
+		t.Fatalf("expected nil, got %v", got)
+	}
+	if key != "" {
+		t.Fatalf("expected empty key, got %v", key)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlTranslateQueue_Take_Poll(t *testing.T) {
+	mock, queue := newPostgresqlMock(t)
+	queue.wait = 50 * time.Millisecond
+	queue.pollInterval = 20 * time.Millisecond
+
+	want := &TranslateTask{Isid: "1", Filename: "filename", OriginalFileId: 1}
+	taskJson, _ := json.Marshal(want)
+
+	mock.ExpectQuery(takeJobCmd).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(takeJobCmd).WillReturnRows(sqlmock.NewRows([]string{"id", "task"}).AddRow(7, taskJson))
+
+	got, key := queue.Take(context.Background())
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if key != "7" {
+		t.Fatalf("expected %v, got %v", "7", key)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlTranslateQueue_Take_Cancelled(t *testing.T) {
+	mock, queue := newPostgresqlMock(t)
+	queue.wait = time.Minute
+	queue.pollInterval = time.Second
+
+	mock.ExpectQuery(takeJobCmd).WillReturnError(sql.ErrNoRows)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	// Cancelling stops polling instead of waiting out the queue's wait time
+	start := time.Now()
+	if got, _ := queue.Take(ctx); got != nil {
+		t.Fatalf("expected nil, got %v", got)
+	}
+	if elapsed := time.Since(start); elapsed >= queue.pollInterval {
+		t.Fatalf("expected Take to return once cancelled, took %v", elapsed)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlTranslateQueue_Delete(t *testing.T) {
+	mock, queue := newPostgresqlMock(t)
+
+	mock.ExpectExec(`DELETE FROM translate_jobs WHERE id = \$1;`).
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := queue.Delete(context.Background(), "7"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlTranslateQueue_Delete_InvalidKey(t *testing.T) {
+	_, queue := newPostgresqlMock(t)
+
+	if err := queue.Delete(context.Background(), "key"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
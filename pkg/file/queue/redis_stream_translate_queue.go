@@ -0,0 +1,161 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisStreamTaskField = "task"
+
+// redisStreamDelayedSuffix names, after the stream, the sorted set holding
+// tasks until their NotBefore.
+const redisStreamDelayedSuffix = ":delayed"
+
+// redisStreamPromoteCount is how many due tasks Take moves onto the stream at once.
+const redisStreamPromoteCount = 10
+
+// RedisStreamTranslateQueue stores tasks in a Redis stream read through a
+// consumer group. Take's key is the stream entry ID, which Delete acknowledges.
+//
+// Streams can't hold entries back, so tasks with a NotBefore wait in a sorted
+// set scored by it, and Take adds those that are due to the stream.
+//
+// Entries left pending longer than minIdle are assumed to belong to a dead
+// worker and are claimed by the next Take, so minIdle must be longer than
+// the slowest translation.
+type RedisStreamTranslateQueue struct {
+	client   *redis.ClusterClient
+	stream   string
+	group    string
+	consumer string
+	minIdle  time.Duration
+}
+
+func NewRedisStreamTranslateQueue(client *redis.ClusterClient, stream string, group string, consumer string, minIdle time.Duration) *RedisStreamTranslateQueue {
+	return &RedisStreamTranslateQueue{client, stream, group, consumer, minIdle}
+}
+
+// CreateGroup creates the stream and its consumer group if they do not exist yet.
+func (q *RedisStreamTranslateQueue) CreateGroup() error {
+	err := q.client.XGroupCreateMkStream(context.Background(), q.stream, q.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+func (q *RedisStreamTranslateQueue) Add(ctx context.Context, t *TranslateTask) error {
+	taskJson, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	if t.NotBefore.After(time.Now()) {
+		return q.client.ZAdd(ctx, q.delayed(), redis.Z{
+			Score:  float64(t.NotBefore.UnixMilli()),
+			Member: string(taskJson),
+		}).Err()
+	}
+
+	return q.add(ctx, string(taskJson))
+}
+
+func (q *RedisStreamTranslateQueue) add(ctx context.Context, taskJson string) error {
+	return q.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: q.stream,
+		Values: []string{redisStreamTaskField, taskJson},
+	}).Err()
+}
+
+func (q *RedisStreamTranslateQueue) delayed() string {
+	return q.stream + redisStreamDelayedSuffix
+}
+
+// promote adds the delayed tasks that are due to the stream. Only the consumer
+// that removes a task from the sorted set adds it, so none is added twice.
+func (q *RedisStreamTranslateQueue) promote(ctx context.Context) {
+	due, err := q.client.ZRangeByScore(ctx, q.delayed(), &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(time.Now().UnixMilli(), 10),
+		Count: redisStreamPromoteCount,
+	}).Result()
+	if err != nil {
+		return
+	}
+
+	for _, taskJson := range due {
+		removed, err := q.client.ZRem(ctx, q.delayed(), taskJson).Result()
+		if err != nil || removed == 0 {
+			continue
+		}
+
+		if err := q.add(ctx, taskJson); err != nil {
+			// Put it back for the next Take rather than lose it
+			q.client.ZAdd(ctx, q.delayed(), redis.Z{Score: float64(time.Now().UnixMilli()), Member: taskJson})
+		}
+	}
+}
+
+func (q *RedisStreamTranslateQueue) Take(ctx context.Context) (*TranslateTask, string) {
+	q.promote(ctx)
+
+	message, ok := q.claim(ctx)
+	if !ok {
+		streams, err := q.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    q.group,
+			Consumer: q.consumer,
+			Streams:  []string{q.stream, ">"},
+			Count:    1,
+			Block:    2 * time.Second,
+		}).Result()
+		if err != nil || len(streams) == 0 || len(streams[0].Messages) == 0 {
+			return nil, ""
+		}
+
+		message = streams[0].Messages[0]
+	}
+
+	body, _ := message.Values[redisStreamTaskField].(string)
+
+	var task *TranslateTask
+	if err := json.Unmarshal([]byte(body), &task); err != nil {
+		// Acknowledge malformed entries so they are not claimed forever
+		q.Delete(ctx, message.ID)
+		return nil, ""
+	}
+
+	return task, message.ID
+}
+
+// claim takes over one entry that another consumer has left pending for too long.
+func (q *RedisStreamTranslateQueue) claim(ctx context.Context) (redis.XMessage, bool) {
+	messages, _, err := q.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   q.stream,
+		Group:    q.group,
+		Consumer: q.consumer,
+		MinIdle:  q.minIdle,
+		Start:    "0-0",
+		Count:    1,
+	}).Result()
+	if err != nil || len(messages) == 0 {
+		return redis.XMessage{}, false
+	}
+
+	return messages[0], true
+}
+
+func (q *RedisStreamTranslateQueue) Delete(ctx context.Context, key string) error {
+	if err := q.client.XAck(ctx, q.stream, q.group, key).Err(); err != nil {
+		return err
+	}
+
+	return q.client.XDel(ctx, q.stream, key).Err()
+}
+
+// Ensure implementation
+var _ TranslateQueue = (*RedisStreamTranslateQueue)(nil)
@@ -0,0 +1,299 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+)
+
+func newRedisStreamMock() (redismock.ClusterClientMock, *RedisStreamTranslateQueue) {
+	client, mock := redismock.NewClusterMock()
+	queue := NewRedisStreamTranslateQueue(client, "stream", "group", "consumer", time.Minute)
+	return mock, queue
+}
+
+func autoClaimArgs() *redis.XAutoClaimArgs {
+	return &redis.XAutoClaimArgs{
+		Stream:   "stream",
+		Group:    "group",
+		Consumer: "consumer",
+		MinIdle:  time.Minute,
+		Start:    "0-0",
+		Count:    1,
+	}
+}
+
+func readGroupArgs() *redis.XReadGroupArgs {
+	return &redis.XReadGroupArgs{
+		Group:    "group",
+		Consumer: "consumer",
+		Streams:  []string{"stream", ">"},
+		Count:    1,
+		Block:    2 * time.Second,
+	}
+}
+
+// expectPromote expects Take to look for delayed tasks, finding due. The score
+// they are due by is the time of the call, so it isn't matched.
+func expectPromote(mock redismock.ClusterClientMock, due ...string) {
+	mock.CustomMatch(func(expected, actual []any) error {
+		if len(expected) != len(actual) {
+			return fmt.Errorf("expected %v, got %v", expected, actual)
+		}
+		for i := range expected {
+			if i != 3 && fmt.Sprint(expected[i]) != fmt.Sprint(actual[i]) {
+				return fmt.Errorf("expected %v, got %v", expected, actual)
+			}
+		}
+		return nil
+	}).ExpectZRangeByScore("stream:delayed", &redis.ZRangeBy{Min: "-inf", Max: "0", Count: 10}).SetVal(due)
+}
+
+func TestRedisStreamTranslateQueue_CreateGroup(t *testing.T) {
+	mock, queue := newRedisStreamMock()
+
+	mock.ExpectXGroupCreateMkStream("stream", "group", "0").SetVal("OK")
+
+	if err := queue.CreateGroup(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedisStreamTranslateQueue_CreateGroup_Exists(t *testing.T) {
+	mock, queue := newRedisStreamMock()
+
+	mock.ExpectXGroupCreateMkStream("stream", "group", "0").
+		SetErr(errors.New("BUSYGROUP Consumer Group name already exists"))
+
+	if err := queue.CreateGroup(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedisStreamTranslateQueue_Add(t *testing.T) {
+	mock, queue := newRedisStreamMock()
+
+	task := &TranslateTask{Isid: "1", Filename: "filename", OriginalFileId: 1}
+	taskJson, _ := json.Marshal(task)
+
+	mock.ExpectXAdd(&redis.XAddArgs{
+		Stream: "stream",
+		Values: []string{"task", string(taskJson)},
+	}).SetVal("1-0")
+
+	if err := queue.Add(context.Background(), task); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedisStreamTranslateQueue_Add_Err(t *testing.T) {
+	mock, queue := newRedisStreamMock()
+
+	task := &TranslateTask{Isid: "1", Filename: "filename", OriginalFileId: 1}
+	taskJson, _ := json.Marshal(task)
+
+	e := errors.New("xadd error")
+	mock.ExpectXAdd(&redis.XAddArgs{
+		Stream: "stream",
+		Values: []string{"task", string(taskJson)},
+	}).SetErr(e)
+
+	if err := queue.Add(context.Background(), task); err != e {
+		t.Fatalf("expected %v, got %v", e, err)
+	}
+}
+
+func TestRedisStreamTranslateQueue_Add_NotBefore(t *testing.T) {
+	mock, queue := newRedisStreamMock()
+
+	notBefore := time.Now().Add(time.Minute)
+	task := &TranslateTask{Isid: "1", Filename: "filename", OriginalFileId: 1, NotBefore: notBefore}
+	taskJson, _ := json.Marshal(task)
+
+	// Held back in the sorted set rather than added to the stream
+	mock.ExpectZAdd("stream:delayed", redis.Z{Score: float64(notBefore.UnixMilli()), Member: string(taskJson)}).SetVal(1)
+
+	if err := queue.Add(context.Background(), task); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedisStreamTranslateQueue_Take_Due(t *testing.T) {
+	mock, queue := newRedisStreamMock()
+
+	want := &TranslateTask{Isid: "1", Filename: "filename", OriginalFileId: 1, NotBefore: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	taskJson, _ := json.Marshal(want)
+
+	// Another consumer removing the second task first adds it instead
+	expectPromote(mock, string(taskJson), "other")
+	mock.ExpectZRem("stream:delayed", string(taskJson)).SetVal(1)
+	mock.ExpectXAdd(&redis.XAddArgs{
+		Stream: "stream",
+		Values: []string{"task", string(taskJson)},
+	}).SetVal("2-0")
+	mock.ExpectZRem("stream:delayed", "other").SetVal(0)
+	mock.ExpectXAutoClaim(autoClaimArgs()).SetVal(nil, "0-0")
+	mock.ExpectXReadGroup(readGroupArgs()).SetVal([]redis.XStream{
+		{
+			Stream:   "stream",
+			Messages: []redis.XMessage{{ID: "2-0", Values: map[string]any{"task": string(taskJson)}}},
+		},
+	})
+
+	got, key := queue.Take(context.Background())
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if key != "2-0" {
+		t.Fatalf("expected %v, got %v", "2-0", key)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedisStreamTranslateQueue_Take(t *testing.T) {
+	mock, queue := newRedisStreamMock()
+
+	want := &TranslateTask{Isid: "1", Filename: "filename", OriginalFileId: 1}
+	taskJson, _ := json.Marshal(want)
+
+	expectPromote(mock)
+	mock.ExpectXAutoClaim(autoClaimArgs()).SetVal(nil, "0-0")
+	mock.ExpectXReadGroup(readGroupArgs()).SetVal([]redis.XStream{
+		{
+			Stream:   "stream",
+			Messages: []redis.XMessage{{ID: "1-0", Values: map[string]any{"task": string(taskJson)}}},
+		},
+	})
+
+	got, key := queue.Take(context.Background())
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if key != "1-0" {
+		t.Fatalf("expected %v, got %v", "1-0", key)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedisStreamTranslateQueue_Take_Claimed(t *testing.T) {
+	mock, queue := newRedisStreamMock()
+
+	want := &TranslateTask{Isid: "1", Filename: "filename", OriginalFileId: 1}
+	taskJson, _ := json.Marshal(want)
+
+	expectPromote(mock)
+	mock.ExpectXAutoClaim(autoClaimArgs()).SetVal(
+		[]redis.XMessage{{ID: "1-0", Values: map[string]any{"task": string(taskJson)}}},
+		"0-0",
+	)
+
+	got, key := queue.Take(context.Background())
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if key != "1-0" {
+		t.Fatalf("expected %v, got %v", "1-0", key)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedisStreamTranslateQueue_Take_Empty(t *testing.T) {
+	mock, queue := newRedisStreamMock()
+
+	expectPromote(mock)
+	mock.ExpectXAutoClaim(autoClaimArgs()).SetVal(nil, "0-0")
+	mock.ExpectXReadGroup(readGroupArgs()).RedisNil()
+
+	got, key := queue.Take(context.Background())
+	if got != nil {
+		t.Fatalf("expected nil, got %v", got)
+	}
+	if key != "" {
+		t.Fatalf("expected empty key, got %v", key)
+	}
+}
+
+func TestRedisStreamTranslateQueue_Take_Malformed(t *testing.T) {
+	mock, queue := newRedisStreamMock()
+
+	expectPromote(mock)
+	mock.ExpectXAutoClaim(autoClaimArgs()).SetVal(nil, "0-0")
+	mock.ExpectXReadGroup(readGroupArgs()).SetVal([]redis.XStream{
+		{
+			Stream:   "stream",
+			Messages: []redis.XMessage{{ID: "1-0", Values: map[string]any{"task": "{"}}},
+		},
+	})
+	mock.ExpectXAck("stream", "group", "1-0").SetVal(1)
+	mock.ExpectXDel("stream", "1-0").SetVal(1)
+
+	got, key := queue.Take(context.Background())
+	if got != nil {
+		t.Fatalf("expected nil, got %v", got)
+	}
+	if key != "" {
+		t.Fatalf("expected empty key, got %v", key)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedisStreamTranslateQueue_Delete(t *testing.T) {
+	mock, queue := newRedisStreamMock()
+
+	mock.ExpectXAck("stream", "group", "1-0").SetVal(1)
+	mock.ExpectXDel("stream", "1-0").SetVal(1)
+
+	if err := queue.Delete(context.Background(), "1-0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedisStreamTranslateQueue_Delete_Err(t *testing.T) {
+	mock, queue := newRedisStreamMock()
+
+	e := errors.New("xack error")
+	mock.ExpectXAck("stream", "group", "1-0").SetErr(e)
+
+	if err := queue.Delete(context.Background(), "1-0"); err != e {
+		t.Fatalf("expected %v, got %v", e, err)
+	}
+}
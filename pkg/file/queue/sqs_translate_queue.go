@@ -1,13 +1,23 @@
 package queue
 
 import (
+	"context"
 	"encoding/json"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 )
 
+// sqsMaxDelaySeconds is the longest SQS delays a message for.
+const sqsMaxDelaySeconds = 900
+
+// sqsMaxVisibilitySeconds is the longest SQS hides a received message for.
+const sqsMaxVisibilitySeconds = 12 * 60 * 60
+
 type SqsTranslateQueue struct {
 	client   sqsiface.SQSAPI
 	queueUrl string
@@ -18,17 +28,25 @@ func NewSqsTranslateQueue(client sqsiface.SQSAPI, queueUrl string, groupId strin
 	return &SqsTranslateQueue{client, queueUrl, groupId}
 }
 
-func (q *SqsTranslateQueue) Add(t *TranslateTask) error {
+func (q *SqsTranslateQueue) Add(ctx context.Context, t *TranslateTask) error {
 	taskJson, err := json.Marshal(t)
 	if err != nil {
 		return err
 	}
 
-	_, err = q.client.SendMessage(&sqs.SendMessageInput{
+	input := &sqs.SendMessageInput{
 		QueueUrl:       aws.String(q.queueUrl),
 		MessageBody:    aws.String(string(taskJson)),
 		MessageGroupId: aws.String(q.groupId),
-	})
+	}
+
+	// FIFO queues don't take a delay per message, their tasks are held back
+	// once taken instead
+	if delay := sqsSecondsUntil(t.NotBefore, sqsMaxDelaySeconds); delay > 0 && !strings.HasSuffix(q.queueUrl, ".fifo") {
+		input.DelaySeconds = aws.Int64(delay)
+	}
+
+	_, err = q.client.SendMessageWithContext(ctx, input)
 	if err != nil {
 		return err
 	}
@@ -36,13 +54,24 @@ func (q *SqsTranslateQueue) Add(t *TranslateTask) error {
 	return nil
 }
 
-func (q *SqsTranslateQueue) Take() (*TranslateTask, string) {
-	result, err := q.client.ReceiveMessage(&sqs.ReceiveMessageInput{
+// sqsSecondsUntil returns how long until notBefore, rounded up and capped at
+// max seconds.
+func sqsSecondsUntil(notBefore time.Time, max int64) int64 {
+	delay := time.Until(notBefore)
+	if delay <= 0 {
+		return 0
+	}
+
+	return min(int64(math.Ceil(delay.Seconds())), max)
+}
+
+func (q *SqsTranslateQueue) Take(ctx context.Context) (*TranslateTask, string) {
+	result, err := q.client.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
 		QueueUrl:            aws.String(q.queueUrl),
 		MaxNumberOfMessages: aws.Int64(1),
 		WaitTimeSeconds:     aws.Int64(2),
 	})
-	if err != nil {
+	if err != nil || len(result.Messages) == 0 {
 		return nil, ""
 	}
 
@@ -53,11 +82,23 @@ func (q *SqsTranslateQueue) Take() (*TranslateTask, string) {
 		return nil, ""
 	}
 
+	// Tasks delivered before they're due, those of FIFO queues or delayed past
+	// what SQS delays for, are hidden until then. On a FIFO queue this holds
+	// back the rest of their group too, as it would be while they're worked on.
+	if wait := sqsSecondsUntil(task.NotBefore, sqsMaxVisibilitySeconds); wait > 0 {
+		q.client.ChangeMessageVisibilityWithContext(ctx, &sqs.ChangeMessageVisibilityInput{
+			QueueUrl:          aws.String(q.queueUrl),
+			ReceiptHandle:     message.ReceiptHandle,
+			VisibilityTimeout: aws.Int64(wait),
+		})
+		return nil, ""
+	}
+
 	return task, *message.ReceiptHandle
 }
 
-func (q *SqsTranslateQueue) Delete(key string) error {
-	_, err := q.client.DeleteMessage(&sqs.DeleteMessageInput{
+func (q *SqsTranslateQueue) Delete(ctx context.Context, key string) error {
+	_, err := q.client.DeleteMessageWithContext(ctx, &sqs.DeleteMessageInput{
 		QueueUrl:      aws.String(q.queueUrl),
 		ReceiptHandle: aws.String(key),
 	})
@@ -1,11 +1,13 @@
 package queue
 
 import (
+	"context"
 	"doc-translate-go/mocks"
 	"encoding/json"
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sqs"
@@ -37,14 +39,50 @@ func TestSqsTranslateQueue_Add(t *testing.T) {
 		MessageGroupId: aws.String(queue.groupId),
 	}
 
-	mockedClient.EXPECT().SendMessage(gomock.Eq(message)).Times(1)
+	mockedClient.EXPECT().SendMessageWithContext(gomock.Any(), gomock.Eq(message)).Times(1)
 
-	err := queue.Add(task)
+	err := queue.Add(context.Background(), task)
 	if err != nil {
 		t.Fatal(err)
 	}
 }
 
+func TestSqsTranslateQueue_Add_NotBefore(t *testing.T) {
+	for queueUrl, delay := range map[string]*int64{"url": aws.Int64(60), "url.fifo": nil} {
+		mockedClient, queue := newMock(t, queueUrl, "id")
+
+		// Rounded up to the next second
+		task := &TranslateTask{Isid: "1", Filename: "filename", NotBefore: time.Now().Add(59*time.Second + 500*time.Millisecond)}
+
+		taskJson, _ := json.Marshal(task)
+		message := &sqs.SendMessageInput{
+			QueueUrl:       aws.String(queue.queueUrl),
+			MessageBody:    aws.String(string(taskJson)),
+			MessageGroupId: aws.String(queue.groupId),
+			DelaySeconds:   delay,
+		}
+
+		mockedClient.EXPECT().SendMessageWithContext(gomock.Any(), gomock.Eq(message)).Times(1)
+
+		if err := queue.Add(context.Background(), task); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSqsSecondsUntil(t *testing.T) {
+	for notBefore, want := range map[time.Time]int64{
+		{}:                               0,
+		time.Now().Add(-time.Minute):     0,
+		time.Now().Add(time.Hour):        900,
+		time.Now().Add(10 * time.Second): 10,
+	} {
+		if got := sqsSecondsUntil(notBefore, sqsMaxDelaySeconds); got != want {
+			t.Fatalf("%v: expected %v, got %v", notBefore, want, got)
+		}
+	}
+}
+
 func TestSqsTranslateQueue_Add_SendMessageErr(t *testing.T) {
 	mockedClient, queue := newMock(t, "url", "id")
 
@@ -64,9 +102,9 @@ func TestSqsTranslateQueue_Add_SendMessageErr(t *testing.T) {
 	}
 
 	e := errors.New("message error")
-	mockedClient.EXPECT().SendMessage(gomock.Eq(message)).Times(1).Return(nil, e)
+	mockedClient.EXPECT().SendMessageWithContext(gomock.Any(), gomock.Eq(message)).Times(1).Return(nil, e)
 
-	err := queue.Add(task)
+	err := queue.Add(context.Background(), task)
 	if err != e {
 		t.Fatalf("expected %v, got %v", e, err)
 	}
@@ -90,8 +128,8 @@ func TestSqsTranslateQueue_Take(t *testing.T) {
 		MessageGroupId: aws.String(queue.groupId),
 	}
 
-	mockedClient.EXPECT().SendMessage(gomock.Eq(wantSendMessageParam)).Times(1)
-	err := queue.Add(task)
+	mockedClient.EXPECT().SendMessageWithContext(gomock.Any(), gomock.Eq(wantSendMessageParam)).Times(1)
+	err := queue.Add(context.Background(), task)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -108,9 +146,9 @@ func TestSqsTranslateQueue_Take(t *testing.T) {
 		QueueUrl:            aws.String("url"),
 		WaitTimeSeconds:     aws.Int64(2),
 	}
-	mockedClient.EXPECT().ReceiveMessage(gomock.Eq(wantReceiveMessageParams)).Times(1).Return(output, nil)
+	mockedClient.EXPECT().ReceiveMessageWithContext(gomock.Any(), gomock.Eq(wantReceiveMessageParams)).Times(1).Return(output, nil)
 
-	got, key := queue.Take()
+	got, key := queue.Take(context.Background())
 	if !reflect.DeepEqual(task, got) {
 		t.Fatalf("expected %v, got %v", task, got)
 	}
@@ -119,6 +157,32 @@ func TestSqsTranslateQueue_Take(t *testing.T) {
 	}
 }
 
+func TestSqsTranslateQueue_Take_NotBefore(t *testing.T) {
+	mockedClient, queue := newMock(t, "url.fifo", "id")
+
+	// A FIFO queue delivers the retried task at once, it's hidden until due
+	task := &TranslateTask{Isid: "1", Filename: "filename", Attempts: 1, NotBefore: time.Now().Add(29*time.Second + 500*time.Millisecond)}
+	taskJson, _ := json.Marshal(task)
+
+	output := &sqs.ReceiveMessageOutput{
+		Messages: []*sqs.Message{
+			{Body: aws.String(string(taskJson)), ReceiptHandle: aws.String("1")},
+		},
+	}
+	mockedClient.EXPECT().ReceiveMessageWithContext(gomock.Any(), gomock.Any()).Times(1).Return(output, nil)
+
+	wantVisibilityParams := &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String("url.fifo"),
+		ReceiptHandle:     aws.String("1"),
+		VisibilityTimeout: aws.Int64(30),
+	}
+	mockedClient.EXPECT().ChangeMessageVisibilityWithContext(gomock.Any(), gomock.Eq(wantVisibilityParams)).Times(1)
+
+	if got, key := queue.Take(context.Background()); got != nil || key != "" {
+		t.Fatalf("expected no task, got %v, %v", got, key)
+	}
+}
+
 func TestSqsTranslateQueue_Take_RetrieveMessageErr(t *testing.T) {
 	mockedClient, queue := newMock(t, "url", "id")
 
@@ -137,8 +201,8 @@ func TestSqsTranslateQueue_Take_RetrieveMessageErr(t *testing.T) {
 		MessageGroupId: aws.String(queue.groupId),
 	}
 
-	mockedClient.EXPECT().SendMessage(gomock.Eq(wantSendMessageParam)).Times(1)
-	err := queue.Add(task)
+	mockedClient.EXPECT().SendMessageWithContext(gomock.Any(), gomock.Eq(wantSendMessageParam)).Times(1)
+	err := queue.Add(context.Background(), task)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -153,11 +217,35 @@ func TestSqsTranslateQueue_Take_RetrieveMessageErr(t *testing.T) {
 
 	mockedClient.
 		EXPECT().
-		ReceiveMessage(gomock.Eq(wantReceiveMessageParams)).
+		ReceiveMessageWithContext(gomock.Any(), gomock.Eq(wantReceiveMessageParams)).
 		Times(1).
 		Return(nil, e)
 
-	got, key := queue.Take()
+	got, key := queue.Take(context.Background())
+	if got != nil {
+		t.Fatalf("expected nil, got %v", got)
+	}
+	if key != "" {
+		t.Fatalf("expected empty key, got %v", key)
+	}
+}
+
+func TestSqsTranslateQueue_Take_Empty(t *testing.T) {
+	mockedClient, queue := newMock(t, "url", "id")
+
+	wantReceiveMessageParams := &sqs.ReceiveMessageInput{
+		MaxNumberOfMessages: aws.Int64(1),
+		QueueUrl:            aws.String("url"),
+		WaitTimeSeconds:     aws.Int64(2),
+	}
+
+	mockedClient.
+		EXPECT().
+		ReceiveMessageWithContext(gomock.Any(), gomock.Eq(wantReceiveMessageParams)).
+		Times(1).
+		Return(&sqs.ReceiveMessageOutput{}, nil)
+
+	got, key := queue.Take(context.Background())
 	if got != nil {
 		t.Fatalf("expected nil, got %v", got)
 	}
@@ -174,9 +262,9 @@ func TestSqsTranslateQueue_Delete(t *testing.T) {
 		ReceiptHandle: aws.String("1"),
 	}
 
-	mockedClient.EXPECT().DeleteMessage(gomock.Eq(wantDeleteMessageParam)).Times(1)
+	mockedClient.EXPECT().DeleteMessageWithContext(gomock.Any(), gomock.Eq(wantDeleteMessageParam)).Times(1)
 
-	err := queue.Delete("1")
+	err := queue.Delete(context.Background(), "1")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -191,9 +279,9 @@ func TestSqsTranslateQueue_Delete_DeleteMessageErr(t *testing.T) {
 	}
 
 	e := errors.New("error")
-	mockedClient.EXPECT().DeleteMessage(gomock.Eq(wantDeleteMessageParam)).Times(1).Return(nil, e)
+	mockedClient.EXPECT().DeleteMessageWithContext(gomock.Any(), gomock.Eq(wantDeleteMessageParam)).Times(1).Return(nil, e)
 
-	err := queue.Delete("1")
+	err := queue.Delete(context.Background(), "1")
 	if err != e {
 		t.Fatalf("expected %v, got %v", e, err)
 	}
@@ -1,15 +1,29 @@
 package queue
 
+import (
+	"context"
+	"time"
+)
+
 type TranslateTask struct {
 	Isid           string `json:"isid"`
 	Filename       string `json:"filename"`
 	SourceLang     string `json:"source_lang"`
 	TargetLang     string `json:"target_lang"`
 	OriginalFileId int    `json:"original_file_id"`
+	SHA256         string `json:"sha256"`
+	MimeType       string `json:"mime_type"`
+	// GlossaryId is the glossary to translate with, 0 for none
+	GlossaryId int `json:"glossary_id"`
+	Attempts   int `json:"attempts"`
+	// QueuedAt is when the file was first queued, retries keep it
+	QueuedAt time.Time `json:"queued_at"`
+	// NotBefore holds a retried task back, queues don't hand it out earlier
+	NotBefore time.Time `json:"not_before"`
 }
 
 type TranslateQueue interface {
-	Add(t *TranslateTask) error
-	Take() (*TranslateTask, string)
-	Delete(key string) error
+	Add(ctx context.Context, t *TranslateTask) error
+	Take(ctx context.Context) (*TranslateTask, string)
+	Delete(ctx context.Context, key string) error
 }
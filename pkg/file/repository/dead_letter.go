@@ -0,0 +1,12 @@
+package repository
+
+import "doc-translate-go/pkg/file/entity"
+
+// DeadLetterRepository operates against a database
+// or any data persistent layer.
+type DeadLetterRepository interface {
+	Create(d *entity.DeadLetter) (int, error)
+	List() ([]*entity.DeadLetter, error)
+	ListByIds(ids []int) ([]*entity.DeadLetter, error)
+	DeleteByIds(ids []int) error
+}
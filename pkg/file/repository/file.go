@@ -1,11 +1,13 @@
 package repository
 
+import "context"
+
 // FileRepository operates against a filesystem as it
 // needs to store and retrieve binary files.
 type FileRepository interface {
-	Persist(b []byte, filepath string) error
-	Get(filepath string) ([]byte, error)
-	Delete(filepath string) error
-	DeleteMany(filepaths []string) error
-	GetUrl(filepath string) (string, error)
+	Persist(ctx context.Context, b []byte, filepath string) error
+	Get(ctx context.Context, filepath string) ([]byte, error)
+	Delete(ctx context.Context, filepath string) error
+	DeleteMany(ctx context.Context, filepaths []string) error
+	GetUrl(ctx context.Context, filepath string) (string, error)
 }
@@ -0,0 +1,175 @@
+package filesystem
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"doc-translate-go/pkg/file/repository"
+)
+
+var (
+	ErrInvalidPath      = errors.New("invalid file path")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrExpiredUrl       = errors.New("url has expired")
+)
+
+// FilesystemFileRepository stores files under a root directory. Download urls
+// point at baseUrl and are signed with secret so they can be served without auth.
+type FilesystemFileRepository struct {
+	root      string
+	secret    []byte
+	baseUrl   string
+	urlExpiry time.Duration
+}
+
+func NewFilesystemFileRepository(root string, secret []byte, baseUrl string, urlExpiry time.Duration) *FilesystemFileRepository {
+	return &FilesystemFileRepository{root, secret, baseUrl, urlExpiry}
+}
+
+// resolve maps a slash separated key such as isid/filename to a path under root,
+// rejecting keys that would escape it.
+func (r *FilesystemFileRepository) resolve(key string) (string, error) {
+	p := filepath.FromSlash(key)
+	if !filepath.IsLocal(p) {
+		return "", ErrInvalidPath
+	}
+
+	return filepath.Join(r.root, p), nil
+}
+
+// Persist writes to a temporary file first and renames it into place,
+// so readers never see a partially written file.
+func (r *FilesystemFileRepository) Persist(ctx context.Context, b []byte, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	p, err := r.resolve(key)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(p)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), p)
+}
+
+func (r *FilesystemFileRepository) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p, err := r.resolve(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(p)
+}
+
+func (r *FilesystemFileRepository) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	p, err := r.resolve(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (r *FilesystemFileRepository) DeleteMany(ctx context.Context, keys []string) error {
+	var errs []error
+
+	for _, p := range keys {
+		errs = append(errs, r.Delete(ctx, p))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *FilesystemFileRepository) GetUrl(ctx context.Context, key string) (string, error) {
+	if _, err := r.resolve(key); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(r.urlExpiry).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("path", key)
+	query.Set("expires", expires)
+	query.Set("signature", r.sign(key, expires))
+
+	return fmt.Sprintf("%s?%s", r.baseUrl, query.Encode()), nil
+}
+
+// Verify checks a url generated by GetUrl has not been tampered with or expired.
+func (r *FilesystemFileRepository) Verify(key string, expires string, signature string) error {
+	want, err := hex.DecodeString(r.sign(key, expires))
+	if err != nil {
+		return err
+	}
+
+	got, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(want, got) {
+		return ErrInvalidSignature
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	if time.Now().Unix() > expiresAt {
+		return ErrExpiredUrl
+	}
+
+	return nil
+}
+
+func (r *FilesystemFileRepository) sign(key string, expires string) string {
+	mac := hmac.New(sha256.New, r.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Ensure implementation
+var _ repository.FileRepository = (*FilesystemFileRepository)(nil)
@@ -0,0 +1,123 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newRepo(t *testing.T, urlExpiry time.Duration) *FilesystemFileRepository {
+	return NewFilesystemFileRepository(t.TempDir(), []byte("secret"), "http://localhost:8080/files", urlExpiry)
+}
+
+func TestFilesystemFileRepository_PersistGet(t *testing.T) {
+	repo := newRepo(t, time.Minute)
+	want := []byte("content")
+
+	if err := repo.Persist(context.Background(), want, "isid/file.docx"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.Get(context.Background(), "isid/file.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	// Only the renamed file should be left behind
+	entries, err := os.ReadDir(filepath.Join(repo.root, "isid"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 file, got %v", len(entries))
+	}
+}
+
+func TestFilesystemFileRepository_PathTraversal(t *testing.T) {
+	repo := newRepo(t, time.Minute)
+
+	for _, key := range []string{"../file.docx", "isid/../../file.docx", "/etc/passwd", ""} {
+		if err := repo.Persist(context.Background(), []byte("content"), key); err != ErrInvalidPath {
+			t.Fatalf("%q: expected %v, got %v", key, ErrInvalidPath, err)
+		}
+
+		if _, err := repo.Get(context.Background(), key); err != ErrInvalidPath {
+			t.Fatalf("%q: expected %v, got %v", key, ErrInvalidPath, err)
+		}
+	}
+}
+
+func TestFilesystemFileRepository_DeleteMany(t *testing.T) {
+	repo := newRepo(t, time.Minute)
+	keys := []string{"isid/a.docx", "isid/b.docx"}
+
+	for _, key := range keys {
+		if err := repo.Persist(context.Background(), []byte("content"), key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Missing files are not an error, same as deleting a missing S3 object
+	if err := repo.DeleteMany(context.Background(), append(keys, "isid/missing.docx")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range keys {
+		if _, err := repo.Get(context.Background(), key); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("%v: expected %v, got %v", key, os.ErrNotExist, err)
+		}
+	}
+}
+
+func TestFilesystemFileRepository_GetUrl(t *testing.T) {
+	repo := newRepo(t, time.Minute)
+
+	u, err := repo.GetUrl(context.Background(), "isid/file name.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := parsed.Query()
+	if q.Get("path") != "isid/file name.docx" {
+		t.Fatalf("expected %v, got %v", "isid/file name.docx", q.Get("path"))
+	}
+
+	if err := repo.Verify(q.Get("path"), q.Get("expires"), q.Get("signature")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.Verify("isid/other.docx", q.Get("expires"), q.Get("signature")); err != ErrInvalidSignature {
+		t.Fatalf("expected %v, got %v", ErrInvalidSignature, err)
+	}
+}
+
+func TestFilesystemFileRepository_GetUrl_Expired(t *testing.T) {
+	repo := newRepo(t, -time.Minute)
+
+	u, err := repo.GetUrl(context.Background(), "isid/file.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, _ := url.Parse(u)
+	q := parsed.Query()
+
+	if err := repo.Verify(q.Get("path"), q.Get("expires"), q.Get("signature")); err != ErrExpiredUrl {
+		t.Fatalf("expected %v, got %v", ErrExpiredUrl, err)
+	}
+}
@@ -0,0 +1,101 @@
+package postgresql
+
+import (
+	"doc-translate-go/pkg/db"
+	"doc-translate-go/pkg/file/entity"
+	"doc-translate-go/pkg/file/repository"
+	"fmt"
+	"strings"
+)
+
+type PostgresqlDeadLetterRepository struct {
+	querier db.Querier
+}
+
+func NewPostgresqlDeadLetterRepository(querier db.Querier) *PostgresqlDeadLetterRepository {
+	return &PostgresqlDeadLetterRepository{querier}
+}
+
+func (r *PostgresqlDeadLetterRepository) Create(d *entity.DeadLetter) (int, error) {
+	cmd := `INSERT INTO dead_letters (task, stage, error, attempts, created_at, created_by)
+                VALUES ($1, $2, $3, $4, $5, $6)
+                RETURNING id;`
+
+	row := r.querier.QueryRow(cmd, d.Task, d.Stage, d.Error, d.Attempts, d.CreatedAt, d.CreatedBy)
+
+	var id int
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *PostgresqlDeadLetterRepository) List() ([]*entity.DeadLetter, error) {
+	cmd := `SELECT id, task, stage, error, attempts, created_at, created_by
+        FROM dead_letters
+        ORDER BY id;`
+
+	return r.list(cmd)
+}
+
+func (r *PostgresqlDeadLetterRepository) ListByIds(ids []int) ([]*entity.DeadLetter, error) {
+	arg_placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+
+	for i, id := range ids {
+		arg_placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+
+	cmd := fmt.Sprintf(`
+                SELECT id, task, stage, error, attempts, created_at, created_by
+                FROM dead_letters
+                WHERE id IN (%s);`,
+		strings.Join(arg_placeholders, ", "),
+	)
+
+	return r.list(cmd, args...)
+}
+
+func (r *PostgresqlDeadLetterRepository) list(cmd string, args ...any) ([]*entity.DeadLetter, error) {
+	var out []*entity.DeadLetter
+
+	rows, err := r.querier.Query(cmd, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d entity.DeadLetter
+		if err := rows.Scan(&d.Id, &d.Task, &d.Stage, &d.Error, &d.Attempts, &d.CreatedAt, &d.CreatedBy); err != nil {
+			return nil, err
+		}
+		out = append(out, &d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (r *PostgresqlDeadLetterRepository) DeleteByIds(ids []int) error {
+	arg_placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+
+	for i, id := range ids {
+		arg_placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+
+	cmd := fmt.Sprintf(`DELETE FROM dead_letters WHERE id IN (%s);`, strings.Join(arg_placeholders, ", "))
+
+	_, err := r.querier.Exec(cmd, args...)
+	return err
+}
+
+// Ensure implementation
+var _ repository.DeadLetterRepository = (*PostgresqlDeadLetterRepository)(nil)
@@ -0,0 +1,118 @@
+package postgresql
+
+import (
+	"doc-translate-go/pkg/file/entity"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func newDeadLetterMock(t *testing.T) (sqlmock.Sqlmock, *PostgresqlDeadLetterRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewPostgresqlDeadLetterRepository(db)
+
+	return mock, repo
+}
+
+func TestPostgresqlDeadLetterRepository_Create(t *testing.T) {
+	mock, repo := newDeadLetterMock(t)
+	ent := &entity.DeadLetter{Id: 1, Task: "{}", Stage: "translate", Attempts: 3}
+
+	rows := sqlmock.NewRows([]string{"id"}).AddRow(ent.Id)
+
+	cmd := `INSERT INTO dead_letters \(task, stage, error, attempts, created_at, created_by\)
+                VALUES \(\$1, \$2, \$3, \$4, \$5, \$6\)
+                RETURNING id;`
+
+	mock.ExpectQuery(cmd).WillReturnRows(rows)
+
+	got, err := repo.Create(ent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != ent.Id {
+		t.Fatalf("expected %v, got %v", ent.Id, got)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlDeadLetterRepository_List(t *testing.T) {
+	mock, repo := newDeadLetterMock(t)
+
+	want := []*entity.DeadLetter{{Id: 1, Task: "{}"}, {Id: 2, Task: "{}"}}
+
+	columns := []string{"id", "task", "stage", "error", "attempts", "created_at", "created_by"}
+	rows := sqlmock.NewRows(columns)
+	for _, d := range want {
+		rows.AddRow(d.Id, d.Task, d.Stage, d.Error, d.Attempts, d.CreatedAt, d.CreatedBy)
+	}
+
+	cmd := `SELECT id, task, stage, error, attempts, created_at, created_by
+        FROM dead_letters
+        ORDER BY id;`
+
+	mock.ExpectQuery(cmd).WillReturnRows(rows)
+
+	got, err := repo.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlDeadLetterRepository_ListByIds_Err(t *testing.T) {
+	mock, repo := newDeadLetterMock(t)
+
+	cmd := `SELECT id, task, stage, error, attempts, created_at, created_by
+                FROM dead_letters
+                WHERE id IN \([$0-9, ]+\);`
+
+	e := errors.New("list error")
+	mock.ExpectQuery(cmd).WillReturnError(e)
+
+	got, err := repo.ListByIds([]int{1, 2})
+	if err != e {
+		t.Fatalf("expected %v, got %v", e, err)
+	}
+
+	if got != nil {
+		t.Fatalf("expected nil, got %v", got)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlDeadLetterRepository_DeleteByIds(t *testing.T) {
+	mock, repo := newDeadLetterMock(t)
+
+	mock.ExpectExec(`DELETE FROM dead_letters WHERE id IN \(\$1, \$2\);`).
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	if err := repo.DeleteByIds([]int{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
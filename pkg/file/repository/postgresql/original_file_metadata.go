@@ -103,7 +103,7 @@ func (r *PostgresqlOriginalFileMetadataRepository) ListByFilenameIsid(filename s
 
 	cmd := `SELECT id, sha256, filename, file_type, file_size, source_language, token_count, created_at, updated_at, created_by
         FROM original_files
-        WHERE file_name = $1 AND created_by = $2;`
+        WHERE filename = $1 AND created_by = $2;`
 
 	rows, err := r.querier.Query(cmd, filename, isid)
 	if err != nil {
@@ -208,7 +208,7 @@ func TestPostgresqlOriginalFileMetadataRepository_ListByFilenameIsid(t *testing.
 
 	cmd := `SELECT id, sha256, filename, file_type, file_size, source_language, token_count, created_at, updated_at, created_by
         FROM original_files
-        WHERE file_name = \$1 AND created_by = \$2;`
+        WHERE filename = \$1 AND created_by = \$2;`
 
 	mock.ExpectQuery(cmd).WillReturnRows(rows)
 
@@ -234,7 +234,7 @@ func TestPostgresqlOriginalFileMetadataRepository_ListByFilenameIsid_Err(t *test
 
 	cmd := `SELECT id, sha256, filename, file_type, file_size, source_language, token_count, created_at, updated_at, created_by
         FROM original_files
-        WHERE file_name = \$1 AND created_by = \$2;`
+        WHERE filename = \$1 AND created_by = \$2;`
 
 	e := errors.New("list error")
 	mock.ExpectQuery(cmd).WillReturnRows(rows).WillReturnError(e)
@@ -1,9 +1,12 @@
 package postgresql
 
 import (
+	"database/sql"
 	"doc-translate-go/pkg/db"
 	"doc-translate-go/pkg/file/entity"
 	"doc-translate-go/pkg/file/repository"
+	glossaryEntity "doc-translate-go/pkg/glossary/entity"
+	"encoding/json"
 	"fmt"
 	"strings"
 )
@@ -17,14 +20,19 @@ func NewPostgresqlTranslatedFileMetadataRepository(querier db.Querier) *Postgres
 }
 
 func (r *PostgresqlTranslatedFileMetadataRepository) Create(f *entity.TranslatedFileMetadata) (int, error) {
-	cmd := `INSERT INTO translated_files (original_files_id, translated_filename, target_language, cost, time_taken, created_at, updated_at, created_by)
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	flags, err := marshalFlags(f.TermFlags)
+	if err != nil {
+		return 0, err
+	}
+
+	cmd := `INSERT INTO translated_files (original_files_id, translated_filename, target_language, cost, time_taken, queue_time, billed_characters, billed_tokens, glossary_id, term_flags, memory_hits, memory_fuzzy_hits, memory_misses, created_at, updated_at, created_by)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, 0), $10, $11, $12, $13, $14, $15, $16)
         RETURNING id;`
 
-	row := r.querier.QueryRow(cmd, f.OriginalFileId, f.Filename, f.TargetLanguage, f.Cost, f.TimeTaken, f.CreatedAt, f.UpdatedAt, f.CreatedBy)
+	row := r.querier.QueryRow(cmd, f.OriginalFileId, f.Filename, f.TargetLanguage, f.Cost, f.TimeTaken, f.QueueTime, f.BilledCharacters, f.BilledTokens, f.GlossaryId, flags, f.MemoryHits, f.MemoryFuzzyHits, f.MemoryMisses, f.CreatedAt, f.UpdatedAt, f.CreatedBy)
 
 	var id int
-	err := row.Scan(&id)
+	err = row.Scan(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -44,7 +52,7 @@ func (r *PostgresqlTranslatedFileMetadataRepository) ListByIds(ids []int) ([]*en
 	}
 
 	cmd := fmt.Sprintf(`
-                SELECT id, original_files_id, translated_filename, target_language, cost, time_taken, created_at, updated_at, created_by
+                SELECT id, original_files_id, translated_filename, target_language, cost, time_taken, queue_time, billed_characters, billed_tokens, COALESCE(glossary_id, 0), term_flags, memory_hits, memory_fuzzy_hits, memory_misses, created_at, updated_at, created_by
                 FROM translated_files
                 WHERE id IN (%s);`,
 		strings.Join(arg_placeholders, ", "),
@@ -57,11 +65,11 @@ func (r *PostgresqlTranslatedFileMetadataRepository) ListByIds(ids []int) ([]*en
 	defer rows.Close()
 
 	for rows.Next() {
-		var f entity.TranslatedFileMetadata
-		if err := rows.Scan(&f.Id, &f.OriginalFileId, &f.Filename, &f.TargetLanguage, &f.Cost, &f.TimeTaken, &f.CreatedAt, &f.UpdatedAt, &f.CreatedBy); err != nil {
+		f, err := scanTranslatedFile(rows)
+		if err != nil {
 			return nil, err
 		}
-		out = append(out, &f)
+		out = append(out, f)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -71,10 +79,31 @@ func (r *PostgresqlTranslatedFileMetadataRepository) ListByIds(ids []int) ([]*en
 	return out, nil
 }
 
+func (r *PostgresqlTranslatedFileMetadataRepository) FindBySHA256(sha256 string, sourceLang string, targetLang string, isid string) (*entity.TranslatedFileMetadata, error) {
+	cmd := `SELECT t.id, t.original_files_id, t.translated_filename, t.target_language, t.cost, t.time_taken, t.queue_time, t.billed_characters, t.billed_tokens, COALESCE(t.glossary_id, 0), t.term_flags, t.memory_hits, t.memory_fuzzy_hits, t.memory_misses, t.created_at, t.updated_at, t.created_by
+                FROM translated_files t
+                JOIN original_files o ON o.id = t.original_files_id
+                WHERE o.sha256 = $1 AND o.source_language = $2 AND t.target_language = $3 AND ($4 = '' OR t.created_by = $4) AND t.glossary_id IS NULL
+                ORDER BY t.created_at DESC
+                LIMIT 1;`
+
+	row := r.querier.QueryRow(cmd, sha256, sourceLang, targetLang, isid)
+
+	f, err := scanTranslatedFile(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
 func (r *PostgresqlTranslatedFileMetadataRepository) ListByIsid(isid string) ([]*entity.TranslatedFileMetadata, error) {
 	var out []*entity.TranslatedFileMetadata
 
-	cmd := `SELECT id, original_files_id, translated_filename, target_language, cost, time_taken, created_by, updated_at, created_by
+	cmd := `SELECT id, original_files_id, translated_filename, target_language, cost, time_taken, queue_time, billed_characters, billed_tokens, COALESCE(glossary_id, 0), term_flags, memory_hits, memory_fuzzy_hits, memory_misses, created_at, updated_at, created_by
                 FROM translated_files
                 WHERE created_by = $1;`
 
@@ -85,11 +114,11 @@ func (r *PostgresqlTranslatedFileMetadataRepository) ListByIsid(isid string) ([]
 	defer rows.Close()
 
 	for rows.Next() {
-		var f entity.TranslatedFileMetadata
-		if err := rows.Scan(&f.Id, &f.OriginalFileId, &f.Filename, &f.TargetLanguage, &f.Cost, &f.TimeTaken, &f.CreatedAt, &f.UpdatedAt, &f.CreatedBy); err != nil {
+		f, err := scanTranslatedFile(rows)
+		if err != nil {
 			return nil, err
 		}
-		out = append(out, &f)
+		out = append(out, f)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -137,18 +166,31 @@ func (r *PostgresqlTranslatedFileMetadataRepository) ListOriginalFileIdsByIds(id
 }
 
 func (r *PostgresqlTranslatedFileMetadataRepository) Update(f *entity.TranslatedFileMetadata) error {
+	flags, err := marshalFlags(f.TermFlags)
+	if err != nil {
+		return err
+	}
+
 	cmd := `UPDATE translated_files SET
                 original_files_id = $1,
                 translated_filename = $2,
                 target_language = $3,
                 cost = $4,
                 time_taken = $5,
-                created_at = $6,
-                updated_at = $7,
-                created_by = $8
-        WHERE id = $9;`
-
-	_, err := r.querier.Exec(cmd, f.OriginalFileId, f.Filename, f.TargetLanguage, f.Cost, f.TimeTaken, f.CreatedAt, f.UpdatedAt, f.CreatedBy, f.Id)
+                queue_time = $6,
+                billed_characters = $7,
+                billed_tokens = $8,
+                glossary_id = NULLIF($9, 0),
+                term_flags = $10,
+                memory_hits = $11,
+                memory_fuzzy_hits = $12,
+                memory_misses = $13,
+                created_at = $14,
+                updated_at = $15,
+                created_by = $16
+        WHERE id = $17;`
+
+	_, err = r.querier.Exec(cmd, f.OriginalFileId, f.Filename, f.TargetLanguage, f.Cost, f.TimeTaken, f.QueueTime, f.BilledCharacters, f.BilledTokens, f.GlossaryId, flags, f.MemoryHits, f.MemoryFuzzyHits, f.MemoryMisses, f.CreatedAt, f.UpdatedAt, f.CreatedBy, f.Id)
 
 	return err
 }
@@ -177,5 +219,29 @@ func (r *PostgresqlTranslatedFileMetadataRepository) DeleteByIds(ids []int) erro
 	return err
 }
 
+func scanTranslatedFile(row interface{ Scan(dest ...any) error }) (*entity.TranslatedFileMetadata, error) {
+	var f entity.TranslatedFileMetadata
+	var flags []byte
+	if err := row.Scan(&f.Id, &f.OriginalFileId, &f.Filename, &f.TargetLanguage, &f.Cost, &f.TimeTaken, &f.QueueTime, &f.BilledCharacters, &f.BilledTokens, &f.GlossaryId, &flags, &f.MemoryHits, &f.MemoryFuzzyHits, &f.MemoryMisses, &f.CreatedAt, &f.UpdatedAt, &f.CreatedBy); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(flags, &f.TermFlags); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// marshalFlags stores no flags as an empty array rather than null.
+func marshalFlags(flags []glossaryEntity.Flag) (string, error) {
+	if flags == nil {
+		flags = []glossaryEntity.Flag{}
+	}
+
+	b, err := json.Marshal(flags)
+	return string(b), err
+}
+
 // Ensure implementation
 var _ repository.TranslatedFileMetadataRepository = (*PostgresqlTranslatedFileMetadataRepository)(nil)
@@ -1,7 +1,9 @@
 package postgresql
 
 import (
+	"database/sql"
 	"doc-translate-go/pkg/file/entity"
+	glossaryEntity "doc-translate-go/pkg/glossary/entity"
 	"errors"
 	"reflect"
 	"testing"
@@ -27,11 +29,14 @@ func TestPostgresqlTranslatedFileMetadataRepository_Create(t *testing.T) {
 	rows := sqlmock.NewRows([]string{"id"}).
 		AddRow(ent.Id)
 
-	cmd := `INSERT INTO translated_files \(original_files_id, translated_filename, target_language, cost, time_taken, created_at, updated_at, created_by\)
-        VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8\)
+	cmd := `INSERT INTO translated_files \(original_files_id, translated_filename, target_language, cost, time_taken, queue_time, billed_characters, billed_tokens, glossary_id, term_flags, memory_hits, memory_fuzzy_hits, memory_misses, created_at, updated_at, created_by\)
+        VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, NULLIF\(\$9, 0\), \$10, \$11, \$12, \$13, \$14, \$15, \$16\)
         RETURNING id;`
 
-	mock.ExpectQuery(cmd).WillReturnRows(rows)
+	// No flags are stored as an empty array, unknown memory counts as null
+	mock.ExpectQuery(cmd).
+		WithArgs(0, "", "", 0.0, 0, 0, 0, 0, 0, "[]", nil, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg(), "").
+		WillReturnRows(rows)
 
 	got, err := repo.Create(ent)
 	if err != nil {
@@ -51,8 +56,8 @@ func TestPostgresqlTranslatedFileMetadataRepository_Create_Err(t *testing.T) {
 	mock, repo := newTranslMock(t)
 	ent := &entity.TranslatedFileMetadata{}
 
-	cmd := `INSERT INTO translated_files \(original_files_id, translated_filename, target_language, cost, time_taken, created_at, updated_at, created_by\)
-        VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8\)
+	cmd := `INSERT INTO translated_files \(original_files_id, translated_filename, target_language, cost, time_taken, queue_time, billed_characters, billed_tokens, glossary_id, term_flags, memory_hits, memory_fuzzy_hits, memory_misses, created_at, updated_at, created_by\)
+        VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, NULLIF\(\$9, 0\), \$10, \$11, \$12, \$13, \$14, \$15, \$16\)
         RETURNING id;`
 
 	e := errors.New("create err")
@@ -75,15 +80,15 @@ func TestPostgresqlTranslatedFileMetadataRepository_Create_Err(t *testing.T) {
 func TestPostgresqlTranslatedFileMetadataRepository_ListByIds(t *testing.T) {
 	mock, repo := newTranslMock(t)
 
-	want := []*entity.TranslatedFileMetadata{{Id: 1}, {Id: 2}}
+	want := []*entity.TranslatedFileMetadata{{Id: 1, TermFlags: []glossaryEntity.Flag{}}, {Id: 2, TermFlags: []glossaryEntity.Flag{}}}
 
-	columns := []string{"id", "original_files_id", "translated_filename", "target_language", "cost", "time_taken", "created_at", "updated_at", "created_by"}
+	columns := []string{"id", "original_files_id", "translated_filename", "target_language", "cost", "time_taken", "queue_time", "billed_characters", "billed_tokens", "glossary_id", "term_flags", "memory_hits", "memory_fuzzy_hits", "memory_misses", "created_at", "updated_at", "created_by"}
 	rows := sqlmock.NewRows(columns)
 	for _, r := range want {
-		rows.AddRow(r.Id, r.OriginalFileId, r.Filename, r.TargetLanguage, r.Cost, r.TimeTaken, r.CreatedAt, r.UpdatedAt, r.CreatedBy)
+		rows.AddRow(r.Id, r.OriginalFileId, r.Filename, r.TargetLanguage, r.Cost, r.TimeTaken, r.QueueTime, r.BilledCharacters, r.BilledTokens, r.GlossaryId, "[]", r.MemoryHits, r.MemoryFuzzyHits, r.MemoryMisses, r.CreatedAt, r.UpdatedAt, r.CreatedBy)
 	}
 
-	cmd := `SELECT id, original_files_id, translated_filename, target_language, cost, time_taken, created_at, updated_at, created_by
+	cmd := `SELECT id, original_files_id, translated_filename, target_language, cost, time_taken, queue_time, billed_characters, billed_tokens, COALESCE\(glossary_id, 0\), term_flags, memory_hits, memory_fuzzy_hits, memory_misses, created_at, updated_at, created_by
                 FROM translated_files
                 WHERE id IN \([$\d, ]+\);`
 
@@ -106,7 +111,7 @@ func TestPostgresqlTranslatedFileMetadataRepository_ListByIds(t *testing.T) {
 func TestPostgresqlTranslatedFileMetadataRepository_ListByIds_Err(t *testing.T) {
 	mock, repo := newTranslMock(t)
 
-	cmd := `SELECT id, original_files_id, translated_filename, target_language, cost, time_taken, created_at, updated_at, created_by
+	cmd := `SELECT id, original_files_id, translated_filename, target_language, cost, time_taken, queue_time, billed_characters, billed_tokens, COALESCE\(glossary_id, 0\), term_flags, memory_hits, memory_fuzzy_hits, memory_misses, created_at, updated_at, created_by
                 FROM translated_files
                 WHERE id IN \([$\d, ]+\);`
 
@@ -130,15 +135,15 @@ func TestPostgresqlTranslatedFileMetadataRepository_ListByIds_Err(t *testing.T)
 func TestPostgresqlTranslatedFileMetadataRepository_ListByIsid(t *testing.T) {
 	mock, repo := newTranslMock(t)
 
-	want := []*entity.TranslatedFileMetadata{{Id: 1, CreatedBy: "1"}, {Id: 2, CreatedBy: "1"}}
+	want := []*entity.TranslatedFileMetadata{{Id: 1, TermFlags: []glossaryEntity.Flag{}, CreatedBy: "1"}, {Id: 2, TermFlags: []glossaryEntity.Flag{}, CreatedBy: "1"}}
 
-	columns := []string{"id", "original_files_id", "translated_filename", "target_language", "cost", "time_taken", "created_at", "updated_at", "created_by"}
+	columns := []string{"id", "original_files_id", "translated_filename", "target_language", "cost", "time_taken", "queue_time", "billed_characters", "billed_tokens", "glossary_id", "term_flags", "memory_hits", "memory_fuzzy_hits", "memory_misses", "created_at", "updated_at", "created_by"}
 	rows := sqlmock.NewRows(columns)
 	for _, r := range want {
-		rows.AddRow(r.Id, r.OriginalFileId, r.Filename, r.TargetLanguage, r.Cost, r.TimeTaken, r.CreatedAt, r.UpdatedAt, r.CreatedBy)
+		rows.AddRow(r.Id, r.OriginalFileId, r.Filename, r.TargetLanguage, r.Cost, r.TimeTaken, r.QueueTime, r.BilledCharacters, r.BilledTokens, r.GlossaryId, "[]", r.MemoryHits, r.MemoryFuzzyHits, r.MemoryMisses, r.CreatedAt, r.UpdatedAt, r.CreatedBy)
 	}
 
-	cmd := `SELECT id, original_files_id, translated_filename, target_language, cost, time_taken, created_by, updated_at, created_by
+	cmd := `SELECT id, original_files_id, translated_filename, target_language, cost, time_taken, queue_time, billed_characters, billed_tokens, COALESCE\(glossary_id, 0\), term_flags, memory_hits, memory_fuzzy_hits, memory_misses, created_at, updated_at, created_by
                 FROM translated_files
                 WHERE created_by = \$1;`
 
@@ -161,7 +166,7 @@ func TestPostgresqlTranslatedFileMetadataRepository_ListByIsid(t *testing.T) {
 func TestPostgresqlTranslatedFileMetadataRepository_ListByIsid_Err(t *testing.T) {
 	mock, repo := newTranslMock(t)
 
-	cmd := `SELECT id, original_files_id, translated_filename, target_language, cost, time_taken, created_by, updated_at, created_by
+	cmd := `SELECT id, original_files_id, translated_filename, target_language, cost, time_taken, queue_time, billed_characters, billed_tokens, COALESCE\(glossary_id, 0\), term_flags, memory_hits, memory_fuzzy_hits, memory_misses, created_at, updated_at, created_by
                 FROM translated_files
                 WHERE created_by = \$1;`
 
@@ -243,10 +248,18 @@ func TestPostgresqlTranslatedFileMetadataRepository_Update(t *testing.T) {
                 target_language = \$3,
                 cost = \$4,
                 time_taken = \$5,
-                created_at = \$6,
-                updated_at = \$7,
-                created_by = \$8
-        WHERE id = \$9;`
+                queue_time = \$6,
+                billed_characters = \$7,
+                billed_tokens = \$8,
+                glossary_id = NULLIF\(\$9, 0\),
+                term_flags = \$10,
+                memory_hits = \$11,
+                memory_fuzzy_hits = \$12,
+                memory_misses = \$13,
+                created_at = \$14,
+                updated_at = \$15,
+                created_by = \$16
+        WHERE id = \$17;`
 
 	result := sqlmock.NewResult(1, 1)
 	mock.ExpectExec(cmd).WillReturnResult(result)
@@ -272,10 +285,18 @@ func TestPostgresqlTranslatedFileMetadataRepository_Update_Err(t *testing.T) {
                 target_language = \$3,
                 cost = \$4,
                 time_taken = \$5,
-                created_at = \$6,
-                updated_at = \$7,
-                created_by = \$8
-        WHERE id = \$9;`
+                queue_time = \$6,
+                billed_characters = \$7,
+                billed_tokens = \$8,
+                glossary_id = NULLIF\(\$9, 0\),
+                term_flags = \$10,
+                memory_hits = \$11,
+                memory_fuzzy_hits = \$12,
+                memory_misses = \$13,
+                created_at = \$14,
+                updated_at = \$15,
+                created_by = \$16
+        WHERE id = \$17;`
 
 	e := errors.New("update err")
 	mock.ExpectExec(cmd).WillReturnResult(nil).WillReturnError(e)
@@ -361,3 +382,57 @@ func TestPostgresqlTranslatedFileMetadataRepository_DeleteByIds_Err(t *testing.T
 		t.Fatal(t)
 	}
 }
+
+func TestPostgresqlTranslatedFileMetadataRepository_FindBySHA256(t *testing.T) {
+	mock, repo := newTranslMock(t)
+	want := &entity.TranslatedFileMetadata{Id: 1, OriginalFileId: 2, Filename: "translated-en-to-vi-file.docx", TargetLanguage: "vi", TermFlags: []glossaryEntity.Flag{}, MemoryHits: newInt(5), MemoryFuzzyHits: newInt(2), MemoryMisses: newInt(1), CreatedBy: "isid"}
+
+	columns := []string{"id", "original_files_id", "translated_filename", "target_language", "cost", "time_taken", "queue_time", "billed_characters", "billed_tokens", "glossary_id", "term_flags", "memory_hits", "memory_fuzzy_hits", "memory_misses", "created_at", "updated_at", "created_by"}
+	rows := sqlmock.NewRows(columns).
+		AddRow(want.Id, want.OriginalFileId, want.Filename, want.TargetLanguage, want.Cost, want.TimeTaken, want.QueueTime, want.BilledCharacters, want.BilledTokens, want.GlossaryId, "[]", 5, 2, 1, want.CreatedAt, want.UpdatedAt, want.CreatedBy)
+
+	cmd := `SELECT t.id, t.original_files_id, t.translated_filename, t.target_language, t.cost, t.time_taken, t.queue_time, t.billed_characters, t.billed_tokens, COALESCE\(t.glossary_id, 0\), t.term_flags, t.memory_hits, t.memory_fuzzy_hits, t.memory_misses, t.created_at, t.updated_at, t.created_by
+                FROM translated_files t
+                JOIN original_files o ON o.id = t.original_files_id
+                WHERE o.sha256 = \$1 AND o.source_language = \$2 AND t.target_language = \$3 AND \(\$4 = '' OR t.created_by = \$4\) AND t.glossary_id IS NULL
+                ORDER BY t.created_at DESC
+                LIMIT 1;`
+
+	mock.ExpectQuery(cmd).WithArgs("abc", "en", "vi", "isid").WillReturnRows(rows)
+
+	got, err := repo.FindBySHA256("abc", "en", "vi", "isid")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlTranslatedFileMetadataRepository_FindBySHA256_NotFound(t *testing.T) {
+	mock, repo := newTranslMock(t)
+
+	mock.ExpectQuery(`SELECT (.+) FROM translated_files t`).WithArgs("abc", "en", "vi", "").WillReturnError(sql.ErrNoRows)
+
+	got, err := repo.FindBySHA256("abc", "en", "vi", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != nil {
+		t.Fatalf("expected nil, got %v", got)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newInt(n int) *int {
+	return &n
+}
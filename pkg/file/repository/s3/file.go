@@ -2,6 +2,7 @@ package s3
 
 import (
 	"bytes"
+	"context"
 	"doc-translate-go/pkg/file/repository"
 	"time"
 
@@ -23,8 +24,8 @@ func NewS3FileRepository(uploader s3manageriface.UploaderAPI, downloader s3manag
 	return &S3FileRepository{uploader, downloader, service, bucketName}
 }
 
-func (r *S3FileRepository) Persist(b []byte, filepath string) error {
-	_, err := r.uploader.Upload(&s3manager.UploadInput{
+func (r *S3FileRepository) Persist(ctx context.Context, b []byte, filepath string) error {
+	_, err := r.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
 		Bucket: aws.String(r.bucketName),
 		Key:    aws.String(filepath),
 		Body:   bytes.NewReader(b),
@@ -36,10 +37,11 @@ func (r *S3FileRepository) Persist(b []byte, filepath string) error {
 	return nil
 }
 
-func (r *S3FileRepository) Get(filepath string) ([]byte, error) {
+func (r *S3FileRepository) Get(ctx context.Context, filepath string) ([]byte, error) {
 	buffer := aws.WriteAtBuffer{}
 
-	_, err := r.downloader.Download(
+	_, err := r.downloader.DownloadWithContext(
+		ctx,
 		&buffer,
 		&s3.GetObjectInput{
 			Bucket: aws.String(r.bucketName),
@@ -53,8 +55,8 @@ func (r *S3FileRepository) Get(filepath string) ([]byte, error) {
 	return buffer.Bytes(), nil
 }
 
-func (r *S3FileRepository) Delete(filepath string) error {
-	_, err := r.service.DeleteObject(&s3.DeleteObjectInput{
+func (r *S3FileRepository) Delete(ctx context.Context, filepath string) error {
+	_, err := r.service.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(r.bucketName),
 		Key:    aws.String(filepath),
 	})
@@ -62,7 +64,7 @@ func (r *S3FileRepository) Delete(filepath string) error {
 		return err
 	}
 
-	err = r.service.WaitUntilObjectNotExists(&s3.HeadObjectInput{
+	err = r.service.WaitUntilObjectNotExistsWithContext(ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(r.bucketName),
 		Key:    aws.String(filepath),
 	})
@@ -73,14 +75,14 @@ func (r *S3FileRepository) Delete(filepath string) error {
 	return nil
 }
 
-func (r *S3FileRepository) DeleteMany(filepaths []string) error {
+func (r *S3FileRepository) DeleteMany(ctx context.Context, filepaths []string) error {
 	var objects []*s3.ObjectIdentifier
 
 	for _, key := range filepaths {
 		objects = append(objects, &s3.ObjectIdentifier{Key: aws.String(key)})
 	}
 
-	_, err := r.service.DeleteObjects(&s3.DeleteObjectsInput{
+	_, err := r.service.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
 		Bucket: &r.bucketName,
 		Delete: &s3.Delete{
 			Objects: objects,
@@ -94,7 +96,7 @@ func (r *S3FileRepository) DeleteMany(filepaths []string) error {
 	return nil
 }
 
-func (r *S3FileRepository) GetUrl(filepath string) (string, error) {
+func (r *S3FileRepository) GetUrl(ctx context.Context, filepath string) (string, error) {
 	return r.generatePresignedUrl(filepath, 15*time.Minute)
 }
 
@@ -2,6 +2,7 @@ package s3
 
 import (
 	"bytes"
+	"context"
 	"doc-translate-go/mocks"
 	"errors"
 	"io"
@@ -30,9 +31,9 @@ func TestS3FileRepository_Persist(t *testing.T) {
 		Body:   bytes.NewReader(dat),
 	}
 
-	mockedUploader.EXPECT().Upload(gomock.Eq(input)).Times(1)
+	mockedUploader.EXPECT().UploadWithContext(gomock.Any(), gomock.Eq(input)).Times(1)
 
-	err := repo.Persist(dat, filepath)
+	err := repo.Persist(context.Background(), dat, filepath)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -55,9 +56,9 @@ func TestS3FileRepository_Persist_Err(t *testing.T) {
 	}
 
 	e := errors.New("upload error")
-	mockedUploader.EXPECT().Upload(gomock.Eq(input)).Return(nil, e).Times(1)
+	mockedUploader.EXPECT().UploadWithContext(gomock.Any(), gomock.Eq(input)).Return(nil, e).Times(1)
 
-	err := repo.Persist(dat, filepath)
+	err := repo.Persist(context.Background(), dat, filepath)
 	if err != e {
 		t.Fatalf("expected error %v, got error %v", e, err)
 	}
@@ -80,12 +81,14 @@ func TestS3FileRepository_Get(t *testing.T) {
 
 	mockedDownloader.
 		EXPECT().
-		Download(gomock.Any(), gomock.Eq(input)).
-		Do(func(w io.WriterAt, in *s3.GetObjectInput, args ...func(*s3manager.Downloader)) { w.WriteAt(want, 0) }).
+		DownloadWithContext(gomock.Any(), gomock.Any(), gomock.Eq(input)).
+		Do(func(ctx context.Context, w io.WriterAt, in *s3.GetObjectInput, args ...func(*s3manager.Downloader)) {
+			w.WriteAt(want, 0)
+		}).
 		Times(1).
 		Return(int64(1), nil)
 
-	got, err := repo.Get(filepath)
+	got, err := repo.Get(context.Background(), filepath)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -112,11 +115,11 @@ func TestS3FileRepository_Get_Err(t *testing.T) {
 	e := errors.New("get error")
 	mockedDownloader.
 		EXPECT().
-		Download(gomock.Any(), gomock.Eq(input)).
+		DownloadWithContext(gomock.Any(), gomock.Any(), gomock.Eq(input)).
 		Times(1).
 		Return(int64(0), e)
 
-	got, err := repo.Get(filepath)
+	got, err := repo.Get(context.Background(), filepath)
 	if err != e {
 		t.Fatalf("expected %v, got %v", e, err)
 	}
@@ -135,17 +138,17 @@ func TestS3FileRepository_Delete(t *testing.T) {
 
 	repo := NewS3FileRepository(nil, nil, mockedService, bucket)
 
-	mockedService.EXPECT().DeleteObject(gomock.Eq(&s3.DeleteObjectInput{
+	mockedService.EXPECT().DeleteObjectWithContext(gomock.Any(), gomock.Eq(&s3.DeleteObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(filepath),
 	})).Return(nil, nil)
 
-	mockedService.EXPECT().WaitUntilObjectNotExists(gomock.Eq(&s3.HeadObjectInput{
+	mockedService.EXPECT().WaitUntilObjectNotExistsWithContext(gomock.Any(), gomock.Eq(&s3.HeadObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(filepath),
 	})).Return(nil)
 
-	err := repo.Delete(filepath)
+	err := repo.Delete(context.Background(), filepath)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -161,12 +164,12 @@ func TestS3FileRepository_Delete_DeleteObjectErr(t *testing.T) {
 	repo := NewS3FileRepository(nil, nil, mockedService, bucket)
 
 	e := errors.New("delete error")
-	mockedService.EXPECT().DeleteObject(gomock.Eq(&s3.DeleteObjectInput{
+	mockedService.EXPECT().DeleteObjectWithContext(gomock.Any(), gomock.Eq(&s3.DeleteObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(filepath),
 	})).Return(nil, e)
 
-	err := repo.Delete(filepath)
+	err := repo.Delete(context.Background(), filepath)
 	if err != e {
 		t.Fatalf("expected %v, got %v", e, err)
 	}
@@ -181,18 +184,18 @@ func TestS3FileRepository_Delete_WaitErr(t *testing.T) {
 
 	repo := NewS3FileRepository(nil, nil, mockedService, bucket)
 
-	mockedService.EXPECT().DeleteObject(gomock.Eq(&s3.DeleteObjectInput{
+	mockedService.EXPECT().DeleteObjectWithContext(gomock.Any(), gomock.Eq(&s3.DeleteObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(filepath),
 	})).Return(nil, nil)
 
 	e := errors.New("wait error")
-	mockedService.EXPECT().WaitUntilObjectNotExists(gomock.Eq(&s3.HeadObjectInput{
+	mockedService.EXPECT().WaitUntilObjectNotExistsWithContext(gomock.Any(), gomock.Eq(&s3.HeadObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(filepath),
 	})).Return(e)
 
-	err := repo.Delete(filepath)
+	err := repo.Delete(context.Background(), filepath)
 	if err != e {
 		t.Fatalf("expected %v, got %v", e, err)
 	}
@@ -212,7 +215,7 @@ func TestS3FileRepository_DeleteMany(t *testing.T) {
 
 	repo := NewS3FileRepository(nil, nil, mockedService, bucket)
 
-	mockedService.EXPECT().DeleteObjects(&s3.DeleteObjectsInput{
+	mockedService.EXPECT().DeleteObjectsWithContext(gomock.Any(), &s3.DeleteObjectsInput{
 		Bucket: &bucket,
 		Delete: &s3.Delete{
 			Objects: objects,
@@ -220,7 +223,7 @@ func TestS3FileRepository_DeleteMany(t *testing.T) {
 		},
 	}).Return(nil, nil)
 
-	err := repo.DeleteMany(filepaths)
+	err := repo.DeleteMany(context.Background(), filepaths)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -236,9 +239,9 @@ func TestS3FileRepository_DeleteMany_Err(t *testing.T) {
 	repo := NewS3FileRepository(nil, nil, mockedService, bucket)
 
 	e := errors.New("delete error")
-	mockedService.EXPECT().DeleteObjects(gomock.Any()).Return(nil, e)
+	mockedService.EXPECT().DeleteObjectsWithContext(gomock.Any(), gomock.Any()).Return(nil, e)
 
-	err := repo.DeleteMany(filepaths)
+	err := repo.DeleteMany(context.Background(), filepaths)
 	if err != e {
 		t.Fatalf("expected %v, got %v", e, err)
 	}
@@ -9,6 +9,10 @@ type TranslatedFileMetadataRepository interface {
 	ListByIds(ids []int) ([]*entity.TranslatedFileMetadata, error)
 	ListByIsid(isid string) ([]*entity.TranslatedFileMetadata, error)
 	ListOriginalFileIdsByIds(ids []int) ([]int, error)
+	// FindBySHA256 returns the latest translation of a file with identical content
+	// in the same languages and without a glossary, created by isid or anyone
+	// when isid is empty. It returns nil when there is none.
+	FindBySHA256(sha256 string, sourceLang string, targetLang string, isid string) (*entity.TranslatedFileMetadata, error)
 	Update(f *entity.TranslatedFileMetadata) error
 	DeleteById(id int) error
 	DeleteByIds(ids []int) error
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"doc-translate-go/pkg/file/entity"
+	"doc-translate-go/pkg/file/queue"
+	"doc-translate-go/pkg/file/repository"
+	"doc-translate-go/pkg/tracker"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type DeadLetterUseCase struct {
+	repo           repository.DeadLetterRepository
+	translateQueue queue.TranslateQueue
+	fileTracker    tracker.FileTracker
+}
+
+func NewDeadLetterUseCase(repo repository.DeadLetterRepository, translateQueue queue.TranslateQueue, fileTracker tracker.FileTracker) *DeadLetterUseCase {
+	return &DeadLetterUseCase{repo, translateQueue, fileTracker}
+}
+
+// Persist stores a task that failed at stage so operators can inspect and requeue it.
+func (uc *DeadLetterUseCase) Persist(t *queue.TranslateTask, stage string, cause error) (int, error) {
+	taskJson, err := json.Marshal(t)
+	if err != nil {
+		return 0, err
+	}
+
+	return uc.repo.Create(&entity.DeadLetter{
+		Task:      string(taskJson),
+		Stage:     stage,
+		Error:     cause.Error(),
+		Attempts:  t.Attempts,
+		CreatedAt: time.Now(),
+		CreatedBy: t.Isid,
+	})
+}
+
+func (uc *DeadLetterUseCase) List() ([]*entity.DeadLetter, error) {
+	return uc.repo.List()
+}
+
+// Requeue puts dead-lettered tasks back on the translate queue with a fresh
+// attempt count and removes them from the dead letters.
+func (uc *DeadLetterUseCase) Requeue(ctx context.Context, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	deadLetters, err := uc.repo.ListByIds(ids)
+	if err != nil {
+		return err
+	}
+
+	var requeued []int
+	for _, d := range deadLetters {
+		var t queue.TranslateTask
+		if err := json.Unmarshal([]byte(d.Task), &t); err != nil {
+			return errors.Join(err, uc.deleteByIds(requeued))
+		}
+
+		t.Attempts = 0
+		t.NotBefore = time.Time{}
+		if err := uc.translateQueue.Add(ctx, &t); err != nil {
+			// Drop what's already back on the queue so it isn't requeued twice
+			return errors.Join(err, uc.deleteByIds(requeued))
+		}
+
+		uc.fileTracker.Create(ctx, &tracker.FileStatus{
+			Key:        fileTrackerKey(t.Isid, t.Filename, t.TargetLang),
+			Status:     "in progress",
+			Stage:      tracker.StageQueued,
+			SourceLang: t.SourceLang,
+			TargetLang: t.TargetLang,
+		})
+
+		requeued = append(requeued, d.Id)
+	}
+
+	return uc.deleteByIds(requeued)
+}
+
+func (uc *DeadLetterUseCase) deleteByIds(ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return uc.repo.DeleteByIds(ids)
+}
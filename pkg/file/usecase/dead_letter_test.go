@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"doc-translate-go/pkg/file/queue"
+	"doc-translate-go/pkg/file/repository/postgresql"
+	"doc-translate-go/pkg/tracker"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func newTestDeadLetterUseCase(t *testing.T) (*DeadLetterUseCase, sqlmock.Sqlmock, *recordingQueue, *tracker.MemoryFileTracker) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	translateQueue := &recordingQueue{}
+	fileTracker := tracker.NewMemoryFileTracker(0)
+
+	return NewDeadLetterUseCase(postgresql.NewPostgresqlDeadLetterRepository(db), translateQueue, fileTracker), mock, translateQueue, fileTracker
+}
+
+func TestDeadLetterUseCase_Requeue(t *testing.T) {
+	uc, mock, translateQueue, fileTracker := newTestDeadLetterUseCase(t)
+
+	task := &queue.TranslateTask{Isid: "isid", Filename: "file.docx", SourceLang: "en", TargetLang: "vi", Attempts: 3, NotBefore: time.Now()}
+	taskJson, _ := json.Marshal(task)
+
+	mock.ExpectQuery(`SELECT (.+) FROM dead_letters WHERE id IN \(\$1\);`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "task", "stage", "error", "attempts", "created_at", "created_by"}).
+			AddRow(7, string(taskJson), "translate", "timeout", 3, time.Now(), "isid"))
+	mock.ExpectExec(`DELETE FROM dead_letters WHERE id IN \(\$1\);`).
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := uc.Requeue(context.Background(), []int{7}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The task starts over, with every attempt and straight away
+	if len(translateQueue.added) != 1 {
+		t.Fatalf("expected the task to be added, got %v", translateQueue.added)
+	}
+	if got := translateQueue.added[0]; got.Attempts != 0 || !got.NotBefore.IsZero() {
+		t.Fatalf("expected a fresh task, got %v", got)
+	}
+
+	status, err := fileTracker.Get(context.Background(), "isid_vi_file.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != "in progress" || status.Stage != tracker.StageQueued {
+		t.Fatalf("expected the file to be queued, got %v", status)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeadLetterUseCase_Requeue_NoIds(t *testing.T) {
+	uc, mock, translateQueue, _ := newTestDeadLetterUseCase(t)
+
+	if err := uc.Requeue(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(translateQueue.added) != 0 {
+		t.Fatalf("expected nothing to be added, got %v", translateQueue.added)
+	}
+
+	// No query with an empty IN list
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"doc-translate-go/pkg/file/repository"
 	"sync"
 )
@@ -13,12 +14,13 @@ func NewFileUseCase(repo repository.FileRepository) *FileUseCase {
 	return &FileUseCase{repo}
 }
 
-func (uc *FileUseCase) Get(filepath string) ([]byte, error) {
-	return uc.repo.Get(filepath)
+func (uc *FileUseCase) Get(ctx context.Context, filepath string) ([]byte, error) {
+	return uc.repo.Get(ctx, filepath)
 }
 
-func (uc *FileUseCase) GetMany(filepaths []string) (map[string][]byte, map[string]error) {
+func (uc *FileUseCase) GetMany(ctx context.Context, filepaths []string) (map[string][]byte, map[string]error) {
 	wg := sync.WaitGroup{}
+	mu := sync.Mutex{}
 
 	data := make(map[string][]byte)
 	errors := make(map[string]error)
@@ -29,7 +31,11 @@ func (uc *FileUseCase) GetMany(filepaths []string) (map[string][]byte, map[strin
 		go func() {
 			defer wg.Done()
 
-			dat, err := uc.Get(p)
+			dat, err := uc.Get(ctx, p)
+
+			mu.Lock()
+			defer mu.Unlock()
+
 			data[p] = dat
 			errors[p] = err
 		}()
@@ -40,24 +46,24 @@ func (uc *FileUseCase) GetMany(filepaths []string) (map[string][]byte, map[strin
 	return data, errors
 }
 
-func (uc *FileUseCase) Persist(b []byte, filepath string) error {
-	return uc.repo.Persist(b, filepath)
+func (uc *FileUseCase) Persist(ctx context.Context, b []byte, filepath string) error {
+	return uc.repo.Persist(ctx, b, filepath)
 }
 
-func (uc *FileUseCase) Delete(filepath string) error {
-	return uc.repo.Delete(filepath)
+func (uc *FileUseCase) Delete(ctx context.Context, filepath string) error {
+	return uc.repo.Delete(ctx, filepath)
 }
 
-func (uc *FileUseCase) DeleteMany(filepaths []string) error {
-	return uc.repo.DeleteMany(filepaths)
+func (uc *FileUseCase) DeleteMany(ctx context.Context, filepaths []string) error {
+	return uc.repo.DeleteMany(ctx, filepaths)
 }
 
-func (uc *FileUseCase) GetUrls(filepaths []string) (map[string]string, map[string]error) {
+func (uc *FileUseCase) GetUrls(ctx context.Context, filepaths []string) (map[string]string, map[string]error) {
 	urls := make(map[string]string)
 	errors := make(map[string]error)
 
 	for _, filepath := range filepaths {
-		url, err := uc.repo.GetUrl(filepath)
+		url, err := uc.repo.GetUrl(ctx, filepath)
 		urls[filepath] = url
 		errors[filepath] = err
 	}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"doc-translate-go/pkg/tracker"
+	"sync"
+	"time"
+)
+
+// heartbeatsPerStale is how many times a task's status is rewritten within
+// the time after which it's considered stale.
+const heartbeatsPerStale = 3
+
+// liveStatusKey carries the liveStatus of the task being executed.
+type liveStatusKey struct{}
+
+// liveStatus is the last status tracked for a task being executed.
+type liveStatus struct {
+	mu     sync.Mutex
+	status *tracker.FileStatus
+}
+
+// heartbeat keeps rewriting the status the task executed under the returned
+// context was last tracked with, so that a long translation, which reports no
+// progress of its own, isn't taken for one a dead worker left behind. The
+// returned stop returns once the status is no longer rewritten.
+func (uc *TranslateUseCase) heartbeat(ctx context.Context) (context.Context, func()) {
+	live := &liveStatus{}
+	ctx = context.WithValue(ctx, liveStatusKey{}, live)
+
+	interval := uc.staleAfter / heartbeatsPerStale
+	if interval <= 0 {
+		return ctx, func() {}
+	}
+
+	beatCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-beatCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			live.mu.Lock()
+			if live.status != nil && live.status.Status == "in progress" {
+				uc.fileTracker.Create(beatCtx, live.status)
+			}
+			live.mu.Unlock()
+		}
+	}()
+
+	return ctx, func() {
+		cancel()
+		<-done
+	}
+}
+
+// trackLive sets status, noting it for the heartbeat of the task executed
+// under ctx if there's one.
+func (uc *TranslateUseCase) trackLive(ctx context.Context, status *tracker.FileStatus) {
+	live, ok := ctx.Value(liveStatusKey{}).(*liveStatus)
+	if !ok {
+		uc.fileTracker.Create(ctx, status)
+		return
+	}
+
+	live.mu.Lock()
+	defer live.mu.Unlock()
+
+	live.status = status
+	uc.fileTracker.Create(ctx, status)
+}
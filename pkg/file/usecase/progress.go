@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"doc-translate-go/pkg/tracker"
 	"fmt"
 )
@@ -13,6 +14,11 @@ func NewProgressUseCase(tracker tracker.FileTracker) *ProgressUseCase {
 	return &ProgressUseCase{tracker}
 }
 
-func (uc *ProgressUseCase) ListByIsid(isid string) ([]*tracker.FileStatus, error) {
-	return uc.fileTracker.List(fmt.Sprintf("%s*", isid))
+func (uc *ProgressUseCase) ListByIsid(ctx context.Context, isid string) ([]*tracker.FileStatus, error) {
+	return uc.fileTracker.List(ctx, fmt.Sprintf("%s_*", isid))
+}
+
+// Subscribe streams status changes of the user's files until ctx is done.
+func (uc *ProgressUseCase) Subscribe(ctx context.Context, isid string) (<-chan *tracker.FileStatus, error) {
+	return uc.fileTracker.Subscribe(ctx, fmt.Sprintf("%s_*", isid))
 }
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"doc-translate-go/pkg/format"
+	"doc-translate-go/pkg/wordcount"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrNoPrice = errors.New("no price for language pair")
+
+// PriceTable holds the price per token of translating from a source to a
+// target language. Either language may be * to match any.
+type PriceTable struct {
+	prices          map[string]float64
+	currency        string
+	tokensPerSecond float64
+}
+
+// NewPriceTable takes prices keyed by source:target, e.g. en:vi or *:*, and
+// the translator's throughput for estimating durations.
+func NewPriceTable(prices map[string]float64, currency string, tokensPerSecond float64) *PriceTable {
+	return &PriceTable{prices, currency, tokensPerSecond}
+}
+
+// Price returns the price per token between two languages, preferring the
+// most specific entry.
+func (t *PriceTable) Price(sourceLang string, targetLang string) (float64, error) {
+	for _, key := range []string{
+		sourceLang + ":" + targetLang,
+		sourceLang + ":*",
+		"*:" + targetLang,
+		"*:*",
+	} {
+		if price, ok := t.prices[key]; ok {
+			return price, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w %s:%s", ErrNoPrice, sourceLang, targetLang)
+}
+
+// Cost returns the price of translating tokens between two languages.
+func (t *PriceTable) Cost(tokens int, sourceLang string, targetLang string) (float64, error) {
+	price, err := t.Price(sourceLang, targetLang)
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(tokens) * price, nil
+}
+
+// Duration estimates how long translating tokens takes.
+func (t *PriceTable) Duration(tokens int) time.Duration {
+	if t.tokensPerSecond <= 0 {
+		return 0
+	}
+
+	return time.Duration(float64(tokens) / t.tokensPerSecond * float64(time.Second)).Round(time.Second)
+}
+
+// Quote is the estimated price of translating a file into one language.
+type Quote struct {
+	TargetLang      string  `json:"target_lang"`
+	Cost            float64 `json:"cost"`
+	Currency        string  `json:"currency"`
+	DurationSeconds int     `json:"duration_seconds"`
+}
+
+// FileQuote is the size of a file and its price per target language.
+type FileQuote struct {
+	Filename   string `json:"filename"`
+	MimeType   string `json:"mime_type"`
+	SourceLang string `json:"source_lang"`
+	wordcount.Count
+	Quotes []Quote `json:"quotes"`
+}
+
+type QuoteUseCase struct {
+	priceTable *PriceTable
+	formats    []string
+}
+
+func NewQuoteUseCase(priceTable *PriceTable, formats []string) *QuoteUseCase {
+	return &QuoteUseCase{priceTable, formats}
+}
+
+// Quote counts the file's text and prices translating it into every target
+// language, without storing or queuing anything.
+func (uc *QuoteUseCase) Quote(b []byte, filename string, sourceLang string, targetLangs []string) (*FileQuote, error) {
+	if len(targetLangs) == 0 {
+		return nil, ErrNoTargetLanguage
+	}
+
+	mimeType, err := detectFormat(b, filename, uc.formats)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := format.Count(b, mimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &FileQuote{Filename: filename, MimeType: mimeType, SourceLang: sourceLang, Count: count}
+
+	for _, targetLang := range targetLangs {
+		cost, err := uc.priceTable.Cost(count.Tokens, sourceLang, targetLang)
+		if err != nil {
+			return nil, err
+		}
+
+		q.Quotes = append(q.Quotes, Quote{
+			TargetLang:      targetLang,
+			Cost:            cost,
+			Currency:        uc.priceTable.currency,
+			DurationSeconds: int(uc.priceTable.Duration(count.Tokens).Seconds()),
+		})
+	}
+
+	return q, nil
+}
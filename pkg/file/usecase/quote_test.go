@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"doc-translate-go/pkg/format"
+	"doc-translate-go/pkg/wordcount"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPriceTable_Price(t *testing.T) {
+	table := NewPriceTable(map[string]float64{
+		"en:vi": 1,
+		"en:*":  2,
+		"*:ja":  3,
+	}, "USD", 10)
+
+	tests := []struct {
+		sourceLang string
+		targetLang string
+		want       float64
+	}{
+		{"en", "vi", 1},
+		{"en", "ja", 2},
+		{"fr", "ja", 3},
+	}
+
+	for _, tt := range tests {
+		got, err := table.Price(tt.sourceLang, tt.targetLang)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Fatalf("%s:%s: expected %v, got %v", tt.sourceLang, tt.targetLang, tt.want, got)
+		}
+	}
+
+	if _, err := table.Price("fr", "vi"); !errors.Is(err, ErrNoPrice) {
+		t.Fatalf("expected %v, got %v", ErrNoPrice, err)
+	}
+
+	if got := table.Duration(25); got != 3*time.Second {
+		t.Fatalf("expected %v, got %v", 3*time.Second, got)
+	}
+}
+
+func TestQuoteUseCase_Quote(t *testing.T) {
+	b, err := os.ReadFile("../../docx/testdata/sample.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uc := NewQuoteUseCase(NewPriceTable(map[string]float64{"en:vi": 0.5, "*:*": 1}, "USD", 10), []string{format.Docx})
+
+	got, err := uc.Quote(b, "sample.docx", "en", []string{"vi", "ja"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &FileQuote{
+		Filename:   "sample.docx",
+		MimeType:   format.Docx,
+		SourceLang: "en",
+		Count:      wordcount.Count{Words: 24, Characters: 99, Tokens: 38},
+		Quotes: []Quote{
+			{TargetLang: "vi", Cost: 19, Currency: "USD", DurationSeconds: 4},
+			{TargetLang: "ja", Cost: 38, Currency: "USD", DurationSeconds: 4},
+		},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	if _, err := uc.Quote([]byte("not a docx"), "sample.docx", "en", []string{"vi"}); err != format.ErrUnsupported {
+		t.Fatalf("expected %v, got %v", format.ErrUnsupported, err)
+	}
+
+	if _, err := uc.Quote(b, "sample.docx", "en", nil); !errors.Is(err, ErrNoTargetLanguage) {
+		t.Fatalf("expected %v, got %v", ErrNoTargetLanguage, err)
+	}
+
+	uc = NewQuoteUseCase(NewPriceTable(map[string]float64{"*:*": 1}, "USD", 10), []string{format.Pptx})
+	if _, err := uc.Quote(b, "sample.docx", "en", []string{"vi"}); !errors.Is(err, ErrFormatNotAllowed) {
+		t.Fatalf("expected %v, got %v", ErrFormatNotAllowed, err)
+	}
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"doc-translate-go/pkg/translator"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy decides how many times a failing translate task is attempted
+// and how long to wait before each retry.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+func NewRetryPolicy(maxAttempts int, baseDelay time.Duration, maxDelay time.Duration) *RetryPolicy {
+	return &RetryPolicy{maxAttempts, baseDelay, maxDelay}
+}
+
+// ShouldRetry reports whether a task that has failed attempts times with err
+// should be tried again.
+func (p *RetryPolicy) ShouldRetry(attempts int, err error) bool {
+	return attempts < p.MaxAttempts && IsTransient(err)
+}
+
+// Backoff returns the delay before the retry that follows the given number of
+// failed attempts, doubling every time up to MaxDelay.
+func (p *RetryPolicy) Backoff(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, p.MaxDelay)
+}
+
+// IsTransient reports whether err is likely to go away on its own, such as the
+// document processor being unavailable or S3 returning a 5xx. Anything else,
+// e.g. the processor rejecting a corrupt document, is considered permanent.
+// A joined error, such as every translator of a chain failing, is transient
+// when any of its errors is.
+func IsTransient(err error) bool {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			if IsTransient(e) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if errors.Is(err, translator.ErrCircuitOpen) || errors.Is(err, translator.ErrTimeout) {
+		return true
+	}
+
+	if s, ok := status.FromError(err); ok && s.Code() != codes.Unknown {
+		switch s.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+			return true
+		default:
+			return false
+		}
+	}
+
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) {
+		return reqErr.StatusCode() >= http.StatusInternalServerError || reqErr.StatusCode() == http.StatusTooManyRequests
+	}
+
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		return awsErr.Code() == request.ErrCodeRequestError || awsErr.Code() == request.ErrCodeResponseTimeout
+	}
+
+	return false
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"doc-translate-go/pkg/translator"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := NewRetryPolicy(5, time.Second, 5*time.Second)
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := p.Backoff(i + 1); got != w {
+			t.Fatalf("attempt %v: expected %v, got %v", i+1, w, got)
+		}
+	}
+}
+
+func TestRetryPolicy_ShouldRetry(t *testing.T) {
+	p := NewRetryPolicy(3, time.Second, time.Second)
+	transient := status.Error(codes.Unavailable, "unavailable")
+
+	if !p.ShouldRetry(2, transient) {
+		t.Fatal("expected retry before max attempts")
+	}
+
+	if p.ShouldRetry(3, transient) {
+		t.Fatal("expected no retry at max attempts")
+	}
+
+	if p.ShouldRetry(1, status.Error(codes.InvalidArgument, "corrupt docx")) {
+		t.Fatal("expected no retry on permanent error")
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"grpc unavailable", status.Error(codes.Unavailable, ""), true},
+		{"grpc deadline exceeded", status.Error(codes.DeadlineExceeded, ""), true},
+		{"grpc invalid argument", status.Error(codes.InvalidArgument, ""), false},
+		{"s3 5xx", awserr.NewRequestFailure(awserr.New("InternalError", "", nil), http.StatusServiceUnavailable, ""), true},
+		{"s3 not found", awserr.NewRequestFailure(awserr.New("NoSuchKey", "", nil), http.StatusNotFound, ""), false},
+		{"aws network error", awserr.New("RequestError", "", nil), true},
+		{"other", errors.New("corrupt"), false},
+		{"circuit open", fmt.Errorf("grpc: %w", translator.ErrCircuitOpen), true},
+		{"translator timeout", translator.ErrTimeout, true},
+		{"chain with a transient error", errors.Join(status.Error(codes.InvalidArgument, ""), translator.ErrCircuitOpen), true},
+		{"chain without a transient error", errors.Join(status.Error(codes.InvalidArgument, ""), errors.New("corrupt")), false},
+	}
+
+	for _, tt := range tests {
+		if got := IsTransient(tt.err); got != tt.want {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
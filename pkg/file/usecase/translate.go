@@ -2,16 +2,71 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"doc-translate-go/pkg/docx"
 	"doc-translate-go/pkg/file/entity"
 	"doc-translate-go/pkg/file/queue"
+	"doc-translate-go/pkg/format"
+	glossaryEntity "doc-translate-go/pkg/glossary/entity"
+	glossary "doc-translate-go/pkg/glossary/usecase"
+	"doc-translate-go/pkg/mask"
+	memoryEntity "doc-translate-go/pkg/memory/entity"
+	memory "doc-translate-go/pkg/memory/usecase"
+	"doc-translate-go/pkg/subtitle"
+	"doc-translate-go/pkg/textdoc"
 	"doc-translate-go/pkg/tracker"
 	"doc-translate-go/pkg/translator"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"path/filepath"
+	"html"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 )
 
+// ReuseScope is whose earlier translations of identical content may be reused.
+type ReuseScope string
+
+const (
+	ReuseNone ReuseScope = "none"
+	ReuseUser ReuseScope = "user"
+	ReuseAll  ReuseScope = "all"
+)
+
+var ErrFormatNotAllowed = errors.New("format not allowed")
+
+var ErrNoTargetLanguage = errors.New("no target language")
+
+// minPollInterval is the shortest time a worker waits between two empty takes.
+const minPollInterval = 100 * time.Millisecond
+
+// maxSegmentRequests bounds how many segments of a text document are with
+// the translator at once.
+const maxSegmentRequests = 8
+
+// interruptTimeout bounds handing an interrupted task back, which happens
+// after the worker's own context is already cancelled.
+const interruptTimeout = 5 * time.Second
+
+// stageProgress is the percentage a file is at when it enters a stage.
+// Translating takes up everything between downloading and uploading.
+var stageProgress = map[tracker.Stage]int{
+	tracker.StageQueued:      0,
+	tracker.StageDownloading: 5,
+	tracker.StageTranslating: 10,
+	tracker.StageUploading:   90,
+	tracker.StageDone:        100,
+	tracker.StageFailed:      0,
+}
+
+// translatingProgress maps the translator's own percentage onto the file's.
+func translatingProgress(percent int) int {
+	from, to := stageProgress[tracker.StageTranslating], stageProgress[tracker.StageUploading]
+	return from + percent*(to-from)/100
+}
+
 type TranslateUseCase struct {
 	translator           translator.Translator
 	originalFileMetaUC   *OriginalFileMetadataUseCase
@@ -19,6 +74,31 @@ type TranslateUseCase struct {
 	fileUC               *FileUseCase
 	fileTracker          tracker.FileTracker
 	translateQueue       queue.TranslateQueue
+	workerPool           *WorkerPool
+	retryPolicy          *RetryPolicy
+	reuseScope           ReuseScope
+	formats              []string
+	deadLetterUC         *DeadLetterUseCase
+	priceTable           *PriceTable
+	lineLengths          subtitle.LineLengths
+	glossaryUC           *glossary.GlossaryUseCase
+	memoryUC             *memory.TranslationMemoryUseCase
+	staleAfter           time.Duration
+}
+
+// TranslateOptions are the optional collaborators of a TranslateUseCase. One
+// left nil turns its feature off: failed tasks aren't dead-lettered, files
+// cost nothing, subtitles keep the default line length, uploads naming a
+// glossary are rejected and the translation memory isn't looked up.
+type TranslateOptions struct {
+	DeadLetterUC *DeadLetterUseCase
+	PriceTable   *PriceTable
+	LineLengths  subtitle.LineLengths
+	GlossaryUC   *glossary.GlossaryUseCase
+	MemoryUC     *memory.TranslationMemoryUseCase
+	// StaleAfter is how long a file's status may stand still before it's
+	// interrupted, 0 to never interrupt it
+	StaleAfter time.Duration
 }
 
 func NewTranslateUseCase(
@@ -28,6 +108,11 @@ func NewTranslateUseCase(
 	fileUseCase *FileUseCase,
 	fileTracker tracker.FileTracker,
 	translateQueue queue.TranslateQueue,
+	workerPool *WorkerPool,
+	retryPolicy *RetryPolicy,
+	reuseScope ReuseScope,
+	formats []string,
+	options TranslateOptions,
 ) *TranslateUseCase {
 	return &TranslateUseCase{
 		translator,
@@ -36,164 +121,885 @@ func NewTranslateUseCase(
 		fileUseCase,
 		fileTracker,
 		translateQueue,
+		workerPool,
+		retryPolicy,
+		reuseScope,
+		formats,
+		options.DeadLetterUC,
+		options.PriceTable,
+		options.LineLengths,
+		options.GlossaryUC,
+		options.MemoryUC,
+		options.StaleAfter,
+	}
+}
+
+func (uc *TranslateUseCase) Translate(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+	return uc.translator.Translate(ctx, b, mimeType, sourceLang, targetLang)
+}
+
+// translateWithProgress passes onProgress to translators able to report progress.
+func (uc *TranslateUseCase) translateWithProgress(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string, onProgress translator.ProgressFunc) ([]byte, error) {
+	if t, ok := uc.translator.(translator.ProgressTranslator); ok {
+		return t.TranslateWithProgress(ctx, b, mimeType, sourceLang, targetLang, onProgress)
+	}
+
+	return uc.translator.Translate(ctx, b, mimeType, sourceLang, targetLang)
+}
+
+// translateFunc translates a document, reporting progress with onProgress.
+type translateFunc func(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string, onProgress translator.ProgressFunc) ([]byte, error)
+
+// dispatch picks how a document of a MIME type is handled before it reaches
+// the translator. Office documents are sent whole, with the translation memory
+// applied if any, text and subtitles are parsed here and only their text is
+// sent.
+func (uc *TranslateUseCase) dispatch(mimeType string) translateFunc {
+	switch {
+	case format.IsText(mimeType):
+		return uc.translateSegments
+	case format.IsSubtitle(mimeType):
+		return uc.translateSubtitles
+	case uc.memoryUC != nil && (mimeType == format.Docx || mimeType == format.Pptx || mimeType == format.Xlsx):
+		return uc.translateOffice
+	}
+
+	return uc.translateWithProgress
+}
+
+// translateSegments translates a file of one of the text formats segment by
+// segment, so its markup never reaches the translator, and reassembles it.
+// Progress is reported as segments come back.
+func (uc *TranslateUseCase) translateSegments(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string, onProgress translator.ProgressFunc) ([]byte, error) {
+	d, err := format.Segment(b, mimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := d.Segments()
+	matches := uc.lookupMemory(ctx, segments, sourceLang, targetLang)
+
+	if err := uc.translateTexts(ctx, segments, matches, sourceLang, targetLang, onProgress); err != nil {
+		return nil, err
+	}
+
+	return d.Assemble(segments)
+}
+
+// translateSubtitles translates the text of every cue of a subtitle file,
+// keeping its indices and timings as they are. Cue text is reflowed to the
+// target language's line length, when one is set, otherwise its line breaks
+// are left to the translator. Progress is reported as cue text comes back.
+func (uc *TranslateUseCase) translateSubtitles(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string, onProgress translator.ProgressFunc) ([]byte, error) {
+	f, err := format.Subtitles(b, mimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	width := uc.lineLengths.For(targetLang)
+	cues := f.Cues()
+	docs := make([]*textdoc.Document, len(cues))
+	var segments []string
+	for i, c := range cues {
+		text := c.Text
+		if width > 0 {
+			// Lines are rebuilt anyway, so the translator sees whole sentences
+			text = strings.Join(strings.Fields(text), " ")
+		}
+
+		if docs[i], err = textdoc.ParseCue([]byte(text)); err != nil {
+			return nil, err
+		}
+		segments = append(segments, docs[i].Segments()...)
+	}
+
+	// The memory is looked up and the text translated for the whole file at
+	// once, not cue by cue
+	matches := uc.lookupMemory(ctx, segments, sourceLang, targetLang)
+
+	if err := uc.translateTexts(ctx, segments, matches, sourceLang, targetLang, onProgress); err != nil {
+		return nil, err
+	}
+
+	for i, c := range cues {
+		n := len(docs[i].Segments())
+		translated, err := docs[i].Assemble(segments[:n])
+		if err != nil {
+			return nil, err
+		}
+		segments = segments[n:]
+
+		c.Text = subtitle.Reflow(string(translated), width)
+	}
+
+	return f.Bytes(), nil
+}
+
+// translateOffice sends an office document to the translator with the
+// paragraphs having an exact translation memory match already translated, and
+// the close matches of the others along. DOCX paragraphs are matched with their
+// formatting as tags, presentations and spreadsheets text element by element.
+func (uc *TranslateUseCase) translateOffice(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string, onProgress translator.ProgressFunc) ([]byte, error) {
+	var fuzzy []translator.MemoryMatch
+	var err error
+
+	if mimeType == format.Docx {
+		b, fuzzy, err = uc.applyMemoryDocx(ctx, b, sourceLang, targetLang)
+	} else {
+		b, fuzzy, err = uc.applyMemoryParts(ctx, b, mimeType, sourceLang, targetLang)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fuzzy) > 0 {
+		ctx = translator.WithMemory(ctx, fuzzy)
+	}
+
+	return uc.translateWithProgress(ctx, b, mimeType, sourceLang, targetLang, onProgress)
+}
+
+func (uc *TranslateUseCase) applyMemoryDocx(ctx context.Context, b []byte, sourceLang string, targetLang string) ([]byte, []translator.MemoryMatch, error) {
+	d, err := docx.Open(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	segments := d.Segments()
+	texts := make([]string, len(segments))
+	for i, s := range segments {
+		texts[i] = html.UnescapeString(s.Markup())
+	}
+
+	exact, fuzzy := uc.matchMemory(ctx, texts, sourceLang, targetLang)
+	if len(exact) == 0 {
+		return b, fuzzy, nil
+	}
+
+	for i, target := range exact {
+		segments[i] = segments[i].ParseMarkup(target)
+	}
+
+	b, err = d.Assemble(segments)
+	return b, fuzzy, err
+}
+
+func (uc *TranslateUseCase) applyMemoryParts(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, []translator.MemoryMatch, error) {
+	texts, err := format.TextElements(b, mimeType)
+	if err != nil {
+		return nil, nil, err
 	}
+
+	exact, fuzzy := uc.matchMemory(ctx, texts, sourceLang, targetLang)
+	if len(exact) == 0 {
+		return b, fuzzy, nil
+	}
+
+	i := 0
+	b, err = format.RewriteParts(b, mimeType, func(text string) string {
+		if target, ok := exact[i]; ok {
+			text = target
+		}
+		i++
+		return text
+	})
+	return b, fuzzy, err
+}
+
+// matchMemory looks the texts of an office document up in the translation
+// memory, counting them in the file's stats. It returns the targets of exact
+// matches by index and the close matches. Blank texts aren't looked up.
+func (uc *TranslateUseCase) matchMemory(ctx context.Context, texts []string, sourceLang string, targetLang string) (map[int]string, []translator.MemoryMatch) {
+	var indices []int
+	var segments []string
+	for i, text := range texts {
+		if strings.TrimSpace(text) != "" {
+			indices = append(indices, i)
+			segments = append(segments, text)
+		}
+	}
+
+	matches := uc.lookupMemory(ctx, segments, sourceLang, targetLang)
+	stats, _ := ctx.Value(memoryStatsKey{}).(*memoryStats)
+
+	exact := make(map[int]string)
+	var fuzzy []translator.MemoryMatch
+	for i, m := range matches {
+		stats.record(m)
+
+		switch {
+		case m == nil:
+		case m.Exact:
+			exact[indices[i]] = m.Target
+		default:
+			fuzzy = append(fuzzy, translator.MemoryMatch{Source: m.Source, Target: m.Target, Score: m.Score})
+		}
+	}
+
+	return exact, fuzzy
+}
+
+// translateTexts translates segments in place, up to maxSegmentRequests at
+// once. onProgress, if any, is called as segments come back. Segments with an
+// exact translation memory match, from matches, take its translation, those
+// with a close one are sent along with it. The first failure stops the rest.
+func (uc *TranslateUseCase) translateTexts(ctx context.Context, segments []string, matches []*memoryEntity.Match, sourceLang string, targetLang string, onProgress translator.ProgressFunc) error {
+	stats, _ := ctx.Value(memoryStatsKey{}).(*memoryStats)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	done := 0
+	semaphore := make(chan struct{}, maxSegmentRequests)
+
+	// The first failure is kept and the segments still out are cancelled
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	// Progress is reported in order, however segments come back
+	finish := func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		done++
+		if onProgress != nil {
+			onProgress(done * 100 / len(segments))
+		}
+	}
+
+	for i, s := range segments {
+		m := matches[i]
+		stats.record(m)
+
+		if m != nil && m.Exact {
+			segments[i] = m.Target
+			finish()
+			continue
+		}
+
+		segmentCtx := ctx
+		if m != nil {
+			segmentCtx = translator.WithMemory(ctx, []translator.MemoryMatch{{Source: m.Source, Target: m.Target, Score: m.Score}})
+		}
+
+		semaphore <- struct{}{}
+		if err := ctx.Err(); err != nil {
+			fail(err)
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			translated, err := uc.translator.Translate(segmentCtx, []byte(s), format.Text, sourceLang, targetLang)
+			if err != nil {
+				fail(err)
+				return
+			}
+
+			segments[i] = string(translated)
+			finish()
+		}()
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
+
+// lookupMemory returns the translation memory match of every segment, nil for
+// those with none. The memory is best effort, segments it fails to look up are
+// translated without a match and the file's stats are left unknown.
+func (uc *TranslateUseCase) lookupMemory(ctx context.Context, segments []string, sourceLang string, targetLang string) []*memoryEntity.Match {
+	if uc.memoryUC != nil {
+		if matches, err := uc.memoryUC.Lookup(sourceLang, targetLang, segments); err == nil {
+			if stats, ok := ctx.Value(memoryStatsKey{}).(*memoryStats); ok {
+				stats.lookedUp = true
+			}
+			return matches
+		}
+	}
+
+	return make([]*memoryEntity.Match, len(segments))
 }
 
-func (uc *TranslateUseCase) Translate(b []byte, sourceLang string, targetLang string) ([]byte, error) {
-	return uc.translator.Translate(b, sourceLang, targetLang)
+// memoryStats counts the segments of a file translated from an exact
+// translation memory match, with a close one and without any. The counts only
+// tell something once lookedUp, files whose segments weren't looked up have
+// none.
+type memoryStats struct {
+	lookedUp  bool
+	hits      int
+	fuzzyHits int
+	misses    int
 }
 
-// TranslateAsync stores file in filesystem and sends a message to a queue
+type memoryStatsKey struct{}
+
+// record counts a segment by its match, nil for none. Files translated
+// without counting have nil stats.
+func (s *memoryStats) record(m *memoryEntity.Match) {
+	switch {
+	case s == nil:
+	case m == nil:
+		s.misses++
+	case m.Exact:
+		s.hits++
+	default:
+		s.fuzzyHits++
+	}
+}
+
+// counts returns the hits, fuzzy hits and misses, all nil when nothing was
+// looked up.
+func (s *memoryStats) counts() (*int, *int, *int) {
+	if !s.lookedUp {
+		return nil, nil, nil
+	}
+	return &s.hits, &s.fuzzyHits, &s.misses
+}
+
+// TranslateAsync stores file in filesystem once and sends a message to a queue
+// for every target language. A glossaryId other than 0 must be a glossary isid
+// may use for every one of the languages.
 func (uc *TranslateUseCase) TranslateAsync(
+	ctx context.Context,
 	b []byte,
 	filename string,
 	filesize int,
 	isid string,
 	sourceLang string,
-	targetLang string,
+	targetLangs []string,
+	glossaryId int,
 ) error {
-	fileExt := filepath.Ext(filename)
-	if fileExt != "docx" {
-		return errors.New("invalid extension")
+	mimeType, err := detectFormat(b, filename, uc.formats)
+	if err != nil {
+		return err
 	}
 
-	fileTrackerKey := fmt.Sprintf("%s_%s", isid, filename)
+	if len(targetLangs) == 0 {
+		return ErrNoTargetLanguage
+	}
 
-	uc.fileTracker.Create(&tracker.FileStatus{
-		Key:        fileTrackerKey,
-		Status:     "in progress",
-		SourceLang: sourceLang,
-		TargetLang: targetLang,
-	})
+	if glossaryId != 0 {
+		for _, targetLang := range targetLangs {
+			if _, err := uc.glossaryFor(glossaryId, isid, sourceLang, targetLang); err != nil {
+				return err
+			}
+		}
+	}
+
+	uc.trackAll(ctx, isid, filename, sourceLang, targetLangs, "in progress", tracker.StageQueued)
+
+	// Languages not queued yet are failed by whatever goes wrong, otherwise
+	// they'd be left in progress with no task to ever move them on
+	queued := 0
+	failUnqueued := func(status string) {
+		uc.trackAll(ctx, isid, filename, sourceLang, targetLangs[queued:], status, tracker.StageFailed)
+	}
 
 	metadatas, err := uc.originalFileMetaUC.ListByFilenameIsid(filename, isid)
 	if err != nil {
+		failUnqueued("fail:metadata")
 		return errors.New("failed to check for duplicated files")
 	}
 
 	if len(metadatas) > 0 {
-		uc.fileTracker.Create(&tracker.FileStatus{
-			Key:        fileTrackerKey,
-			Status:     "fail:duplicate",
-			SourceLang: sourceLang,
-			TargetLang: targetLang,
-		})
+		failUnqueued("fail:duplicate")
 		return errors.New("failed to check for duplicated files")
 	}
 
-	err = uc.fileUC.Persist(b, fmt.Sprintf("%s/%s", isid, filename))
+	err = uc.fileUC.Persist(ctx, b, fmt.Sprintf("%s/%s", isid, filename))
 	if err != nil {
-		uc.fileTracker.Create(&tracker.FileStatus{
-			Key:        fileTrackerKey,
-			Status:     "fail:persist",
-			SourceLang: sourceLang,
-			TargetLang: targetLang,
-		})
+		failUnqueued("fail:persist")
 		return errors.New("failed to persist file")
 	}
 
-	fileType := "docx"
+	sum := sha256.Sum256(b)
+	checksum := hex.EncodeToString(sum[:])
+
+	// A file that can't be read is left for the translator to reject
+	count, _ := format.Count(b, mimeType)
+
 	now := time.Now()
 	id, err := uc.originalFileMetaUC.Persist(&entity.OriginalFileMetadata{
-		SHA256:         "",
+		SHA256:         checksum,
 		Filename:       filename,
-		FileType:       fileType,
+		FileType:       mimeType,
 		FileSize:       filesize,
 		SourceLanguage: sourceLang,
-		TokenCount:     0,
+		TokenCount:     count.Tokens,
 		CreatedAt:      now,
 		UpdatedAt:      now,
 		CreatedBy:      isid,
 	})
 	if err != nil {
+		failUnqueued("fail:metadata")
 		return errors.New("failed to store original file metadata")
 	}
 
-	err = uc.translateQueue.Add(&queue.TranslateTask{
-		Isid:           isid,
-		Filename:       filename,
-		SourceLang:     sourceLang,
-		TargetLang:     targetLang,
-		OriginalFileId: id,
-	})
-	if err != nil {
-		return err
+	for _, targetLang := range targetLangs {
+		err = uc.translateQueue.Add(ctx, &queue.TranslateTask{
+			Isid:           isid,
+			Filename:       filename,
+			SourceLang:     sourceLang,
+			TargetLang:     targetLang,
+			OriginalFileId: id,
+			SHA256:         checksum,
+			MimeType:       mimeType,
+			GlossaryId:     glossaryId,
+			QueuedAt:       now,
+		})
+		if err != nil {
+			failUnqueued("fail:queue")
+			return err
+		}
+		queued++
 	}
 
 	return nil
 }
 
+// detectFormat returns the MIME type of a file, provided it's one of formats.
+func detectFormat(b []byte, filename string, formats []string) (string, error) {
+	mimeType, err := format.Detect(b, filename)
+	if err != nil {
+		return "", err
+	}
+
+	if !slices.Contains(formats, mimeType) {
+		return "", fmt.Errorf("%w: %s", ErrFormatNotAllowed, format.Extension(mimeType))
+	}
+
+	return mimeType, nil
+}
+
+// trackAll sets the same status on the file for every target language.
+func (uc *TranslateUseCase) trackAll(ctx context.Context, isid string, filename string, sourceLang string, targetLangs []string, status string, stage tracker.Stage) {
+	for _, targetLang := range targetLangs {
+		uc.fileTracker.Create(ctx, &tracker.FileStatus{
+			Key:        fileTrackerKey(isid, filename, targetLang),
+			Status:     status,
+			Stage:      stage,
+			Progress:   stageProgress[stage],
+			SourceLang: sourceLang,
+			TargetLang: targetLang,
+		})
+	}
+}
+
+// track sets the status of the task's file.
+func (uc *TranslateUseCase) track(ctx context.Context, t *queue.TranslateTask, status string, stage tracker.Stage, progress int) {
+	uc.trackLive(ctx, &tracker.FileStatus{
+		Key:        fileTrackerKey(t.Isid, t.Filename, t.TargetLang),
+		Status:     status,
+		Stage:      stage,
+		Progress:   progress,
+		SourceLang: t.SourceLang,
+		TargetLang: t.TargetLang,
+	})
+}
+
+// fileTrackerKey identifies one file's translation into one language, so
+// fanning a file out to several languages yields independent statuses.
+func fileTrackerKey(isid string, filename string, targetLang string) string {
+	return fmt.Sprintf("%s_%s_%s", isid, targetLang, filename)
+}
+
 // ExecuteQueue takes one message out of the queue and performs translating.
-func (uc *TranslateUseCase) ExecuteQueue() error {
-	t, key := uc.translateQueue.Take()
+func (uc *TranslateUseCase) ExecuteQueue(ctx context.Context) error {
+	t, key := uc.translateQueue.Take(ctx)
 	if t == nil {
 		return nil
 	}
 
-	fileTrackerKey := fmt.Sprintf("%s_%s", t.Isid, t.Filename)
+	return uc.execute(ctx, t, key)
+}
+
+// execute translates the task and removes it from the queue. A failed task is
+// retried with backoff while its error is transient, and dead-lettered otherwise.
+func (uc *TranslateUseCase) execute(ctx context.Context, t *queue.TranslateTask, key string) error {
+	liveCtx, stop := uc.heartbeat(ctx)
+	stage, err := uc.translateTask(liveCtx, t)
+	stop()
 
-	b, err := uc.fileUC.Get(fmt.Sprintf("%s/%s", t.Isid, t.Filename))
 	if err != nil {
-		uc.fileTracker.Create(&tracker.FileStatus{
-			Key:        fileTrackerKey,
-			Status:     "fail:read",
-			SourceLang: t.SourceLang,
-			TargetLang: t.TargetLang,
-		})
-		return err
+		return uc.fail(ctx, t, key, stage, err)
+	}
+
+	// Subscribers are told the file is done before its status disappears
+	uc.track(ctx, t, tracker.StatusDone, tracker.StageDone, stageProgress[tracker.StageDone])
+	uc.fileTracker.Delete(ctx, fileTrackerKey(t.Isid, t.Filename, t.TargetLang))
+
+	uc.translateQueue.Delete(ctx, key)
+
+	return nil
+}
+
+// translateTask reads, translates and persists the task's file. On failure it
+// returns the stage that failed.
+func (uc *TranslateUseCase) translateTask(ctx context.Context, t *queue.TranslateTask) (string, error) {
+	start := time.Now()
+
+	uc.track(ctx, t, "in progress", tracker.StageDownloading, stageProgress[tracker.StageDownloading])
+
+	b, err := uc.fileUC.Get(ctx, fmt.Sprintf("%s/%s", t.Isid, t.Filename))
+	if err != nil {
+		return "read", err
 	}
 
-	translated_b, err := uc.Translate(b, t.SourceLang, t.TargetLang)
+	g, err := uc.taskGlossary(t)
 	if err != nil {
-		uc.fileTracker.Create(&tracker.FileStatus{
-			Key:        fileTrackerKey,
-			Status:     "fail:translate",
-			SourceLang: t.SourceLang,
-			TargetLang: t.TargetLang,
+		return "glossary", err
+	}
+
+	uc.track(ctx, t, "in progress", tracker.StageTranslating, stageProgress[tracker.StageTranslating])
+
+	// Reused translations weren't billed, so they keep no usage nor memory stats
+	usageCtx, usage := translator.WithUsage(ctx)
+	stats := &memoryStats{}
+	usageCtx = context.WithValue(usageCtx, memoryStatsKey{}, stats)
+	if g != nil {
+		usageCtx = translator.WithGlossary(usageCtx, translatorTerms(g.Terms))
+	}
+	translateStart := time.Now()
+
+	// Earlier translations may not have used the glossary, so none is reused
+	var translated_b []byte
+	reused := false
+	if g == nil {
+		translated_b, reused = uc.reuseTranslation(ctx, t)
+	}
+	if !reused {
+		translate := uc.dispatch(taskMimeType(t))
+
+		lastProgress := stageProgress[tracker.StageTranslating]
+		translated_b, err = translate(usageCtx, b, taskMimeType(t), t.SourceLang, t.TargetLang, func(percent int) {
+			// Only write to the tracker when the file's percentage actually moves
+			if progress := translatingProgress(percent); progress != lastProgress {
+				lastProgress = progress
+				uc.track(ctx, t, "in progress", tracker.StageTranslating, progress)
+			}
 		})
-		return err
+		if errors.Is(err, mask.ErrLost) {
+			return "mask", err
+		}
+		if err != nil {
+			return "translate", err
+		}
 	}
 
+	timeTaken := time.Since(translateStart)
+
+	var flags []glossaryEntity.Flag
+	if g != nil {
+		flags = checkTerms(g.Terms, b, translated_b, taskMimeType(t))
+	}
+
+	uc.track(ctx, t, "in progress", tracker.StageUploading, stageProgress[tracker.StageUploading])
+
 	translatedFilename := fmt.Sprintf("translated-%s-to-%s-%s", t.SourceLang, t.TargetLang, t.Filename)
-	err = uc.fileUC.Persist(translated_b, fmt.Sprintf("%s/%s", t.Isid, translatedFilename))
+	err = uc.fileUC.Persist(ctx, translated_b, fmt.Sprintf("%s/%s", t.Isid, translatedFilename))
 	if err != nil {
-		uc.fileTracker.Create(&tracker.FileStatus{
-			Key:        fileTrackerKey,
-			Status:     "fail:persist",
-			SourceLang: t.SourceLang,
-			TargetLang: t.TargetLang,
-		})
-		return err
+		return "persist", err
 	}
 
+	// Tasks queued before QueuedAt existed have no wait to tell
+	var queueTime time.Duration
+	if !t.QueuedAt.IsZero() {
+		queueTime = start.Sub(t.QueuedAt)
+	}
+
+	// Usage without a price is still stored, so the cost can be worked out later
+	var cost float64
+	if uc.priceTable != nil {
+		cost, _ = uc.priceTable.Cost(usage.Tokens(), t.SourceLang, t.TargetLang)
+	}
+
+	hits, fuzzyHits, misses := stats.counts()
+
 	now := time.Now()
-	uc.translatedFileMetaUC.Persist(&entity.TranslatedFileMetadata{
-		OriginalFileId: t.OriginalFileId,
-		Filename:       translatedFilename,
-		TargetLanguage: t.TargetLang,
-		Cost:           0,
-		CreatedAt:      now,
-		UpdatedAt:      now,
-		CreatedBy:      t.Isid,
+	_, err = uc.translatedFileMetaUC.Persist(&entity.TranslatedFileMetadata{
+		OriginalFileId:   t.OriginalFileId,
+		Filename:         translatedFilename,
+		TargetLanguage:   t.TargetLang,
+		Cost:             cost,
+		TimeTaken:        int(timeTaken.Milliseconds()),
+		QueueTime:        int(queueTime.Milliseconds()),
+		BilledCharacters: usage.Characters(),
+		BilledTokens:     usage.Tokens(),
+		GlossaryId:       t.GlossaryId,
+		TermFlags:        flags,
+		MemoryHits:       hits,
+		MemoryFuzzyHits:  fuzzyHits,
+		MemoryMisses:     misses,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		CreatedBy:        t.Isid,
 	})
+	if err != nil {
+		return "metadata", err
+	}
 
-	uc.fileTracker.Delete(fileTrackerKey)
+	return "", nil
+}
 
-	uc.translateQueue.Delete(key)
+// taskMimeType returns the MIME type of the task's file. Tasks queued before
+// other formats were supported are DOCX.
+func taskMimeType(t *queue.TranslateTask) string {
+	if t.MimeType == "" {
+		return format.Docx
+	}
+	return t.MimeType
+}
 
-	return nil
+// taskGlossary returns the glossary the task's file is translated with, nil
+// for none. A glossary deleted or changed to other languages since the file
+// was queued fails the task.
+func (uc *TranslateUseCase) taskGlossary(t *queue.TranslateTask) (*glossaryEntity.Glossary, error) {
+	if t.GlossaryId == 0 {
+		return nil, nil
+	}
+
+	return uc.glossaryFor(t.GlossaryId, t.Isid, t.SourceLang, t.TargetLang)
+}
+
+// glossaryFor returns glossary id as isid sees it, provided it's for the
+// languages.
+// Without glossaries there's none to be found.
+func (uc *TranslateUseCase) glossaryFor(id int, isid string, sourceLang string, targetLang string) (*glossaryEntity.Glossary, error) {
+	if uc.glossaryUC == nil {
+		return nil, glossary.ErrNotFound
+	}
+
+	return uc.glossaryUC.GetFor(id, isid, sourceLang, targetLang)
 }
 
-// ListenAndExecute polls from translate queue and performs translating one message by another.
-func (uc *TranslateUseCase) ListenAndExecute(ctx context.Context) {
-	ticker := time.NewTicker(500 * time.Millisecond)
+// translatorTerms converts glossary terms for the translator.
+func translatorTerms(terms []glossaryEntity.Term) []translator.Term {
+	result := make([]translator.Term, len(terms))
+	for i, t := range terms {
+		result[i] = translator.Term{Source: t.Source, Target: t.Target, Required: t.Required}
+	}
+	return result
+}
+
+// checkTerms flags the paragraphs of a translation missing a required term of
+// the glossary. A file whose text can't be read goes unchecked.
+func checkTerms(terms []glossaryEntity.Term, b []byte, translated []byte, mimeType string) []glossaryEntity.Flag {
+	source, err := format.Paragraphs(b, mimeType)
+	if err != nil {
+		return nil
+	}
+
+	target, err := format.Paragraphs(translated, mimeType)
+	if err != nil {
+		return nil
+	}
+
+	return glossary.Check(terms, source, target)
+}
+
+// reuseTranslation looks for an earlier translation of identical content in
+// the same languages, so the translator isn't paid twice for the same file.
+func (uc *TranslateUseCase) reuseTranslation(ctx context.Context, t *queue.TranslateTask) ([]byte, bool) {
+	if t.SHA256 == "" || uc.reuseScope == ReuseNone {
+		return nil, false
+	}
+
+	isid := t.Isid
+	if uc.reuseScope == ReuseAll {
+		isid = ""
+	}
+
+	m, err := uc.translatedFileMetaUC.FindBySHA256(t.SHA256, t.SourceLang, t.TargetLang, isid)
+	if err != nil || m == nil {
+		return nil, false
+	}
+
+	// The translated file may have been deleted since, translate it again then
+	b, err := uc.fileUC.Get(ctx, fmt.Sprintf("%s/%s", m.CreatedBy, m.Filename))
+	if err != nil {
+		return nil, false
+	}
+
+	return b, true
+}
+
+// fail queues the task again, held back for a backoff, while its error is
+// transient and it has attempts left, and dead-letters it otherwise. Either
+// way its original message is deleted.
+func (uc *TranslateUseCase) fail(ctx context.Context, t *queue.TranslateTask, key string, stage string, cause error) error {
+	// Cancelled mid-translation, which is no fault of the task's, so it's
+	// neither spending an attempt nor dead-lettered
+	if ctx.Err() != nil {
+		uc.interrupt(ctx, t, key)
+		return cause
+	}
+
+	t.Attempts++
+
+	if uc.retryPolicy.ShouldRetry(t.Attempts, cause) {
+		// The queue holds the retry back for the backoff, rather than the
+		// worker, whose lease would run out meanwhile
+		t.NotBefore = time.Now().Add(uc.retryPolicy.Backoff(t.Attempts))
+
+		if err := uc.translateQueue.Add(ctx, t); err != nil {
+			return errors.Join(cause, err)
+		}
+
+		uc.translateQueue.Delete(ctx, key)
+		uc.track(ctx, t, "in progress", tracker.StageQueued, stageProgress[tracker.StageQueued])
+
+		return cause
+	}
+
+	uc.track(ctx, t, fmt.Sprintf("fail:%s", stage), tracker.StageFailed, stageProgress[tracker.StageFailed])
+
+	if uc.deadLetterUC != nil {
+		if _, err := uc.deadLetterUC.Persist(t, stage, cause); err != nil {
+			return errors.Join(cause, err)
+		}
+	}
+
+	uc.translateQueue.Delete(ctx, key)
+
+	return cause
+}
+
+// interrupt marks the task's file as interrupted and hands the task back to the
+// queue. Should that fail the queue redelivers it once its lease runs out.
+func (uc *TranslateUseCase) interrupt(ctx context.Context, t *queue.TranslateTask, key string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), interruptTimeout)
+	defer cancel()
+
+	uc.track(ctx, t, tracker.StatusInterrupted, tracker.StageFailed, stageProgress[tracker.StageFailed])
+
+	if err := uc.translateQueue.Add(ctx, t); err == nil {
+		uc.translateQueue.Delete(ctx, key)
+	}
+}
+
+// InterruptStale marks files as interrupted when their status hasn't moved for
+// olderThan, as left behind by a worker that died mid-translation. Queued files
+// are left alone, they're waiting on the queue rather than on a worker.
+func (uc *TranslateUseCase) InterruptStale(ctx context.Context, olderThan time.Duration) (int, error) {
+	statuses, err := uc.fileTracker.List(ctx, "*")
+	if err != nil {
+		return 0, err
+	}
+
+	deadline := time.Now().Add(-olderThan)
+	n := 0
+
+	for _, s := range statuses {
+		if s.Status != "in progress" || s.Stage == tracker.StageQueued || !s.UpdatedAt.Before(deadline) {
+			continue
+		}
+
+		s.Status = tracker.StatusInterrupted
+		s.Stage = tracker.StageFailed
+		s.Progress = stageProgress[tracker.StageFailed]
+
+		if err := uc.fileTracker.Create(ctx, s); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+// staleSweepLock is taken by the process sweeping stale statuses.
+const staleSweepLock = "stale-sweep"
+
+// WatchStale interrupts files whose status has stood still for StaleAfter,
+// checking every StaleAfter until ctx is cancelled. When the file tracker is
+// shared by several processes only one of them sweeps at a time.
+func (uc *TranslateUseCase) WatchStale(ctx context.Context) {
+	if uc.staleAfter <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(uc.staleAfter)
+	defer ticker.Stop()
 
 	for {
+		uc.sweepStale(ctx)
+
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			uc.ExecuteQueue()
 		}
 	}
 }
+
+// sweepStale runs InterruptStale unless another process holds the sweep lock.
+// The lock isn't released, it lasts until the next sweep is due.
+func (uc *TranslateUseCase) sweepStale(ctx context.Context) (int, error) {
+	if locker, ok := uc.fileTracker.(tracker.Locker); ok {
+		locked, err := locker.TryLock(ctx, staleSweepLock, uc.staleAfter)
+		if err != nil || !locked {
+			return 0, err
+		}
+	}
+
+	return uc.InterruptStale(ctx, uc.staleAfter)
+}
+
+// ListenAndExecute runs one worker per slot of the worker pool, each long-polling
+// the translate queue. Workers stop taking tasks once ctx is cancelled, while the
+// tasks they already took keep running under workCtx. It returns once every
+// worker has finished or been interrupted by workCtx being cancelled.
+func (uc *TranslateUseCase) ListenAndExecute(ctx context.Context, workCtx context.Context) {
+	wg := sync.WaitGroup{}
+
+	for id := 0; id < uc.workerPool.Size(); id++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			uc.work(ctx, workCtx, id)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (uc *TranslateUseCase) work(ctx context.Context, workCtx context.Context, id int) {
+	for ctx.Err() == nil {
+		// Take blocks until a task arrives or the queue's wait time elapses
+		start := time.Now()
+		t, key := uc.translateQueue.Take(ctx)
+		if t == nil {
+			// Don't spin on a queue that returns straight away, e.g. when it's unreachable
+			select {
+			case <-ctx.Done():
+			case <-time.After(minPollInterval - time.Since(start)):
+			}
+			continue
+		}
+
+		uc.workerPool.setBusy(id, true)
+		uc.execute(workCtx, t, key)
+		uc.workerPool.setBusy(id, false)
+	}
+}
+
+// WorkerStates reports whether each worker is translating or waiting for a task.
+func (uc *TranslateUseCase) WorkerStates() []WorkerState {
+	return uc.workerPool.States()
+}
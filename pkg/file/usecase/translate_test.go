@@ -0,0 +1,1278 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"doc-translate-go/pkg/file/queue"
+	"doc-translate-go/pkg/file/repository/filesystem"
+	"doc-translate-go/pkg/file/repository/postgresql"
+	"doc-translate-go/pkg/format"
+	glossaryPG "doc-translate-go/pkg/glossary/repository/postgresql"
+	glossary "doc-translate-go/pkg/glossary/usecase"
+	"doc-translate-go/pkg/mask"
+	memoryEntity "doc-translate-go/pkg/memory/entity"
+	memoryPG "doc-translate-go/pkg/memory/repository/postgresql"
+	memory "doc-translate-go/pkg/memory/usecase"
+	"doc-translate-go/pkg/subtitle"
+	"doc-translate-go/pkg/tracker"
+	"doc-translate-go/pkg/translator"
+	userPG "doc-translate-go/pkg/user/repository/postgresql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+// progressTranslator reports a few percentages before echoing the document back.
+type progressTranslator struct{}
+
+func (t *progressTranslator) Translate(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+	return b, nil
+}
+
+func (t *progressTranslator) TranslateWithProgress(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string, onProgress translator.ProgressFunc) ([]byte, error) {
+	for _, percent := range []int{50, 50, 100} {
+		onProgress(percent)
+	}
+	return b, nil
+}
+
+func TestTranslatingProgress(t *testing.T) {
+	for percent, want := range map[int]int{0: 10, 50: 50, 100: 90} {
+		if got := translatingProgress(percent); got != want {
+			t.Fatalf("%v: expected %v, got %v", percent, want, got)
+		}
+	}
+}
+
+// translatorFunc translates with the test's function, which says what the
+// translator answers and notes what it was asked.
+type translatorFunc func(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error)
+
+func (f translatorFunc) Translate(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+	return f(ctx, b, mimeType, sourceLang, targetLang)
+}
+
+// upper translates by uppercasing the text it's given.
+func upper(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+	return bytes.ToUpper(b), nil
+}
+
+// unexpectedTranslation fails the test if a translation is requested.
+func unexpectedTranslation(t *testing.T) translatorFunc {
+	return func(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+		t.Fatal("unexpected call to translator")
+		return nil, nil
+	}
+}
+
+// newTestTranslateUseCase builds a use case with none of the optional
+// collaborators, over a mocked database.
+func newTestTranslateUseCase(t *testing.T, translr translator.Translator, reuseScope ReuseScope) (*TranslateUseCase, sqlmock.Sqlmock, *FileUseCase, *tracker.MemoryFileTracker) {
+	return newTestTranslateUseCaseWith(t, translr, reuseScope, func(db *sql.DB) TranslateOptions {
+		return TranslateOptions{}
+	})
+}
+
+// newTestTranslateUseCaseWith builds a use case with the optional
+// collaborators options returns, given the mocked database.
+func newTestTranslateUseCaseWith(t *testing.T, translr translator.Translator, reuseScope ReuseScope, options func(db *sql.DB) TranslateOptions) (*TranslateUseCase, sqlmock.Sqlmock, *FileUseCase, *tracker.MemoryFileTracker) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fileUC := NewFileUseCase(filesystem.NewFilesystemFileRepository(t.TempDir(), []byte("secret"), "", time.Minute))
+	if err := fileUC.Persist(context.Background(), []byte("content"), "isid/file.docx"); err != nil {
+		t.Fatal(err)
+	}
+
+	fileTracker := tracker.NewMemoryFileTracker(0)
+	uc := NewTranslateUseCase(
+		translr,
+		NewOriginalFileMetadataUseCase(postgresql.NewPostgresqlOriginalFileMetadataRepository(db)),
+		NewTranslatedFileMetadataUseCase(postgresql.NewPostgresqlTranslatedFileMetadataRepository(db)),
+		fileUC,
+		fileTracker,
+		queue.NewChannelTranslateQueue(make(chan *queue.TranslateTask, 1)),
+		NewWorkerPool(1),
+		NewRetryPolicy(1, time.Millisecond, time.Millisecond),
+		reuseScope,
+		[]string{format.Docx},
+		options(db),
+	)
+
+	return uc, mock, fileUC, fileTracker
+}
+
+var originalFilesColumns = []string{"id", "sha256", "filename", "file_type", "file_size", "source_language", "token_count", "created_at", "updated_at", "created_by"}
+
+func TestTranslateUseCase_TranslateAsync_FanOut(t *testing.T) {
+	uc, mock, fileUC, fileTracker := newTestTranslateUseCase(t, unexpectedTranslation(t), ReuseNone)
+	uc.formats = []string{format.Text}
+	translateQueue := &recordingQueue{}
+	uc.translateQueue = translateQueue
+
+	mock.ExpectQuery("SELECT (.+) FROM original_files").
+		WithArgs("hello.txt", "isid").
+		WillReturnRows(sqlmock.NewRows(originalFilesColumns))
+	// One file however many languages it's translated to
+	mock.ExpectQuery("INSERT INTO original_files").
+		WithArgs(sqlmock.AnyArg(), "hello.txt", format.Text, 5, "en", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "isid").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	ctx := context.Background()
+	if err := uc.TranslateAsync(ctx, []byte("Hello"), "hello.txt", 5, "isid", "en", []string{"vi", "ja"}, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fileUC.Get(ctx, "isid/hello.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A task and a status per language
+	var langs []string
+	for _, task := range translateQueue.added {
+		if task.OriginalFileId != 3 || task.MimeType != format.Text {
+			t.Fatalf("unexpected task %v", task)
+		}
+		langs = append(langs, task.TargetLang)
+	}
+	if want := []string{"vi", "ja"}; !reflect.DeepEqual(want, langs) {
+		t.Fatalf("expected tasks for %v, got %v", want, langs)
+	}
+
+	for _, key := range []string{"isid_vi_hello.txt", "isid_ja_hello.txt"} {
+		status, err := fileTracker.Get(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.Status != "in progress" || status.Stage != tracker.StageQueued {
+			t.Fatalf("%v: expected the file to be queued, got %v", key, status)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newDocx builds the smallest package detected as DOCX.
+// newDocx zips a DOCX with a paragraph per text of paragraphs.
+func newDocx(t *testing.T, paragraphs ...string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+
+	f, err := w.Create("[Content_Types].xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+</Types>`))
+
+	if f, err = w.Create("word/document.xml"); err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>`))
+	for _, p := range paragraphs {
+		f.Write([]byte(`<w:p><w:r><w:t>` + p + `</w:t></w:r></w:p>`))
+	}
+	f.Write([]byte(`</w:body></w:document>`))
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestTranslateUseCase_TranslateAsync_Rejected(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		b           []byte
+		filename    string
+		targetLangs []string
+		want        error
+	}{
+		// Only DOCX is allowed
+		{"format not allowed", []byte("Hello"), "hello.txt", []string{"vi"}, ErrFormatNotAllowed},
+		{"unsupported", []byte("Hello"), "hello.exe", []string{"vi"}, format.ErrUnsupported},
+		{"extension mismatch", newDocx(t), "hello.txt", []string{"vi"}, format.ErrExtensionMismatch},
+		{"no target language", newDocx(t), "hello.docx", nil, ErrNoTargetLanguage},
+	} {
+		uc, mock, _, fileTracker := newTestTranslateUseCase(t, unexpectedTranslation(t), ReuseNone)
+		translateQueue := &recordingQueue{}
+		uc.translateQueue = translateQueue
+
+		ctx := context.Background()
+		err := uc.TranslateAsync(ctx, test.b, test.filename, len(test.b), "isid", "en", test.targetLangs, 0)
+		if !errors.Is(err, test.want) {
+			t.Fatalf("%v: expected %v, got %v", test.name, test.want, err)
+		}
+
+		// Nothing is stored, queued or tracked
+		if len(translateQueue.added) != 0 {
+			t.Fatalf("%v: expected nothing to be queued, got %v", test.name, translateQueue.added)
+		}
+		if statuses, _ := fileTracker.List(ctx, "*"); len(statuses) != 0 {
+			t.Fatalf("%v: expected no status, got %v", test.name, statuses)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("%v: %v", test.name, err)
+		}
+	}
+}
+
+var glossariesColumns = []string{"id", "name", "source_language", "target_language", "team", "terms", "created_at", "updated_at", "created_by"}
+
+func TestTranslateUseCase_TranslateAsync_Glossary(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		expect      func(mock sqlmock.Sqlmock)
+		targetLangs []string
+		want        error
+	}{
+		{
+			name: "other user's",
+			expect: func(mock sqlmock.Sqlmock) {
+				now := time.Now()
+				mock.ExpectQuery(`SELECT (.+) FROM glossaries WHERE id = \$1;`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(glossariesColumns).AddRow(1, "Products", "en", "vi", "", "[]", now, now, "other"))
+				mock.ExpectQuery(`SELECT (.+) FROM users WHERE isid = \$1;`).
+					WithArgs("isid").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "isid", "role", "email", "team", "created_at", "updated_at"}).AddRow(1, "isid", "user", "", "team", now, now))
+			},
+			targetLangs: []string{"vi"},
+			want:        glossary.ErrNotFound,
+		},
+		{
+			// The glossary is checked against every target language
+			name: "other languages",
+			expect: func(mock sqlmock.Sqlmock) {
+				now := time.Now()
+				for range 2 {
+					mock.ExpectQuery(`SELECT (.+) FROM glossaries WHERE id = \$1;`).
+						WithArgs(1).
+						WillReturnRows(sqlmock.NewRows(glossariesColumns).AddRow(1, "Products", "en", "vi", "", "[]", now, now, "isid"))
+				}
+			},
+			targetLangs: []string{"vi", "ja"},
+			want:        glossary.ErrLanguageMismatch,
+		},
+	} {
+		uc, mock, _, fileTracker := newTestTranslateUseCaseWith(t, unexpectedTranslation(t), ReuseNone, func(db *sql.DB) TranslateOptions {
+			return TranslateOptions{GlossaryUC: glossary.NewGlossaryUseCase(glossaryPG.NewPostgresqlGlossaryRepository(db), userPG.NewPostgresqlUserRepository(db))}
+		})
+		uc.formats = []string{format.Text}
+		translateQueue := &recordingQueue{}
+		uc.translateQueue = translateQueue
+
+		test.expect(mock)
+
+		ctx := context.Background()
+		err := uc.TranslateAsync(ctx, []byte("Hello"), "hello.txt", 5, "isid", "en", test.targetLangs, 1)
+		if !errors.Is(err, test.want) {
+			t.Fatalf("%v: expected %v, got %v", test.name, test.want, err)
+		}
+
+		// The file is refused before anything is stored, queued or tracked
+		if len(translateQueue.added) != 0 {
+			t.Fatalf("%v: expected nothing to be queued, got %v", test.name, translateQueue.added)
+		}
+		if statuses, _ := fileTracker.List(ctx, "*"); len(statuses) != 0 {
+			t.Fatalf("%v: expected no status, got %v", test.name, statuses)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("%v: %v", test.name, err)
+		}
+	}
+}
+
+// fullQueue takes n tasks, then fails to add any more.
+type fullQueue struct {
+	recordingQueue
+	n int
+}
+
+var errQueueFull = errors.New("queue full")
+
+func (q *fullQueue) Add(ctx context.Context, t *queue.TranslateTask) error {
+	if len(q.added) == q.n {
+		return errQueueFull
+	}
+	return q.recordingQueue.Add(ctx, t)
+}
+
+func TestTranslateUseCase_TranslateAsync_QueueFull(t *testing.T) {
+	uc, mock, _, fileTracker := newTestTranslateUseCase(t, unexpectedTranslation(t), ReuseNone)
+	uc.formats = []string{format.Text}
+	uc.translateQueue = &fullQueue{n: 1}
+
+	mock.ExpectQuery("SELECT (.+) FROM original_files").WillReturnRows(sqlmock.NewRows(originalFilesColumns))
+	mock.ExpectQuery("INSERT INTO original_files").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	ctx := context.Background()
+	if err := uc.TranslateAsync(ctx, []byte("Hello"), "hello.txt", 5, "isid", "en", []string{"vi", "ja", "fr"}, 0); !errors.Is(err, errQueueFull) {
+		t.Fatalf("expected %v, got %v", errQueueFull, err)
+	}
+
+	// The queued language goes on, the others aren't left in progress
+	for key, want := range map[string]string{
+		"isid_vi_hello.txt": "in progress",
+		"isid_ja_hello.txt": "fail:queue",
+		"isid_fr_hello.txt": "fail:queue",
+	} {
+		status, err := fileTracker.Get(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.Status != want {
+			t.Fatalf("%v: expected %v, got %v", key, want, status.Status)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslateUseCase_TranslateAsync_MetadataErr(t *testing.T) {
+	uc, mock, _, fileTracker := newTestTranslateUseCase(t, unexpectedTranslation(t), ReuseNone)
+	uc.formats = []string{format.Text}
+
+	mock.ExpectQuery("SELECT (.+) FROM original_files").WillReturnError(errors.New("connection refused"))
+
+	ctx := context.Background()
+	if err := uc.TranslateAsync(ctx, []byte("Hello"), "hello.txt", 5, "isid", "en", []string{"vi", "ja"}, 0); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	for _, key := range []string{"isid_vi_hello.txt", "isid_ja_hello.txt"} {
+		status, err := fileTracker.Get(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.Stage != tracker.StageFailed {
+			t.Fatalf("%v: expected the file to have failed, got %v", key, status)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslateUseCase_Execute_Stages(t *testing.T) {
+	uc, mock, _, fileTracker := newTestTranslateUseCase(t, &progressTranslator{}, ReuseNone)
+
+	mock.ExpectQuery("INSERT INTO translated_files").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	statuses, err := fileTracker.Subscribe(ctx, "*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task := &queue.TranslateTask{Isid: "isid", Filename: "file.docx", SourceLang: "en", TargetLang: "vi"}
+	if err := uc.execute(ctx, task, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []tracker.Stage
+	var progress []int
+	for range 7 {
+		s := <-statuses
+		got = append(got, s.Stage)
+		progress = append(progress, s.Progress)
+	}
+
+	want := []tracker.Stage{
+		tracker.StageDownloading,
+		tracker.StageTranslating,
+		tracker.StageTranslating,
+		tracker.StageTranslating,
+		tracker.StageUploading,
+		tracker.StageDone,
+		// The removed status keeps the last stage
+		tracker.StageDone,
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected stages %v, got %v", want, got)
+	}
+
+	wantProgress := []int{5, 10, 50, 90, 90, 100, 100}
+	if !reflect.DeepEqual(wantProgress, progress) {
+		t.Fatalf("expected progress %v, got %v", wantProgress, progress)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslateUseCase_Execute_ReuseTranslation(t *testing.T) {
+	uc, mock, fileUC, _ := newTestTranslateUseCase(t, unexpectedTranslation(t), ReuseAll)
+
+	if err := fileUC.Persist(context.Background(), []byte("translated"), "other/translated-en-to-vi-template.docx"); err != nil {
+		t.Fatal(err)
+	}
+
+	columns := []string{"id", "original_files_id", "translated_filename", "target_language", "cost", "time_taken", "queue_time", "billed_characters", "billed_tokens", "glossary_id", "term_flags", "memory_hits", "memory_fuzzy_hits", "memory_misses", "created_at", "updated_at", "created_by"}
+	mock.ExpectQuery("SELECT (.+) FROM translated_files t").
+		WithArgs("abc", "en", "vi", "").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 1, "translated-en-to-vi-template.docx", "vi", 0, 0, 0, 0, 0, 0, "[]", nil, nil, nil, time.Now(), time.Now(), "other"))
+	// Nothing was billed nor looked up for the reused translation
+	mock.ExpectQuery("INSERT INTO translated_files").
+		WithArgs(0, "translated-en-to-vi-file.docx", "vi", 0.0, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, 0, 0, "[]", nil, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg(), "isid").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+	task := &queue.TranslateTask{Isid: "isid", Filename: "file.docx", SourceLang: "en", TargetLang: "vi", SHA256: "abc"}
+	if err := uc.execute(context.Background(), task, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := fileUC.Get(context.Background(), "isid/translated-en-to-vi-file.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != "translated" {
+		t.Fatalf("expected %s, got %s", "translated", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslateUseCase_Execute_ReuseUser_Miss(t *testing.T) {
+	uc, mock, fileUC, _ := newTestTranslateUseCase(t, &progressTranslator{}, ReuseUser)
+
+	mock.ExpectQuery("SELECT (.+) FROM translated_files t").
+		WithArgs("abc", "en", "vi", "isid").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("INSERT INTO translated_files").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	task := &queue.TranslateTask{Isid: "isid", Filename: "file.docx", SourceLang: "en", TargetLang: "vi", SHA256: "abc"}
+	if err := uc.execute(context.Background(), task, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fileUC.Get(context.Background(), "isid/translated-en-to-vi-file.docx"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// recordingQueue notes the tasks added to it and the keys deleted from it,
+// never handing any task out.
+type recordingQueue struct {
+	mu      sync.Mutex
+	added   []queue.TranslateTask
+	deleted []string
+}
+
+func (q *recordingQueue) Add(ctx context.Context, t *queue.TranslateTask) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.added = append(q.added, *t)
+	return nil
+}
+
+func (q *recordingQueue) Take(ctx context.Context) (*queue.TranslateTask, string) {
+	return nil, ""
+}
+
+func (q *recordingQueue) Delete(ctx context.Context, key string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.deleted = append(q.deleted, key)
+	return nil
+}
+
+func TestTranslateUseCase_Execute_Retry(t *testing.T) {
+	translr := translatorFunc(func(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+		return nil, translator.ErrTimeout
+	})
+	uc, mock, _, fileTracker := newTestTranslateUseCaseWith(t, translr, ReuseNone, func(db *sql.DB) TranslateOptions {
+		// Dead-lettering only stores the task, requeuing it is tested on its own
+		return TranslateOptions{DeadLetterUC: NewDeadLetterUseCase(postgresql.NewPostgresqlDeadLetterRepository(db), nil, nil)}
+	})
+	uc.retryPolicy = NewRetryPolicy(3, time.Minute, time.Hour)
+	translateQueue := &recordingQueue{}
+	uc.translateQueue = translateQueue
+
+	task := &queue.TranslateTask{Isid: "isid", Filename: "file.docx", SourceLang: "en", TargetLang: "vi", Attempts: 1}
+
+	// The backoff is left to the queue, the worker doesn't wait it out
+	start := time.Now()
+	if err := uc.execute(context.Background(), task, "key"); !errors.Is(err, translator.ErrTimeout) {
+		t.Fatalf("expected %v, got %v", translator.ErrTimeout, err)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Minute {
+		t.Fatalf("expected execute to return before the backoff, took %v", elapsed)
+	}
+
+	if len(translateQueue.added) != 1 {
+		t.Fatalf("expected the task to be added again, got %v", translateQueue.added)
+	}
+
+	// The second failure waits twice the base delay
+	retried := translateQueue.added[0]
+	if retried.Attempts != 2 {
+		t.Fatalf("expected %v attempts, got %v", 2, retried.Attempts)
+	}
+	if delay := time.Until(retried.NotBefore); delay <= time.Minute || delay > 2*time.Minute {
+		t.Fatalf("expected the retry to be held back 2 minutes, got %v", delay)
+	}
+
+	if want := []string{"key"}; !reflect.DeepEqual(want, translateQueue.deleted) {
+		t.Fatalf("expected %v deleted, got %v", want, translateQueue.deleted)
+	}
+
+	status, err := fileTracker.Get(context.Background(), "isid_vi_file.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != "in progress" || status.Stage != tracker.StageQueued {
+		t.Fatalf("expected the file to be queued again, got %v", status)
+	}
+
+	// Nothing is dead-lettered
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslateUseCase_Execute_DeadLetter(t *testing.T) {
+	translr := translatorFunc(func(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+		return nil, translator.ErrTimeout
+	})
+	uc, mock, _, fileTracker := newTestTranslateUseCaseWith(t, translr, ReuseNone, func(db *sql.DB) TranslateOptions {
+		// Dead-lettering only stores the task, requeuing it is tested on its own
+		return TranslateOptions{DeadLetterUC: NewDeadLetterUseCase(postgresql.NewPostgresqlDeadLetterRepository(db), nil, nil)}
+	})
+	uc.retryPolicy = NewRetryPolicy(3, time.Minute, time.Hour)
+	translateQueue := &recordingQueue{}
+	uc.translateQueue = translateQueue
+
+	mock.ExpectQuery("INSERT INTO dead_letters").
+		WithArgs(sqlmock.AnyArg(), "translate", translator.ErrTimeout.Error(), 3, sqlmock.AnyArg(), "isid").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	// The third failure is the last attempt
+	task := &queue.TranslateTask{Isid: "isid", Filename: "file.docx", SourceLang: "en", TargetLang: "vi", Attempts: 2}
+	if err := uc.execute(context.Background(), task, "key"); !errors.Is(err, translator.ErrTimeout) {
+		t.Fatalf("expected %v, got %v", translator.ErrTimeout, err)
+	}
+
+	if len(translateQueue.added) != 0 {
+		t.Fatalf("expected the task not to be added again, got %v", translateQueue.added)
+	}
+	if want := []string{"key"}; !reflect.DeepEqual(want, translateQueue.deleted) {
+		t.Fatalf("expected %v deleted, got %v", want, translateQueue.deleted)
+	}
+
+	status, err := fileTracker.Get(context.Background(), "isid_vi_file.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != "fail:translate" {
+		t.Fatalf("expected %v, got %v", "fail:translate", status.Status)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// atLeast matches integer arguments no smaller than itself.
+type atLeast int64
+
+func (a atLeast) Match(v driver.Value) bool {
+	n, ok := v.(int64)
+	return ok && n >= int64(a)
+}
+
+func TestTranslateUseCase_Execute_Usage(t *testing.T) {
+	// The document is echoed back, billed a character per byte and a token per two
+	translr := translatorFunc(func(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+		translator.RecordUsage(ctx, int64(len(b)), int64(len(b)/2))
+		return b, nil
+	})
+	uc, mock, _, _ := newTestTranslateUseCaseWith(t, translr, ReuseNone, func(db *sql.DB) TranslateOptions {
+		return TranslateOptions{PriceTable: NewPriceTable(map[string]float64{"en:vi": 0.5}, "USD", 10)}
+	})
+
+	// "content" is 7 characters and 3 tokens, at 0.5 per token. Without a
+	// translation memory nothing is looked up, so there are no memory counts
+	mock.ExpectQuery("INSERT INTO translated_files").
+		WithArgs(1, "translated-en-to-vi-file.docx", "vi", 1.5, atLeast(0), atLeast(time.Hour.Milliseconds()), 7, 3, 0, "[]", nil, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg(), "isid").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	task := &queue.TranslateTask{
+		Isid:           "isid",
+		Filename:       "file.docx",
+		SourceLang:     "en",
+		TargetLang:     "vi",
+		OriginalFileId: 1,
+		QueuedAt:       time.Now().Add(-time.Hour),
+	}
+	if err := uc.execute(context.Background(), task, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslateUseCase_Execute_Segmented(t *testing.T) {
+	var mu sync.Mutex
+	var mimeTypes []string
+	translr := translatorFunc(func(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		mimeTypes = append(mimeTypes, mimeType)
+		return bytes.ToUpper(b), nil
+	})
+	uc, mock, fileUC, _ := newTestTranslateUseCase(t, translr, ReuseNone)
+
+	content := "# Title\n\nSee [the docs](https://example.com/docs).\n\n```\ncode\n```\n"
+	if err := fileUC.Persist(context.Background(), []byte(content), "isid/file.md"); err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectQuery("INSERT INTO translated_files").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	task := &queue.TranslateTask{Isid: "isid", Filename: "file.md", MimeType: format.Markdown, SourceLang: "en", TargetLang: "vi"}
+	if err := uc.execute(context.Background(), task, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the text is translated, segment by segment
+	if want := []string{format.Text, format.Text}; !reflect.DeepEqual(want, mimeTypes) {
+		t.Fatalf("expected %v, got %v", want, mimeTypes)
+	}
+
+	got, err := fileUC.Get(context.Background(), "isid/translated-en-to-vi-file.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "# TITLE\n\nSEE [THE DOCS](https://example.com/docs).\n\n```\ncode\n```\n"
+	if string(got) != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslateUseCase_TranslateTexts(t *testing.T) {
+	// Text is uppercased after a while, noting the most translations at once,
+	// and fail fails
+	var mu sync.Mutex
+	inFlight, most, fail := 0, 0, ""
+	translr := translatorFunc(func(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+		mu.Lock()
+		inFlight++
+		most = max(most, inFlight)
+		mu.Unlock()
+
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		if string(b) == fail {
+			return nil, errors.New("translation failed")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		return bytes.ToUpper(b), nil
+	})
+	uc, _, _, _ := newTestTranslateUseCase(t, translr, ReuseNone)
+
+	var segments, want []string
+	for i := range 3 * maxSegmentRequests {
+		segments = append(segments, fmt.Sprintf("segment %d", i))
+		want = append(want, fmt.Sprintf("SEGMENT %d", i))
+	}
+
+	var progress []int
+	onProgress := func(percentage int) { progress = append(progress, percentage) }
+
+	if err := uc.translateTexts(context.Background(), segments, make([]*memoryEntity.Match, len(segments)), "en", "vi", onProgress); err != nil {
+		t.Fatal(err)
+	}
+
+	// Segments are sent together but no more than the bound at once, and come
+	// back in place
+	if most < 2 || most > maxSegmentRequests {
+		t.Fatalf("expected between 2 and %v translations at once, got %v", maxSegmentRequests, most)
+	}
+	if !reflect.DeepEqual(want, segments) {
+		t.Fatalf("expected %v, got %v", want, segments)
+	}
+	if len(progress) != len(segments) || progress[len(progress)-1] != 100 {
+		t.Fatalf("expected progress up to 100 once per segment, got %v", progress)
+	}
+
+	fail = "segment 5"
+	segments = []string{"segment 4", "segment 5", "segment 6"}
+	if err := uc.translateTexts(context.Background(), segments, make([]*memoryEntity.Match, len(segments)), "en", "vi", nil); err == nil || err.Error() != "translation failed" {
+		t.Fatalf("expected the segment's failure, got %v", err)
+	}
+}
+
+func TestTranslateUseCase_Execute_Subtitles(t *testing.T) {
+	uc, mock, fileUC, _ := newTestTranslateUseCaseWith(t, translatorFunc(upper), ReuseNone, func(db *sql.DB) TranslateOptions {
+		return TranslateOptions{LineLengths: subtitle.LineLengths{"ja": 10}}
+	})
+
+	content := "1\n00:00:01,000 --> 00:00:02,500\nHello <i>there</i>,\nmy friend.\n\n2\n00:00:03,000 --> 00:00:04,000\n♪ ♪\n"
+	if err := fileUC.Persist(context.Background(), []byte(content), "isid/movie.srt"); err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectQuery("INSERT INTO translated_files").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery("INSERT INTO translated_files").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+	// Lines are kept without a line length for the language, rebuilt with one
+	want := map[string]string{
+		"vi": "1\n00:00:01,000 --> 00:00:02,500\nHELLO <i>THERE</i>,\nMY FRIEND.\n\n2\n00:00:03,000 --> 00:00:04,000\n♪ ♪\n",
+		"ja": "1\n00:00:01,000 --> 00:00:02,500\nHELLO\n<i>THERE</i>, MY\nFRIEND.\n\n2\n00:00:03,000 --> 00:00:04,000\n♪ ♪\n",
+	}
+	for _, lang := range []string{"vi", "ja"} {
+		task := &queue.TranslateTask{Isid: "isid", Filename: "movie.srt", MimeType: format.Srt, SourceLang: "en", TargetLang: lang, OriginalFileId: 1}
+		if err := uc.execute(context.Background(), task, ""); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := fileUC.Get(context.Background(), "isid/translated-en-to-"+lang+"-movie.srt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want[lang] {
+			t.Fatalf("%v: expected %q, got %q", lang, want[lang], got)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslateUseCase_Execute_Glossary(t *testing.T) {
+	var mu sync.Mutex
+	var terms []translator.Term
+	translr := translatorFunc(func(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		terms = translator.Glossary(ctx)
+		return bytes.ToUpper(b), nil
+	})
+	// An earlier translation to reuse would have been made without the glossary
+	uc, mock, fileUC, _ := newTestTranslateUseCaseWith(t, translr, ReuseAll, func(db *sql.DB) TranslateOptions {
+		return TranslateOptions{GlossaryUC: glossary.NewGlossaryUseCase(glossaryPG.NewPostgresqlGlossaryRepository(db), userPG.NewPostgresqlUserRepository(db))}
+	})
+
+	if err := fileUC.Persist(context.Background(), []byte("# Acme\n\nRead the docs.\n"), "isid/file.md"); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM glossaries WHERE id = \$1;`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "source_language", "target_language", "team", "terms", "created_at", "updated_at", "created_by"}).
+			AddRow(1, "Products", "en", "vi", "", `[{"source":"Acme","target":"Acme","required":true},{"source":"docs","target":"tài liệu","required":true}]`, now, now, "isid"))
+
+	// The translator keeps Acme but not the docs
+	mock.ExpectQuery("INSERT INTO translated_files").
+		WithArgs(1, "translated-en-to-vi-file.md", "vi", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 1, `[{"paragraph":1,"text":"Read the docs.","source":"docs","target":"tài liệu"}]`, nil, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg(), "isid").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	task := &queue.TranslateTask{Isid: "isid", Filename: "file.md", MimeType: format.Markdown, SourceLang: "en", TargetLang: "vi", OriginalFileId: 1, SHA256: "abc", GlossaryId: 1}
+	if err := uc.execute(context.Background(), task, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []translator.Term{{Source: "Acme", Target: "Acme", Required: true}, {Source: "docs", Target: "tài liệu", Required: true}}
+	if !reflect.DeepEqual(want, terms) {
+		t.Fatalf("expected %v, got %v", want, terms)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslateUseCase_Execute_Memory(t *testing.T) {
+	// Text is uppercased, noting the translation memory matches sent with it
+	var mu sync.Mutex
+	matches := make(map[string][]translator.MemoryMatch)
+	translr := translatorFunc(func(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		matches[string(b)] = translator.Memory(ctx)
+		return bytes.ToUpper(b), nil
+	})
+	uc, mock, fileUC, _ := newTestTranslateUseCaseWith(t, translr, ReuseNone, func(db *sql.DB) TranslateOptions {
+		return TranslateOptions{MemoryUC: memory.NewTranslationMemoryUseCase(memoryPG.NewPostgresqlTranslationMemoryRepository(db), 0.75)}
+	})
+
+	if err := fileUC.Persist(context.Background(), []byte("# Title\n\nRead the docs.\n\nSee you.\n"), "isid/file.md"); err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectQuery(`SELECT source_text, target_text FROM translation_units`).
+		WithArgs("en", "vi", pq.Array([]string{"Read the docs.", "See you.", "Title"})).
+		WillReturnRows(sqlmock.NewRows([]string{"source_text", "target_text"}).AddRow("Title", "Tiêu đề"))
+	mock.ExpectQuery(`SELECT (.+) FROM unnest`).
+		WithArgs("en", "vi", pq.Array([]string{"Read the docs.", "See you."}), 0.75).
+		WillReturnRows(sqlmock.NewRows([]string{"source", "source_text", "target_text", "score"}).AddRow("Read the docs.", "Read the docs!", "Đọc tài liệu!", 0.8))
+
+	// One exact hit, one fuzzy hit and one miss
+	mock.ExpectQuery("INSERT INTO translated_files").
+		WithArgs(0, "translated-en-to-vi-file.md", "vi", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "[]", 1, 1, 1, sqlmock.AnyArg(), sqlmock.AnyArg(), "isid").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	task := &queue.TranslateTask{Isid: "isid", Filename: "file.md", MimeType: format.Markdown, SourceLang: "en", TargetLang: "vi"}
+	if err := uc.execute(context.Background(), task, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// The exact hit never reaches the translator
+	want := map[string][]translator.MemoryMatch{"Read the docs.": {{Source: "Read the docs!", Target: "Đọc tài liệu!", Score: 0.8}}, "See you.": nil}
+	if !reflect.DeepEqual(want, matches) {
+		t.Fatalf("expected %v, got %v", want, matches)
+	}
+
+	got, err := fileUC.Get(context.Background(), "isid/translated-en-to-vi-file.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "# Tiêu đề\n\nREAD THE DOCS.\n\nSEE YOU.\n"; string(got) != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslateUseCase_Execute_MemorySubtitles(t *testing.T) {
+	// Text is uppercased, noting the translation memory matches sent with it
+	var mu sync.Mutex
+	matches := make(map[string][]translator.MemoryMatch)
+	translr := translatorFunc(func(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		matches[string(b)] = translator.Memory(ctx)
+		return bytes.ToUpper(b), nil
+	})
+	uc, mock, fileUC, _ := newTestTranslateUseCaseWith(t, translr, ReuseNone, func(db *sql.DB) TranslateOptions {
+		return TranslateOptions{MemoryUC: memory.NewTranslationMemoryUseCase(memoryPG.NewPostgresqlTranslationMemoryRepository(db), 0.75)}
+	})
+
+	content := "1\n00:00:01,000 --> 00:00:02,000\nHello.\n\n2\n00:00:03,000 --> 00:00:04,000\nSee you.\n\n3\n00:00:05,000 --> 00:00:06,000\nHello.\n"
+	if err := fileUC.Persist(context.Background(), []byte(content), "isid/movie.srt"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The cues are looked up together, not one by one
+	mock.ExpectQuery(`SELECT source_text, target_text FROM translation_units`).
+		WithArgs("en", "vi", pq.Array([]string{"Hello.", "See you."})).
+		WillReturnRows(sqlmock.NewRows([]string{"source_text", "target_text"}).AddRow("Hello.", "Xin chào."))
+	mock.ExpectQuery(`SELECT (.+) FROM unnest`).
+		WithArgs("en", "vi", pq.Array([]string{"See you."}), 0.75).
+		WillReturnRows(sqlmock.NewRows([]string{"source", "source_text", "target_text", "score"}))
+
+	mock.ExpectQuery("INSERT INTO translated_files").
+		WithArgs(0, "translated-en-to-vi-movie.srt", "vi", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "[]", 2, 0, 1, sqlmock.AnyArg(), sqlmock.AnyArg(), "isid").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	task := &queue.TranslateTask{Isid: "isid", Filename: "movie.srt", MimeType: format.Srt, SourceLang: "en", TargetLang: "vi"}
+	if err := uc.execute(context.Background(), task, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the miss reaches the translator
+	if want := map[string][]translator.MemoryMatch{"See you.": nil}; !reflect.DeepEqual(want, matches) {
+		t.Fatalf("expected %v, got %v", want, matches)
+	}
+
+	got, err := fileUC.Get(context.Background(), "isid/translated-en-to-vi-movie.srt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "1\n00:00:01,000 --> 00:00:02,000\nXin chào.\n\n2\n00:00:03,000 --> 00:00:04,000\nSEE YOU.\n\n3\n00:00:05,000 --> 00:00:06,000\nXin chào.\n"; string(got) != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslateUseCase_Execute_MemoryDocx(t *testing.T) {
+	// The document is echoed back, noting it and the translation memory
+	// matches sent with it
+	var docs [][]byte
+	var matches [][]translator.MemoryMatch
+	translr := translatorFunc(func(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+		docs = append(docs, b)
+		matches = append(matches, translator.Memory(ctx))
+		return b, nil
+	})
+	uc, mock, fileUC, _ := newTestTranslateUseCaseWith(t, translr, ReuseNone, func(db *sql.DB) TranslateOptions {
+		return TranslateOptions{MemoryUC: memory.NewTranslationMemoryUseCase(memoryPG.NewPostgresqlTranslationMemoryRepository(db), 0.75)}
+	})
+
+	if err := fileUC.Persist(context.Background(), newDocx(t, "Hello", "Read the docs.", "See you."), "isid/file.docx"); err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectQuery(`SELECT source_text, target_text FROM translation_units`).
+		WithArgs("en", "vi", pq.Array([]string{"Hello", "Read the docs.", "See you."})).
+		WillReturnRows(sqlmock.NewRows([]string{"source_text", "target_text"}).AddRow("Hello", "Xin chào"))
+	mock.ExpectQuery(`SELECT (.+) FROM unnest`).
+		WithArgs("en", "vi", pq.Array([]string{"Read the docs.", "See you."}), 0.75).
+		WillReturnRows(sqlmock.NewRows([]string{"source", "source_text", "target_text", "score"}).AddRow("Read the docs.", "Read the docs!", "Đọc tài liệu!", 0.8))
+
+	// One exact hit, one fuzzy hit and one miss
+	mock.ExpectQuery("INSERT INTO translated_files").
+		WithArgs(0, "translated-en-to-vi-file.docx", "vi", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 0, "[]", 1, 1, 1, sqlmock.AnyArg(), sqlmock.AnyArg(), "isid").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	task := &queue.TranslateTask{Isid: "isid", Filename: "file.docx", MimeType: format.Docx, SourceLang: "en", TargetLang: "vi"}
+	if err := uc.execute(context.Background(), task, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// The document goes whole, the exact hit already translated and the close
+	// one along with it
+	if want := [][]translator.MemoryMatch{{{Source: "Read the docs!", Target: "Đọc tài liệu!", Score: 0.8}}}; !reflect.DeepEqual(want, matches) {
+		t.Fatalf("expected %v, got %v", want, matches)
+	}
+
+	got, err := format.Paragraphs(docs[0], format.Docx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Xin chào", "Read the docs.", "See you."}; !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslateUseCase_TranslateTask_MaskLost(t *testing.T) {
+	rules, err := mask.NewRules()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The translator drops the placeholder, whatever the document had in it
+	translr := translator.NewMaskingTranslator(translatorFunc(func(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+		return []byte("Xin chào"), nil
+	}), rules)
+	uc, mock, fileUC, _ := newTestTranslateUseCase(t, translr, ReuseNone)
+
+	if err := fileUC.Persist(context.Background(), []byte("Hello {{name}}"), "isid/file.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	task := &queue.TranslateTask{Isid: "isid", Filename: "file.txt", MimeType: format.Text, SourceLang: "en", TargetLang: "vi"}
+	stage, err := uc.translateTask(context.Background(), task)
+	if !errors.Is(err, mask.ErrLost) {
+		t.Fatalf("expected %v, got %v", mask.ErrLost, err)
+	}
+	if stage != "mask" {
+		t.Fatalf("expected stage %v, got %v", "mask", stage)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslateUseCase_Execute_Cancelled(t *testing.T) {
+	uc, mock, _, fileTracker := newTestTranslateUseCase(t, unexpectedTranslation(t), ReuseNone)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A cancelled task is neither retried nor dead-lettered
+	task := &queue.TranslateTask{Isid: "isid", Filename: "file.docx", SourceLang: "en", TargetLang: "vi"}
+	if err := uc.execute(ctx, task, ""); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if task.Attempts != 0 {
+		t.Fatalf("expected no attempt to be spent, got %v", task.Attempts)
+	}
+
+	status, err := fileTracker.Get(context.Background(), "isid_vi_file.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != tracker.StatusInterrupted {
+		t.Fatalf("expected %v, got %v", tracker.StatusInterrupted, status.Status)
+	}
+
+	// The task is handed back to the queue for another worker
+	if got, _ := uc.translateQueue.Take(context.Background()); !reflect.DeepEqual(task, got) {
+		t.Fatalf("expected %v, got %v", task, got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslateUseCase_InterruptStale(t *testing.T) {
+	uc, _, _, fileTracker := newTestTranslateUseCase(t, unexpectedTranslation(t), ReuseNone)
+	ctx := context.Background()
+
+	fileTracker.Create(ctx, &tracker.FileStatus{Key: "isid_vi_a.docx", Status: "in progress", Stage: tracker.StageTranslating, TargetLang: "vi"})
+	fileTracker.Create(ctx, &tracker.FileStatus{Key: "isid_vi_b.docx", Status: "in progress", Stage: tracker.StageQueued})
+	fileTracker.Create(ctx, &tracker.FileStatus{Key: "isid_vi_c.docx", Status: "fail:translate", Stage: tracker.StageFailed})
+
+	// Nothing has been still for an hour
+	if n, err := uc.InterruptStale(ctx, time.Hour); err != nil || n != 0 {
+		t.Fatalf("expected no stale status, got %v, %v", n, err)
+	}
+
+	time.Sleep(time.Millisecond)
+
+	if n, err := uc.InterruptStale(ctx, time.Nanosecond); err != nil || n != 1 {
+		t.Fatalf("expected 1 stale status, got %v, %v", n, err)
+	}
+
+	got, err := fileTracker.Get(ctx, "isid_vi_a.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != tracker.StatusInterrupted || got.Stage != tracker.StageFailed || got.TargetLang != "vi" {
+		t.Fatalf("unexpected status %v", got)
+	}
+
+	if got, _ := fileTracker.Get(ctx, "isid_vi_b.docx"); got.Status != "in progress" {
+		t.Fatalf("expected queued file to be left alone, got %v", got)
+	}
+}
+
+// blockingTranslator echoes documents back once released, announcing every
+// translation it starts.
+type blockingTranslator struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingTranslator() *blockingTranslator {
+	return &blockingTranslator{make(chan struct{}), make(chan struct{})}
+}
+
+func (t *blockingTranslator) Translate(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+	t.started <- struct{}{}
+	<-t.release
+	return b, nil
+}
+
+// waitFor fails the test unless c delivers or is closed within a second.
+func waitFor(t *testing.T, c <-chan struct{}, what string) {
+	select {
+	case <-c:
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+}
+
+func TestTranslateUseCase_ListenAndExecute_Concurrent(t *testing.T) {
+	translr := newBlockingTranslator()
+	uc, mock, _, _ := newTestTranslateUseCase(t, translr, ReuseNone)
+	uc.workerPool = NewWorkerPool(3)
+	uc.translateQueue = queue.NewChannelTranslateQueue(make(chan *queue.TranslateTask, 3))
+	mock.MatchExpectationsInOrder(false)
+
+	for i, lang := range []string{"vi", "ja", "fr"} {
+		mock.ExpectQuery("INSERT INTO translated_files").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(i + 1))
+
+		task := &queue.TranslateTask{Isid: "isid", Filename: "file.docx", SourceLang: "en", TargetLang: lang}
+		if err := uc.translateQueue.Add(context.Background(), task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		uc.ListenAndExecute(ctx, context.Background())
+	}()
+
+	// Every worker translates one of the tasks at the same time
+	for range 3 {
+		select {
+		case <-translr.started:
+		case <-time.After(time.Second):
+			t.Fatal("expected 3 translations at once")
+		}
+	}
+
+	for _, s := range uc.WorkerStates() {
+		if !s.Busy {
+			t.Fatalf("expected every worker to be busy, got %v", uc.WorkerStates())
+		}
+	}
+
+	close(translr.release)
+	cancel()
+	waitFor(t, done, "workers to stop")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslateUseCase_ListenAndExecute_Drain(t *testing.T) {
+	translr := newBlockingTranslator()
+	uc, mock, fileUC, _ := newTestTranslateUseCase(t, translr, ReuseNone)
+	uc.translateQueue = queue.NewChannelTranslateQueue(make(chan *queue.TranslateTask, 2))
+
+	mock.ExpectQuery("INSERT INTO translated_files").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	for _, lang := range []string{"vi", "ja"} {
+		task := &queue.TranslateTask{Isid: "isid", Filename: "file.docx", SourceLang: "en", TargetLang: lang}
+		if err := uc.translateQueue.Add(context.Background(), task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		uc.ListenAndExecute(ctx, context.Background())
+	}()
+
+	waitFor(t, translr.started, "a translation to start")
+	cancel()
+
+	// The task already taken is finished before returning
+	select {
+	case <-done:
+		t.Fatal("expected ListenAndExecute to wait for the task in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(translr.release)
+	waitFor(t, done, "workers to stop")
+
+	if _, err := fileUC.Get(context.Background(), "isid/translated-en-to-vi-file.docx"); err != nil {
+		t.Fatal(err)
+	}
+
+	// No other task is taken once cancelled
+	if got, _ := uc.translateQueue.Take(context.Background()); got == nil || got.TargetLang != "ja" {
+		t.Fatalf("expected the ja task to be left on the queue, got %v", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslateUseCase_Execute_Heartbeat(t *testing.T) {
+	translr := newBlockingTranslator()
+	uc, mock, _, _ := newTestTranslateUseCase(t, translr, ReuseNone)
+	uc.staleAfter = 150 * time.Millisecond
+
+	mock.ExpectQuery("INSERT INTO translated_files").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	ctx := context.Background()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		task := &queue.TranslateTask{Isid: "isid", Filename: "file.docx", SourceLang: "en", TargetLang: "vi"}
+		if err := uc.execute(ctx, task, ""); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	waitFor(t, translr.started, "the translation to start")
+
+	// The translator reports no progress, yet the file isn't stale
+	time.Sleep(2 * uc.staleAfter)
+	if n, err := uc.InterruptStale(ctx, uc.staleAfter); err != nil || n != 0 {
+		t.Fatalf("expected no stale status, got %v, %v", n, err)
+	}
+
+	close(translr.release)
+	waitFor(t, done, "the translation to finish")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// lockedTracker is a file tracker shared with other processes, which hold
+// every lock.
+type lockedTracker struct {
+	*tracker.MemoryFileTracker
+}
+
+func (t lockedTracker) TryLock(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	return false, nil
+}
+
+func TestTranslateUseCase_SweepStale_Locked(t *testing.T) {
+	uc, _, _, fileTracker := newTestTranslateUseCase(t, unexpectedTranslation(t), ReuseNone)
+	uc.staleAfter = time.Nanosecond
+	ctx := context.Background()
+
+	fileTracker.Create(ctx, &tracker.FileStatus{Key: "isid_vi_a.docx", Status: "in progress", Stage: tracker.StageTranslating})
+	time.Sleep(time.Millisecond)
+
+	// Another process is sweeping
+	uc.fileTracker = lockedTracker{fileTracker}
+	if n, err := uc.sweepStale(ctx); err != nil || n != 0 {
+		t.Fatalf("expected no sweep, got %v, %v", n, err)
+	}
+
+	uc.fileTracker = fileTracker
+	if n, err := uc.sweepStale(ctx); err != nil || n != 1 {
+		t.Fatalf("expected 1 stale status, got %v, %v", n, err)
+	}
+}
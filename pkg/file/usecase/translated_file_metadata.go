@@ -21,6 +21,10 @@ func (uc *TranslatedFileMetadataUseCase) ListByIsid(isid string) ([]*entity.Tran
 	return uc.repo.ListByIsid(isid)
 }
 
+func (uc *TranslatedFileMetadataUseCase) FindBySHA256(sha256 string, sourceLang string, targetLang string, isid string) (*entity.TranslatedFileMetadata, error) {
+	return uc.repo.FindBySHA256(sha256, sourceLang, targetLang, isid)
+}
+
 func (uc *TranslatedFileMetadataUseCase) ListByIds(ids []int) ([]*entity.TranslatedFileMetadata, error) {
 	return uc.repo.ListByIds(ids)
 }
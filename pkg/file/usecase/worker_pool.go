@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+)
+
+type WorkerState struct {
+	Id    int       `json:"id"`
+	Busy  bool      `json:"busy"`
+	Since time.Time `json:"since"`
+}
+
+// WorkerPool keeps track of what each translate worker is doing.
+// Its size bounds how many tasks are translated at once.
+type WorkerPool struct {
+	mu     sync.RWMutex
+	states []WorkerState
+}
+
+func NewWorkerPool(size int) *WorkerPool {
+	if size < 1 {
+		size = 1
+	}
+
+	now := time.Now()
+	states := make([]WorkerState, size)
+	for i := range states {
+		states[i] = WorkerState{Id: i, Since: now}
+	}
+
+	return &WorkerPool{states: states}
+}
+
+func (p *WorkerPool) Size() int {
+	return len(p.states)
+}
+
+func (p *WorkerPool) setBusy(id int, busy bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.states[id].Busy = busy
+	p.states[id].Since = time.Now()
+}
+
+// States returns a snapshot of every worker's state.
+func (p *WorkerPool) States() []WorkerState {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	out := make([]WorkerState, len(p.states))
+	copy(out, p.states)
+
+	return out
+}
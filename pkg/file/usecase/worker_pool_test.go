@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"testing"
+)
+
+func TestWorkerPool(t *testing.T) {
+	p := NewWorkerPool(3)
+
+	if p.Size() != 3 {
+		t.Fatalf("expected %v, got %v", 3, p.Size())
+	}
+
+	p.setBusy(1, true)
+
+	states := p.States()
+	for _, s := range states {
+		if s.Busy != (s.Id == 1) {
+			t.Fatalf("expected only worker 1 to be busy, got %v", states)
+		}
+	}
+
+	// States is a snapshot
+	states[0].Busy = true
+	if p.States()[0].Busy {
+		t.Fatal("expected worker 0 to be idle")
+	}
+}
+
+func TestWorkerPool_MinSize(t *testing.T) {
+	p := NewWorkerPool(0)
+
+	if p.Size() != 1 {
+		t.Fatalf("expected %v, got %v", 1, p.Size())
+	}
+}
@@ -0,0 +1,161 @@
+package format
+
+import (
+	"archive/zip"
+	"bytes"
+	"doc-translate-go/pkg/docx"
+	"doc-translate-go/pkg/ooxml"
+	"doc-translate-go/pkg/textdoc"
+	"doc-translate-go/pkg/wordcount"
+	"encoding/xml"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// textParts are the parts of a presentation or a spreadsheet holding text.
+var textParts = map[string]*regexp.Regexp{
+	Pptx: regexp.MustCompile(`^ppt/(slides/slide|notesSlides/notesSlide)\d+\.xml$`),
+	Xlsx: regexp.MustCompile(`^xl/(sharedStrings|worksheets/sheet\d+)\.xml$`),
+}
+
+// IsTextPart tells whether the part named name of a presentation or a
+// spreadsheet holds text in <t> elements.
+func IsTextPart(mimeType string, name string) bool {
+	parts, ok := textParts[mimeType]
+	return ok && parts.MatchString(name)
+}
+
+// paragraphs are the elements text is counted by, so words split across runs
+// count once: DrawingML paragraphs, shared strings and inline strings.
+var paragraphs = map[string]bool{
+	"p":  true,
+	"si": true,
+	"is": true,
+}
+
+// Count counts the translatable text of a file of the given MIME type.
+func Count(b []byte, mimeType string) (wordcount.Count, error) {
+	if mimeType == Docx {
+		d, err := docx.Open(b)
+		if err != nil {
+			return wordcount.Count{}, err
+		}
+		return d.Count(), nil
+	}
+
+	if IsText(mimeType) {
+		d, err := Segment(b, mimeType)
+		if err != nil {
+			return wordcount.Count{}, err
+		}
+		return d.Count(), nil
+	}
+
+	if IsSubtitle(mimeType) {
+		return countCues(b, mimeType)
+	}
+
+	parts, ok := textParts[mimeType]
+	if !ok {
+		return wordcount.Count{}, ErrUnsupported
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return wordcount.Count{}, err
+	}
+
+	var c wordcount.Count
+	for _, f := range r.File {
+		if !parts.MatchString(f.Name) {
+			continue
+		}
+
+		content, err := ooxml.ReadFile(r, f.Name)
+		if err != nil {
+			return wordcount.Count{}, err
+		}
+
+		partCount, err := countPart(content)
+		if err != nil {
+			return wordcount.Count{}, err
+		}
+		c = c.Add(partCount)
+	}
+
+	return c, nil
+}
+
+// countCues counts the text of every cue, without its tags.
+func countCues(b []byte, mimeType string) (wordcount.Count, error) {
+	f, err := Subtitles(b, mimeType)
+	if err != nil {
+		return wordcount.Count{}, err
+	}
+
+	var c wordcount.Count
+	for _, cue := range f.Cues() {
+		d, err := textdoc.ParseCue([]byte(cue.Text))
+		if err != nil {
+			return wordcount.Count{}, err
+		}
+		c = c.Add(d.Count())
+	}
+
+	return c, nil
+}
+
+// countPart counts the text of every paragraph of a part.
+func countPart(b []byte) (wordcount.Count, error) {
+	texts, err := partParagraphs(b)
+	if err != nil {
+		return wordcount.Count{}, err
+	}
+
+	var c wordcount.Count
+	for _, text := range texts {
+		c = c.Add(wordcount.Text(text))
+	}
+	return c, nil
+}
+
+// partParagraphs returns the text of every <t> element, joined paragraph by
+// paragraph.
+func partParagraphs(b []byte) ([]string, error) {
+	d := xml.NewDecoder(bytes.NewReader(b))
+
+	var result []string
+	var text strings.Builder
+	inText := false
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			inText = t.Name.Local == "t"
+		case xml.EndElement:
+			inText = false
+			if paragraphs[t.Name.Local] {
+				result = append(result, text.String())
+				text.Reset()
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+
+	if text.Len() > 0 {
+		result = append(result, text.String())
+	}
+	return result, nil
+}
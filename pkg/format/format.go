@@ -0,0 +1,158 @@
+package format
+
+import (
+	"archive/zip"
+	"bytes"
+	"doc-translate-go/pkg/ooxml"
+	"encoding/xml"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// MIME types of the supported formats
+const (
+	Docx = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	Pptx = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+	Xlsx = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+	// Text formats are segmented in-process, only their text is sent for
+	// translation, as Text
+	Text     = "text/plain"
+	Markdown = "text/markdown"
+	HTML     = "text/html"
+
+	// Subtitles have the text of their cues translated, with the timings kept
+	Srt = "application/x-subrip"
+	Vtt = "text/vtt"
+)
+
+var (
+	ErrUnsupported       = errors.New("unsupported format")
+	ErrExtensionMismatch = errors.New("file extension doesn't match its content")
+)
+
+// names maps the names formats are configured by to their MIME types.
+var names = map[string]string{
+	"docx": Docx,
+	"pptx": Pptx,
+	"xlsx": Xlsx,
+	"txt":  Text,
+	"md":   Markdown,
+	"html": HTML,
+	"srt":  Srt,
+	"vtt":  Vtt,
+}
+
+// extensions maps MIME types to the file extensions they may be uploaded with.
+var extensions = map[string][]string{
+	Docx:     {".docx"},
+	Pptx:     {".pptx"},
+	Xlsx:     {".xlsx"},
+	Text:     {".txt"},
+	Markdown: {".md", ".markdown"},
+	HTML:     {".html", ".htm"},
+	Srt:      {".srt"},
+	Vtt:      {".vtt"},
+}
+
+// mainParts maps the content type of an OOXML package's main part, as
+// declared in [Content_Types].xml, to the package's MIME type.
+var mainParts = map[string]string{
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml":   Docx,
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation.main+xml": Pptx,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml":         Xlsx,
+}
+
+// Lookup returns the MIME type of a format by name, e.g. docx.
+func Lookup(name string) (string, bool) {
+	mimeType, ok := names[strings.ToLower(strings.TrimSpace(name))]
+	return mimeType, ok
+}
+
+// Extension returns the usual file extension of a MIME type, with its dot.
+func Extension(mimeType string) string {
+	if exts := extensions[mimeType]; len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+// IsText tells whether a MIME type is one of the text formats.
+func IsText(mimeType string) bool {
+	return mimeType == Text || mimeType == Markdown || mimeType == HTML
+}
+
+// IsSubtitle tells whether a MIME type is one of the subtitle formats.
+func IsSubtitle(mimeType string) bool {
+	return mimeType == Srt || mimeType == Vtt
+}
+
+// Detect returns the MIME type of a file from its content. The filename's
+// extension must agree with it, so a renamed file isn't taken for another.
+// Text has no signature to tell plain text, Markdown, HTML and subtitles
+// apart, so the extension decides between them once the content is known to
+// be text.
+func Detect(b []byte, filename string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+
+	if isText(b) {
+		for _, mimeType := range []string{Text, Markdown, HTML, Srt, Vtt} {
+			if slices.Contains(extensions[mimeType], ext) {
+				return mimeType, nil
+			}
+		}
+		return "", ErrUnsupported
+	}
+
+	mimeType, err := detectOoxml(b)
+	if err != nil {
+		return "", err
+	}
+
+	if !slices.Contains(extensions[mimeType], ext) {
+		return "", ErrExtensionMismatch
+	}
+
+	return mimeType, nil
+}
+
+// isText tells whether b is UTF-8 text, which binary formats never are.
+func isText(b []byte) bool {
+	return utf8.Valid(b) && !bytes.ContainsRune(b, 0)
+}
+
+type contentTypes struct {
+	Overrides []struct {
+		PartName    string `xml:"PartName,attr"`
+		ContentType string `xml:"ContentType,attr"`
+	} `xml:"Override"`
+}
+
+// detectOoxml tells an OOXML package apart by the content type of its main part.
+func detectOoxml(b []byte) (string, error) {
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return "", ErrUnsupported
+	}
+
+	content, err := ooxml.ReadFile(r, "[Content_Types].xml")
+	if err != nil {
+		return "", ErrUnsupported
+	}
+
+	var types contentTypes
+	if err := xml.Unmarshal(content, &types); err != nil {
+		return "", ErrUnsupported
+	}
+
+	for _, o := range types.Overrides {
+		if mimeType, ok := mainParts[o.ContentType]; ok {
+			return mimeType, nil
+		}
+	}
+
+	return "", ErrUnsupported
+}
@@ -0,0 +1,229 @@
+package format
+
+import (
+	"archive/zip"
+	"bytes"
+	"doc-translate-go/pkg/ooxml"
+	"doc-translate-go/pkg/wordcount"
+	"errors"
+	"os"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// newPackage zips files into an OOXML package whose main part has contentType.
+func newPackage(t *testing.T, mainPart string, contentType string, files map[string]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+
+	files["[Content_Types].xml"] = `<?xml version="1.0" encoding="UTF-8"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/` + mainPart + `" ContentType="` + contentType + `"/>
+</Types>`
+
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func newPptx(t *testing.T) []byte {
+	return newPackage(t, "ppt/presentation.xml", "application/vnd.openxmlformats-officedocument.presentationml.presentation.main+xml", map[string]string{
+		"ppt/presentation.xml": `<p:presentation xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"/>`,
+		"ppt/slides/slide1.xml": `<p:sld xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main">
+<p:txBody><a:p><a:r><a:t>Quarterly</a:t></a:r><a:r><a:t> results</a:t></a:r></a:p><a:p><a:r><a:t>Next steps</a:t></a:r></a:p></p:txBody>
+</p:sld>`,
+		"ppt/notesSlides/notesSlide1.xml": `<p:notes xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main">
+<a:p><a:r><a:t>Speak slowly</a:t></a:r></a:p>
+</p:notes>`,
+	})
+}
+
+func newXlsx(t *testing.T) []byte {
+	return newPackage(t, "xl/workbook.xml", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml", map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"/>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>Revenue</t></si><si><r><t>Net</t></r><r><t xml:space="preserve"> income</t></r></si>
+</sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetData><row><c t="s"><v>0</v></c><c t="inlineStr"><is><t>Total</t></is></c><c><v>42</v></c></row></sheetData>
+</worksheet>`,
+	})
+}
+
+func TestDetect(t *testing.T) {
+	docx, err := os.ReadFile("../docx/testdata/sample.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		b        []byte
+		filename string
+		want     string
+		wantErr  error
+	}{
+		{"docx", docx, "report.docx", Docx, nil},
+		{"pptx", newPptx(t), "deck.PPTX", Pptx, nil},
+		{"xlsx", newXlsx(t), "sheet.xlsx", Xlsx, nil},
+		{"renamed", newXlsx(t), "sheet.docx", "", ErrExtensionMismatch},
+		{"text", []byte("plain text"), "notes.txt", Text, nil},
+		{"markdown", []byte("# Title"), "README.MD", Markdown, nil},
+		{"html", []byte("<p>Hi</p>"), "index.htm", HTML, nil},
+		{"srt", []byte("1\n00:00:01,000 --> 00:00:02,000\nHi\n"), "movie.srt", Srt, nil},
+		{"vtt", []byte("WEBVTT\n"), "movie.vtt", Vtt, nil},
+		{"text as docx", []byte("plain text"), "notes.docx", "", ErrUnsupported},
+		{"docx as text", docx, "report.txt", "", ErrExtensionMismatch},
+		{"binary", []byte{0x25, 0x50, 0x44, 0x46, 0x00, 0xff}, "file.txt", "", ErrUnsupported},
+		{"other zip", newPackage(t, "a.xml", "application/xml", map[string]string{}), "a.docx", "", ErrUnsupported},
+	}
+
+	for _, tt := range tests {
+		got, err := Detect(tt.b, tt.filename)
+		if got != tt.want || err != tt.wantErr {
+			t.Fatalf("%s: expected %q, %v, got %q, %v", tt.name, tt.want, tt.wantErr, got, err)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	if got, ok := Lookup(" PPTX "); !ok || got != Pptx {
+		t.Fatalf("expected %s, got %s", Pptx, got)
+	}
+
+	if _, ok := Lookup("pdf"); ok {
+		t.Fatal("expected pdf to be unknown")
+	}
+}
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		b        []byte
+		mimeType string
+		want     wordcount.Count
+	}{
+		// Quarterly results | Next steps | Speak slowly
+		{"pptx", newPptx(t), Pptx, wordcount.Count{Words: 6, Characters: 36, Tokens: 12}},
+		// Revenue | Net income | Total, numbers aren't text
+		{"xlsx", newXlsx(t), Xlsx, wordcount.Count{Words: 4, Characters: 21, Tokens: 7}},
+		// Tags aren't text
+		{"html", []byte("<p>Hello <b>world</b></p>"), HTML, wordcount.Count{Words: 2, Characters: 10, Tokens: 4}},
+		// Nor are timings
+		{"vtt", []byte("WEBVTT\n\n00:01.000 --> 00:02.000\nHello <i>world</i>\n"), Vtt, wordcount.Count{Words: 2, Characters: 10, Tokens: 4}},
+	}
+
+	for _, tt := range tests {
+		got, err := Count(tt.b, tt.mimeType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	if _, err := Count([]byte("%PDF-1.7"), "application/pdf"); err != ErrUnsupported {
+		t.Fatalf("expected %v, got %v", ErrUnsupported, err)
+	}
+}
+
+func TestParagraphs(t *testing.T) {
+	tests := []struct {
+		name     string
+		b        []byte
+		mimeType string
+		want     []string
+	}{
+		// Runs join into their paragraph, in part order
+		{"pptx", newPptx(t), Pptx, []string{"Next steps", "Quarterly results", "Speak slowly"}},
+		{"xlsx", newXlsx(t), Xlsx, []string{"Net income", "Revenue", "Total"}},
+		{"html", []byte("<h1>Title</h1><p>Hello <b>world</b></p>"), HTML, []string{"Title", "Hello world"}},
+		{"vtt", []byte("WEBVTT\n\n00:01.000 --> 00:02.000\nHello\n<i>world</i>\n\n00:02.000 --> 00:03.000\nBye\n"), Vtt, []string{"Hello world", "Bye"}},
+	}
+
+	for _, tt := range tests {
+		got, err := Paragraphs(tt.b, tt.mimeType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Zipped parts come in no particular order
+		if tt.mimeType == Pptx || tt.mimeType == Xlsx {
+			slices.Sort(got)
+		}
+		if !reflect.DeepEqual(tt.want, got) {
+			t.Fatalf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestRewriteParts(t *testing.T) {
+	b := newXlsx(t)
+
+	texts, err := TextElements(b, Xlsx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Zipped parts come in no particular order
+	got := slices.Clone(texts)
+	slices.Sort(got)
+	if want := []string{" income", "Net", "Revenue", "Total"}; !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	// Texts are rewritten in the order they're listed
+	i := 0
+	rewritten, err := RewriteParts(b, Xlsx, func(text string) string {
+		if text != texts[i] {
+			t.Fatalf("expected %q, got %q", texts[i], text)
+		}
+		i++
+		if text == "Revenue" {
+			return "Doanh thu & lãi"
+		}
+		return text
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = Paragraphs(rewritten, Xlsx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(got)
+	if want := []string{"Doanh thu & lãi", "Net income", "Total"}; !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestCount_PartTooLarge(t *testing.T) {
+	prev := ooxml.MaxPartSize
+	ooxml.MaxPartSize = 64
+	t.Cleanup(func() { ooxml.MaxPartSize = prev })
+
+	// Counting happens on upload, so parts past the limit are refused unread
+	for _, tt := range []struct {
+		b        []byte
+		mimeType string
+	}{
+		{newPptx(t), Pptx},
+		{newXlsx(t), Xlsx},
+	} {
+		if _, err := Count(tt.b, tt.mimeType); !errors.Is(err, ooxml.ErrPartTooLarge) {
+			t.Fatalf("%v: expected %v, got %v", tt.mimeType, ooxml.ErrPartTooLarge, err)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package format
+
+import (
+	"archive/zip"
+	"bytes"
+	"doc-translate-go/pkg/docx"
+	"doc-translate-go/pkg/ooxml"
+	"doc-translate-go/pkg/textdoc"
+	"strings"
+)
+
+// Paragraphs returns the translatable text of a file of the given MIME type,
+// without its markup, one entry per paragraph, segment or subtitle cue in
+// document order. Runs of whitespace become a single space and empty
+// paragraphs are left out.
+func Paragraphs(b []byte, mimeType string) ([]string, error) {
+	var texts []string
+
+	switch {
+	case mimeType == Docx:
+		d, err := docx.Open(b)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range d.Segments() {
+			texts = append(texts, s.Text())
+		}
+	case IsText(mimeType):
+		d, err := Segment(b, mimeType)
+		if err != nil {
+			return nil, err
+		}
+		texts = d.Texts()
+	case IsSubtitle(mimeType):
+		f, err := Subtitles(b, mimeType)
+		if err != nil {
+			return nil, err
+		}
+		for _, cue := range f.Cues() {
+			d, err := textdoc.ParseCue([]byte(cue.Text))
+			if err != nil {
+				return nil, err
+			}
+			texts = append(texts, strings.Join(d.Texts(), " "))
+		}
+	default:
+		parts, ok := textParts[mimeType]
+		if !ok {
+			return nil, ErrUnsupported
+		}
+
+		r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range r.File {
+			if !parts.MatchString(f.Name) {
+				continue
+			}
+
+			content, err := ooxml.ReadFile(r, f.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			partTexts, err := partParagraphs(content)
+			if err != nil {
+				return nil, err
+			}
+			texts = append(texts, partTexts...)
+		}
+	}
+
+	var result []string
+	for _, text := range texts {
+		if text = strings.Join(strings.Fields(text), " "); text != "" {
+			result = append(result, text)
+		}
+	}
+	return result, nil
+}
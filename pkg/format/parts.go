@@ -0,0 +1,136 @@
+package format
+
+import (
+	"archive/zip"
+	"bytes"
+	"doc-translate-go/pkg/ooxml"
+	"encoding/xml"
+	"io"
+)
+
+// TextElements returns the text of every <t> element of the text parts of a
+// presentation or a spreadsheet, in the order RewriteParts rewrites them.
+func TextElements(b []byte, mimeType string) ([]string, error) {
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+
+	var texts []string
+	for _, f := range r.File {
+		if !IsTextPart(mimeType, f.Name) {
+			continue
+		}
+
+		part, err := ooxml.ReadFile(r, f.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = rewriteTexts(part, func(text string) string {
+			texts = append(texts, text)
+			return text
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return texts, nil
+}
+
+// RewriteParts returns the presentation or spreadsheet with the text of every
+// <t> element of its text parts replaced by rewrite. Everything else is copied
+// as it is.
+func RewriteParts(b []byte, mimeType string, rewrite func(text string) string) ([]byte, error) {
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+
+	for _, f := range r.File {
+		if !IsTextPart(mimeType, f.Name) {
+			// Unchanged files are copied without recompressing
+			if err := w.Copy(f); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		part, err := ooxml.ReadFile(r, f.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		part, err = rewriteTexts(part, rewrite)
+		if err != nil {
+			return nil, err
+		}
+
+		header := f.FileHeader
+		fw, err := w.CreateHeader(&header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fw.Write(part); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// rewriteTexts replaces the content of the <t> elements of a part whose text
+// rewrite changes, leaving the rest of the part byte for byte.
+func rewriteTexts(b []byte, rewrite func(text string) string) ([]byte, error) {
+	d := xml.NewDecoder(bytes.NewReader(b))
+
+	var out bytes.Buffer
+	var text bytes.Buffer
+	prev, start := 0, -1
+
+	for {
+		offset := int(d.InputOffset())
+
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "t" {
+				start = int(d.InputOffset())
+				text.Reset()
+			}
+		case xml.CharData:
+			if start >= 0 {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			if t.Name.Local != "t" || start < 0 {
+				continue
+			}
+
+			if rewritten := rewrite(text.String()); rewritten != text.String() {
+				out.Write(b[prev:start])
+				xml.EscapeText(&out, []byte(rewritten))
+				prev = offset
+			}
+			start = -1
+		}
+	}
+	out.Write(b[prev:])
+
+	return out.Bytes(), nil
+}
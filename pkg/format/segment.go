@@ -0,0 +1,33 @@
+package format
+
+import (
+	"doc-translate-go/pkg/subtitle"
+	"doc-translate-go/pkg/textdoc"
+)
+
+// Segment splits a file of one of the text formats into the segments to
+// translate and the markup around them.
+func Segment(b []byte, mimeType string) (*textdoc.Document, error) {
+	switch mimeType {
+	case Text:
+		return textdoc.ParseText(b)
+	case Markdown:
+		return textdoc.ParseMarkdown(b)
+	case HTML:
+		return textdoc.ParseHTML(b)
+	}
+
+	return nil, ErrUnsupported
+}
+
+// Subtitles parses a file of one of the subtitle formats.
+func Subtitles(b []byte, mimeType string) (*subtitle.File, error) {
+	switch mimeType {
+	case Srt:
+		return subtitle.ParseSRT(b)
+	case Vtt:
+		return subtitle.ParseVTT(b)
+	}
+
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// Glossary is how terms of one language must be translated into another.
+// It belongs to the user who created it or, when Team is set, to every
+// member of that team.
+type Glossary struct {
+	Id         int       `json:"id"`
+	Name       string    `json:"name"`
+	SourceLang string    `json:"source_lang"`
+	TargetLang string    `json:"target_lang"`
+	Team       string    `json:"team"`
+	Terms      []Term    `json:"terms"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	CreatedBy  string    `json:"created_by"`
+}
+
+// Term is a source term and its translation. Translations missing a required
+// term are flagged.
+type Term struct {
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	Required bool   `json:"required"`
+}
+
+// Flag is a paragraph whose source uses a required term its translation
+// doesn't. Paragraph is its position in the document, from 0.
+type Flag struct {
+	Paragraph int    `json:"paragraph"`
+	Text      string `json:"text"`
+	Source    string `json:"source"`
+	Target    string `json:"target"`
+}
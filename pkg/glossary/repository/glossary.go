@@ -0,0 +1,14 @@
+package repository
+
+import "doc-translate-go/pkg/glossary/entity"
+
+// GlossaryRepository operates against a database
+// or any data persistent layer.
+type GlossaryRepository interface {
+	Create(g *entity.Glossary) (int, error)
+	GetById(id int) (*entity.Glossary, error)
+	// ListByOwner lists the glossaries created by isid or shared with team.
+	ListByOwner(isid string, team string) ([]*entity.Glossary, error)
+	Update(g *entity.Glossary) error
+	DeleteById(id int) error
+}
@@ -0,0 +1,126 @@
+package postgresql
+
+import (
+	"database/sql"
+	"doc-translate-go/pkg/db"
+	"doc-translate-go/pkg/glossary/entity"
+	"doc-translate-go/pkg/glossary/repository"
+	"encoding/json"
+)
+
+type PostgresqlGlossaryRepository struct {
+	querier db.Querier
+}
+
+func NewPostgresqlGlossaryRepository(querier db.Querier) *PostgresqlGlossaryRepository {
+	return &PostgresqlGlossaryRepository{querier}
+}
+
+func (r *PostgresqlGlossaryRepository) Create(g *entity.Glossary) (int, error) {
+	terms, err := marshalTerms(g.Terms)
+	if err != nil {
+		return 0, err
+	}
+
+	cmd := `INSERT INTO glossaries (name, source_language, target_language, team, terms, created_at, updated_at, created_by)
+                VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+                RETURNING id;`
+
+	row := r.querier.QueryRow(cmd, g.Name, g.SourceLang, g.TargetLang, g.Team, terms, g.CreatedAt, g.UpdatedAt, g.CreatedBy)
+
+	var id int
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetById returns nil when there's no glossary with id.
+func (r *PostgresqlGlossaryRepository) GetById(id int) (*entity.Glossary, error) {
+	cmd := `SELECT id, name, source_language, target_language, team, terms, created_at, updated_at, created_by
+                FROM glossaries
+                WHERE id = $1;`
+
+	g, err := scanGlossary(r.querier.QueryRow(cmd, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func (r *PostgresqlGlossaryRepository) ListByOwner(isid string, team string) ([]*entity.Glossary, error) {
+	cmd := `SELECT id, name, source_language, target_language, team, terms, created_at, updated_at, created_by
+                FROM glossaries
+                WHERE created_by = $1 OR ($2 <> '' AND team = $2)
+                ORDER BY name, id;`
+
+	rows, err := r.querier.Query(cmd, isid, team)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*entity.Glossary
+	for rows.Next() {
+		g, err := scanGlossary(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (r *PostgresqlGlossaryRepository) Update(g *entity.Glossary) error {
+	terms, err := marshalTerms(g.Terms)
+	if err != nil {
+		return err
+	}
+
+	cmd := `UPDATE glossaries SET name = $1, source_language = $2, target_language = $3, team = $4, terms = $5, updated_at = $6 WHERE id = $7;`
+	_, err = r.querier.Exec(cmd, g.Name, g.SourceLang, g.TargetLang, g.Team, terms, g.UpdatedAt, g.Id)
+	return err
+}
+
+func (r *PostgresqlGlossaryRepository) DeleteById(id int) error {
+	cmd := `DELETE FROM glossaries WHERE id = $1;`
+	_, err := r.querier.Exec(cmd, id)
+	return err
+}
+
+func scanGlossary(row interface{ Scan(dest ...any) error }) (*entity.Glossary, error) {
+	var g entity.Glossary
+	var terms []byte
+	if err := row.Scan(&g.Id, &g.Name, &g.SourceLang, &g.TargetLang, &g.Team, &terms, &g.CreatedAt, &g.UpdatedAt, &g.CreatedBy); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(terms, &g.Terms); err != nil {
+		return nil, err
+	}
+
+	return &g, nil
+}
+
+// marshalTerms stores no terms as an empty array rather than null.
+func marshalTerms(terms []entity.Term) (string, error) {
+	if terms == nil {
+		terms = []entity.Term{}
+	}
+
+	b, err := json.Marshal(terms)
+	return string(b), err
+}
+
+// Ensure implementation
+var _ repository.GlossaryRepository = (*PostgresqlGlossaryRepository)(nil)
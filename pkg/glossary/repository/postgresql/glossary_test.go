@@ -0,0 +1,144 @@
+package postgresql
+
+import (
+	"doc-translate-go/pkg/glossary/entity"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var glossaryColumns = []string{"id", "name", "source_language", "target_language", "team", "terms", "created_at", "updated_at", "created_by"}
+
+func newGlossaryMock(t *testing.T) (sqlmock.Sqlmock, *PostgresqlGlossaryRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewPostgresqlGlossaryRepository(db)
+
+	return mock, repo
+}
+
+func TestPostgresqlGlossaryRepository_Create(t *testing.T) {
+	mock, repo := newGlossaryMock(t)
+	ent := &entity.Glossary{Id: 1, Name: "Products", SourceLang: "en", TargetLang: "vi", CreatedBy: "isid"}
+
+	rows := sqlmock.NewRows([]string{"id"}).AddRow(ent.Id)
+
+	cmd := `INSERT INTO glossaries \(name, source_language, target_language, team, terms, created_at, updated_at, created_by\)
+                VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8\)
+                RETURNING id;`
+
+	// No terms are stored as an empty array
+	mock.ExpectQuery(cmd).
+		WithArgs("Products", "en", "vi", "", "[]", sqlmock.AnyArg(), sqlmock.AnyArg(), "isid").
+		WillReturnRows(rows)
+
+	got, err := repo.Create(ent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != ent.Id {
+		t.Fatalf("expected %v, got %v", ent.Id, got)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlGlossaryRepository_GetById(t *testing.T) {
+	mock, repo := newGlossaryMock(t)
+
+	want := &entity.Glossary{
+		Id:         1,
+		Name:       "Products",
+		SourceLang: "en",
+		TargetLang: "vi",
+		Team:       "regulatory",
+		Terms:      []entity.Term{{Source: "Acme Cloud", Target: "Acme Cloud", Required: true}},
+		CreatedBy:  "isid",
+	}
+
+	rows := sqlmock.NewRows(glossaryColumns).
+		AddRow(want.Id, want.Name, want.SourceLang, want.TargetLang, want.Team, `[{"source":"Acme Cloud","target":"Acme Cloud","required":true}]`, want.CreatedAt, want.UpdatedAt, want.CreatedBy)
+
+	mock.ExpectQuery(`SELECT (.+) FROM glossaries WHERE id = \$1;`).WithArgs(1).WillReturnRows(rows)
+
+	got, err := repo.GetById(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlGlossaryRepository_GetById_NotFound(t *testing.T) {
+	mock, repo := newGlossaryMock(t)
+
+	mock.ExpectQuery(`SELECT (.+) FROM glossaries WHERE id = \$1;`).WithArgs(1).WillReturnRows(sqlmock.NewRows(glossaryColumns))
+
+	got, err := repo.GetById(1)
+	if err != nil || got != nil {
+		t.Fatalf("expected nil, nil, got %v, %v", got, err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlGlossaryRepository_ListByOwner(t *testing.T) {
+	mock, repo := newGlossaryMock(t)
+
+	now := time.Now()
+	rows := sqlmock.NewRows(glossaryColumns).
+		AddRow(1, "Mine", "en", "vi", "", `[]`, now, now, "isid").
+		AddRow(2, "Shared", "en", "ja", "regulatory", `[]`, now, now, "other")
+
+	mock.ExpectQuery(`SELECT (.+) FROM glossaries WHERE created_by = \$1 OR \(\$2 <> '' AND team = \$2\)`).
+		WithArgs("isid", "regulatory").
+		WillReturnRows(rows)
+
+	got, err := repo.ListByOwner("isid", "regulatory")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 2 || got[1].Team != "regulatory" || got[0].Terms == nil {
+		t.Fatalf("unexpected glossaries %v", got)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlGlossaryRepository_Update(t *testing.T) {
+	mock, repo := newGlossaryMock(t)
+	ent := &entity.Glossary{Id: 1, Name: "Products", SourceLang: "en", TargetLang: "vi", Terms: []entity.Term{{Source: "a", Target: "b"}}}
+
+	cmd := `UPDATE glossaries SET name = \$1, source_language = \$2, target_language = \$3, team = \$4, terms = \$5, updated_at = \$6 WHERE id = \$7;`
+
+	mock.ExpectExec(cmd).
+		WithArgs("Products", "en", "vi", "", `[{"source":"a","target":"b","required":false}]`, sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.Update(ent); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"doc-translate-go/pkg/glossary/entity"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// unspaced are scripts written without spaces between words, whose terms
+// may sit right against the text around them.
+var unspaced = []*unicode.RangeTable{
+	unicode.Han,
+	unicode.Hiragana,
+	unicode.Katakana,
+	unicode.Thai,
+	unicode.Lao,
+	unicode.Khmer,
+	unicode.Myanmar,
+}
+
+// Check flags the paragraphs whose source uses a required term their
+// translation doesn't. Paragraphs are paired by position when both documents
+// have as many, otherwise the term anywhere in the translation will do.
+func Check(terms []entity.Term, source []string, translated []string) []entity.Flag {
+	paired := len(source) == len(translated)
+	whole := strings.Join(translated, "\n")
+
+	flags := []entity.Flag{}
+	for i, text := range source {
+		for _, t := range terms {
+			if !t.Required || !containsTerm(text, t.Source) {
+				continue
+			}
+
+			in := whole
+			if paired {
+				in = translated[i]
+			}
+			if containsTerm(in, t.Target) {
+				continue
+			}
+
+			flags = append(flags, entity.Flag{Paragraph: i, Text: text, Source: t.Source, Target: t.Target})
+		}
+	}
+
+	return flags
+}
+
+// containsTerm tells whether text holds term, ignoring case, as whole words
+// unless the term starts or ends in a script written without spaces.
+func containsTerm(text string, term string) bool {
+	text, term = strings.ToLower(text), strings.ToLower(term)
+	if term == "" {
+		return false
+	}
+
+	first, _ := utf8.DecodeRuneInString(term)
+	last, _ := utf8.DecodeLastRuneInString(term)
+
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], term)
+		if i < 0 {
+			return false
+		}
+		start := offset + i
+		end := start + len(term)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (start == 0 || !isWordRune(before) || isUnspaced(first)) && (end == len(text) || !isWordRune(after) || isUnspaced(last)) {
+			return true
+		}
+
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isUnspaced(r rune) bool {
+	return unicode.IsOneOf(unspaced, r)
+}
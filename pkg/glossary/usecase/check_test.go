@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"doc-translate-go/pkg/glossary/entity"
+	"reflect"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	terms := []entity.Term{
+		{Source: "Acme Cloud", Target: "Acme Cloud", Required: true},
+		{Source: "dosage", Target: "liều dùng", Required: true},
+		{Source: "tablet", Target: "viên", Required: false},
+	}
+
+	source := []string{
+		"Sign in to ACME cloud.",
+		"Check the dosage before use.",
+		"Take one tablet.",
+		"Dosages vary.",
+	}
+	translated := []string{
+		"Đăng nhập vào Acme Cloud.",
+		"Kiểm tra liều lượng trước khi dùng.",
+		"Uống một viên thuốc.",
+		"Liều dùng khác nhau.",
+	}
+
+	// Optional terms and terms inside other words are ignored
+	want := []entity.Flag{{Paragraph: 1, Text: "Check the dosage before use.", Source: "dosage", Target: "liều dùng"}}
+	if got := Check(terms, source, translated); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	// Unpaired paragraphs look for the term anywhere in the translation
+	if got := Check(terms, source, append(translated, "Hết.")); len(got) != 0 {
+		t.Fatalf("expected no flag, got %v", got)
+	}
+}
+
+func TestContainsTerm(t *testing.T) {
+	tests := []struct {
+		text string
+		term string
+		want bool
+	}{
+		{"Use Acme Cloud.", "acme cloud", true},
+		{"Acme Clouds", "Acme Cloud", false},
+		{"preAcme Cloud", "Acme Cloud", false},
+		{"Acme Cloud Acme Cloudy", "Acme Cloudy", true},
+		{"アクメクラウドにログイン", "クラウド", true},
+		{"", "term", false},
+	}
+
+	for _, tt := range tests {
+		if got := containsTerm(tt.text, tt.term); got != tt.want {
+			t.Fatalf("%q in %q: expected %v, got %v", tt.term, tt.text, tt.want, got)
+		}
+	}
+}
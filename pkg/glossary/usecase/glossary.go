@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"doc-translate-go/pkg/glossary/entity"
+	"doc-translate-go/pkg/glossary/repository"
+	userRepository "doc-translate-go/pkg/user/repository"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound         = errors.New("glossary not found")
+	ErrInvalid          = errors.New("invalid glossary")
+	ErrNoTeam           = errors.New("user has no team to share the glossary with")
+	ErrLanguageMismatch = errors.New("glossary is for other languages")
+	ErrImportFormat     = errors.New("glossaries are imported from .csv or .tbx files")
+)
+
+type GlossaryUseCase struct {
+	repo     repository.GlossaryRepository
+	userRepo userRepository.UserRepository
+}
+
+func NewGlossaryUseCase(repo repository.GlossaryRepository, userRepo userRepository.UserRepository) *GlossaryUseCase {
+	return &GlossaryUseCase{repo, userRepo}
+}
+
+// List returns the glossaries isid created or shares with their team.
+func (uc *GlossaryUseCase) List(isid string) ([]*entity.Glossary, error) {
+	team, err := uc.team(isid)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.repo.ListByOwner(isid, team)
+}
+
+// Get returns a glossary isid may use. Other users' glossaries are reported
+// as not found, so their ids tell nothing.
+func (uc *GlossaryUseCase) Get(id int, isid string) (*entity.Glossary, error) {
+	g, err := uc.repo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if g == nil {
+		return nil, ErrNotFound
+	}
+
+	if g.CreatedBy == isid {
+		return g, nil
+	}
+
+	team, err := uc.team(isid)
+	if err != nil {
+		return nil, err
+	}
+	if g.Team == "" || g.Team != team {
+		return nil, ErrNotFound
+	}
+
+	return g, nil
+}
+
+// GetFor returns a glossary isid may use to translate from sourceLang into
+// targetLang.
+func (uc *GlossaryUseCase) GetFor(id int, isid string, sourceLang string, targetLang string) (*entity.Glossary, error) {
+	g, err := uc.Get(id, isid)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(g.SourceLang, sourceLang) || !strings.EqualFold(g.TargetLang, targetLang) {
+		return nil, fmt.Errorf("%w: %s is for %s to %s", ErrLanguageMismatch, g.Name, g.SourceLang, g.TargetLang)
+	}
+
+	return g, nil
+}
+
+// Create stores a new glossary of isid's, shared with their team if shared
+// is set.
+func (uc *GlossaryUseCase) Create(g *entity.Glossary, isid string, shared bool) (int, error) {
+	g.Name = strings.TrimSpace(g.Name)
+	if g.Name == "" || g.SourceLang == "" || g.TargetLang == "" {
+		return 0, fmt.Errorf("%w: name, source and target languages are required", ErrInvalid)
+	}
+
+	terms, err := normalizeTerms(g.Terms)
+	if err != nil {
+		return 0, err
+	}
+	g.Terms = terms
+
+	g.Team = ""
+	if shared {
+		team, err := uc.team(isid)
+		if err != nil {
+			return 0, err
+		}
+		if team == "" {
+			return 0, ErrNoTeam
+		}
+		g.Team = team
+	}
+
+	now := time.Now()
+	g.CreatedAt = now
+	g.UpdatedAt = now
+	g.CreatedBy = isid
+
+	return uc.repo.Create(g)
+}
+
+// Update replaces the name and terms of a glossary isid may use. Its
+// languages and owner stay as they are.
+func (uc *GlossaryUseCase) Update(id int, isid string, name string, terms []entity.Term) (*entity.Glossary, error) {
+	g, err := uc.Get(id, isid)
+	if err != nil {
+		return nil, err
+	}
+
+	if name = strings.TrimSpace(name); name != "" {
+		g.Name = name
+	}
+
+	if g.Terms, err = normalizeTerms(terms); err != nil {
+		return nil, err
+	}
+
+	return g, uc.save(g)
+}
+
+// Delete removes a glossary isid may use.
+func (uc *GlossaryUseCase) Delete(id int, isid string) error {
+	if _, err := uc.Get(id, isid); err != nil {
+		return err
+	}
+
+	return uc.repo.DeleteById(id)
+}
+
+// Import adds the terms of a CSV or TBX file to a glossary isid may use.
+// Imported terms replace those with the same source.
+func (uc *GlossaryUseCase) Import(id int, isid string, b []byte, filename string) (*entity.Glossary, error) {
+	g, err := uc.Get(id, isid)
+	if err != nil {
+		return nil, err
+	}
+
+	var imported []entity.Term
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		imported, err = parseCSV(b, g.SourceLang)
+	case ".tbx":
+		imported, err = parseTBX(b, g.SourceLang, g.TargetLang)
+	default:
+		return nil, ErrImportFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if g.Terms, err = normalizeTerms(append(g.Terms, imported...)); err != nil {
+		return nil, err
+	}
+
+	return g, uc.save(g)
+}
+
+func (uc *GlossaryUseCase) save(g *entity.Glossary) error {
+	g.UpdatedAt = time.Now()
+	return uc.repo.Update(g)
+}
+
+// team returns the team of isid, empty when they have none.
+func (uc *GlossaryUseCase) team(isid string) (string, error) {
+	u, err := uc.userRepo.GetByIsid(isid)
+	if err != nil {
+		return "", err
+	}
+
+	return u.Team, nil
+}
+
+// normalizeTerms trims terms and keeps the last of those with the same
+// source, ignoring case, in the place of the first.
+func normalizeTerms(terms []entity.Term) ([]entity.Term, error) {
+	result := []entity.Term{}
+	index := make(map[string]int)
+
+	for _, t := range terms {
+		t.Source = strings.TrimSpace(t.Source)
+		t.Target = strings.TrimSpace(t.Target)
+		if t.Source == "" || t.Target == "" {
+			return nil, fmt.Errorf("%w: terms need a source and a target", ErrInvalid)
+		}
+
+		key := strings.ToLower(t.Source)
+		if i, ok := index[key]; ok {
+			result[i] = t
+			continue
+		}
+
+		index[key] = len(result)
+		result = append(result, t)
+	}
+
+	return result, nil
+}
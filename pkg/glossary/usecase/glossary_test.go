@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"doc-translate-go/pkg/glossary/entity"
+	"doc-translate-go/pkg/glossary/repository/postgresql"
+	userPG "doc-translate-go/pkg/user/repository/postgresql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func newTestGlossaryUseCase(t *testing.T) (*GlossaryUseCase, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewGlossaryUseCase(postgresql.NewPostgresqlGlossaryRepository(db), userPG.NewPostgresqlUserRepository(db)), mock
+}
+
+func expectGlossary(mock sqlmock.Sqlmock, team string, createdBy string, terms string) {
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM glossaries WHERE id = \$1;`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "source_language", "target_language", "team", "terms", "created_at", "updated_at", "created_by"}).
+			AddRow(1, "Products", "en", "vi", team, terms, now, now, createdBy))
+}
+
+func expectUser(mock sqlmock.Sqlmock, isid string, team string) {
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM users WHERE isid = \$1;`).
+		WithArgs(isid).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "isid", "role", "email", "team", "created_at", "updated_at"}).
+			AddRow(1, isid, "user", "", team, now, now))
+}
+
+func TestGlossaryUseCase_Get(t *testing.T) {
+	tests := []struct {
+		name     string
+		team     string
+		userTeam string
+		wantErr  error
+	}{
+		{"team member", "regulatory", "regulatory", nil},
+		{"other team", "regulatory", "marketing", ErrNotFound},
+		{"personal", "", "", ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		uc, mock := newTestGlossaryUseCase(t)
+
+		expectGlossary(mock, tt.team, "owner", "[]")
+		expectUser(mock, "isid", tt.userTeam)
+
+		if _, err := uc.Get(1, "isid"); err != tt.wantErr {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.wantErr, err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Owners don't need a team
+	uc, mock := newTestGlossaryUseCase(t)
+	expectGlossary(mock, "", "isid", "[]")
+	if _, err := uc.Get(1, "isid"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGlossaryUseCase_GetFor(t *testing.T) {
+	uc, mock := newTestGlossaryUseCase(t)
+
+	expectGlossary(mock, "", "isid", "[]")
+	if _, err := uc.GetFor(1, "isid", "EN", "vi"); err != nil {
+		t.Fatal(err)
+	}
+
+	expectGlossary(mock, "", "isid", "[]")
+	if _, err := uc.GetFor(1, "isid", "en", "ja"); !errors.Is(err, ErrLanguageMismatch) {
+		t.Fatalf("expected %v, got %v", ErrLanguageMismatch, err)
+	}
+}
+
+func TestGlossaryUseCase_Create(t *testing.T) {
+	uc, mock := newTestGlossaryUseCase(t)
+
+	expectUser(mock, "isid", "")
+	if _, err := uc.Create(&entity.Glossary{Name: "Products", SourceLang: "en", TargetLang: "vi"}, "isid", true); err != ErrNoTeam {
+		t.Fatalf("expected %v, got %v", ErrNoTeam, err)
+	}
+
+	if _, err := uc.Create(&entity.Glossary{Name: " ", SourceLang: "en", TargetLang: "vi"}, "isid", false); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected %v, got %v", ErrInvalid, err)
+	}
+
+	expectUser(mock, "isid", "regulatory")
+	mock.ExpectQuery("INSERT INTO glossaries").
+		WithArgs("Products", "en", "vi", "regulatory", `[{"source":"acme","target":"Acme","required":true}]`, sqlmock.AnyArg(), sqlmock.AnyArg(), "isid").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	g := &entity.Glossary{
+		Name:       "Products",
+		SourceLang: "en",
+		TargetLang: "vi",
+		Terms:      []entity.Term{{Source: " Acme ", Target: "ACME", Required: true}, {Source: "acme", Target: "Acme", Required: true}},
+	}
+	if id, err := uc.Create(g, "isid", true); err != nil || id != 1 {
+		t.Fatalf("expected 1, got %v, %v", id, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGlossaryUseCase_Import(t *testing.T) {
+	uc, mock := newTestGlossaryUseCase(t)
+
+	expectGlossary(mock, "", "isid", `[{"source":"Acme","target":"Acme","required":true},{"source":"tablet","target":"viên","required":true}]`)
+	mock.ExpectExec("UPDATE glossaries").
+		WithArgs("Products", "en", "vi", "", `[{"source":"Acme","target":"Acme","required":true},{"source":"Tablet","target":"viên nén","required":false},{"source":"dosage","target":"liều dùng","required":true}]`, sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	g, err := uc.Import(1, "isid", []byte("Tablet,viên nén,false\ndosage,liều dùng\n"), "terms.CSV")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []entity.Term{
+		{Source: "Acme", Target: "Acme", Required: true},
+		{Source: "Tablet", Target: "viên nén", Required: false},
+		{Source: "dosage", Target: "liều dùng", Required: true},
+	}
+	if !reflect.DeepEqual(want, g.Terms) {
+		t.Fatalf("expected %v, got %v", want, g.Terms)
+	}
+
+	expectGlossary(mock, "", "isid", "[]")
+	if _, err := uc.Import(1, "isid", []byte("a,b"), "terms.xlsx"); err != ErrImportFormat {
+		t.Fatalf("expected %v, got %v", ErrImportFormat, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
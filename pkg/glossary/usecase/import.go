@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"bytes"
+	"doc-translate-go/pkg/glossary/entity"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// parseCSV reads terms from rows of source, target and, optionally, whether
+// the term is required, which it is when left out. A first row naming its
+// columns, source or the source language first, is skipped.
+func parseCSV(b []byte, sourceLang string) ([]entity.Term, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(b, []byte("\ufeff"))))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	var terms []entity.Term
+	for line := 1; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+
+		if line == 1 {
+			first := strings.ToLower(strings.TrimSpace(record[0]))
+			if first == "source" || strings.EqualFold(first, sourceLang) {
+				continue
+			}
+		}
+
+		if len(record) < 2 {
+			return nil, fmt.Errorf("%w: line %d has no target", ErrInvalid, line)
+		}
+
+		required := true
+		if len(record) > 2 && strings.TrimSpace(record[2]) != "" {
+			required, err = parseRequired(record[2])
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalid, line, err)
+			}
+		}
+
+		terms = append(terms, entity.Term{Source: record[0], Target: record[1], Required: required})
+	}
+
+	return terms, nil
+}
+
+func parseRequired(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "yes", "y":
+		return true, nil
+	case "no", "n":
+		return false, nil
+	}
+
+	return strconv.ParseBool(strings.TrimSpace(s))
+}
+
+// tbxTerm is a term of one language of a TBX entry.
+type tbxTerm struct {
+	text       string
+	deprecated bool
+}
+
+// parseTBX reads terms from the entries of a TBX file that have a term in
+// both languages, TBX-Basic's termEntry, langSet and tig as well as TBX v3's
+// conceptEntry, langSec and termSec. Deprecated terms are skipped and the
+// first remaining term of each language is taken. Every term is required.
+func parseTBX(b []byte, sourceLang string, targetLang string) ([]entity.Term, error) {
+	d := xml.NewDecoder(bytes.NewReader(b))
+
+	var terms []entity.Term
+	// Terms of the entry being read by language, and the language and term being read
+	entry := make(map[string][]tbxTerm)
+	lang := ""
+	var term *tbxTerm
+	var text strings.Builder
+	inTerm, inStatus := false, false
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "langSet", "langSec":
+				lang = xmlLang(t)
+			case "tig", "ntig", "termSec":
+				term = &tbxTerm{}
+			case "term":
+				inTerm = true
+				text.Reset()
+			case "termNote":
+				inStatus = attr(t, "type") == "administrativeStatus"
+				text.Reset()
+			}
+		case xml.CharData:
+			if inTerm || inStatus {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "term":
+				inTerm = false
+				if term != nil {
+					term.text = strings.TrimSpace(text.String())
+				}
+			case "termNote":
+				if inStatus && term != nil {
+					term.deprecated = strings.HasPrefix(strings.TrimSpace(text.String()), "deprecated")
+				}
+				inStatus = false
+			case "tig", "ntig", "termSec":
+				if term != nil && term.text != "" && !term.deprecated {
+					entry[lang] = append(entry[lang], *term)
+				}
+				term = nil
+			case "termEntry", "conceptEntry":
+				source, target := tbxFirst(entry, sourceLang), tbxFirst(entry, targetLang)
+				if source != "" && target != "" {
+					terms = append(terms, entity.Term{Source: source, Target: target, Required: true})
+				}
+				clear(entry)
+			}
+		}
+	}
+
+	return terms, nil
+}
+
+// tbxFirst returns the first term of an entry in lang or, failing that, in
+// one of its regional variants.
+func tbxFirst(entry map[string][]tbxTerm, lang string) string {
+	var variants []string
+	for l, terms := range entry {
+		if strings.EqualFold(l, lang) && len(terms) > 0 {
+			return terms[0].text
+		}
+		if langMatches(l, lang) && len(terms) > 0 {
+			variants = append(variants, l)
+		}
+	}
+
+	if len(variants) == 0 {
+		return ""
+	}
+	slices.Sort(variants)
+	return entry[variants[0]][0].text
+}
+
+// langMatches tells whether tag, such as en-US, is lang or a variant of it.
+func langMatches(tag string, lang string) bool {
+	tag, lang = strings.ToLower(tag), strings.ToLower(lang)
+	return tag == lang || strings.HasPrefix(tag, lang+"-") || strings.HasPrefix(tag, lang+"_")
+}
+
+func xmlLang(e xml.StartElement) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == "lang" && (a.Name.Space == "xml" || a.Name.Space == "http://www.w3.org/XML/1998/namespace") {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"doc-translate-go/pkg/glossary/entity"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	b := "\ufeffen,vi,required\nAcme Cloud,Acme Cloud\n\"dosage, daily\",liều dùng hằng ngày,no\ntablet, viên ,yes\n"
+
+	want := []entity.Term{
+		{Source: "Acme Cloud", Target: "Acme Cloud", Required: true},
+		{Source: "dosage, daily", Target: "liều dùng hằng ngày", Required: false},
+		{Source: "tablet", Target: "viên ", Required: true},
+	}
+
+	got, err := parseCSV([]byte(b), "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	for _, b := range []string{"source,target\nonly a source\n", "a,b,maybe\n"} {
+		if _, err := parseCSV([]byte(b), "en"); !errors.Is(err, ErrInvalid) {
+			t.Fatalf("%q: expected %v, got %v", b, ErrInvalid, err)
+		}
+	}
+}
+
+const sampleTBX = `<?xml version="1.0" encoding="UTF-8"?>
+<martif type="TBX-Basic" xml:lang="en-US">
+  <text><body>
+    <termEntry id="1">
+      <langSet xml:lang="en-US"><tig><term>Acme Cloud</term></tig></langSet>
+      <langSet xml:lang="vi"><tig><term>Acme Cloud</term></tig></langSet>
+    </termEntry>
+    <termEntry id="2">
+      <langSet xml:lang="en"><tig><term>dosage</term></tig></langSet>
+      <langSet xml:lang="vi">
+        <tig><term>liều</term><termNote type="administrativeStatus">deprecatedTerm-admn-sts</termNote></tig>
+        <tig><term>liều dùng</term><termNote type="administrativeStatus">preferredTerm-admn-sts</termNote></tig>
+      </langSet>
+    </termEntry>
+    <termEntry id="3">
+      <langSet xml:lang="en"><tig><term>tablet</term></tig></langSet>
+      <langSet xml:lang="ja"><tig><term>錠剤</term></tig></langSet>
+    </termEntry>
+  </body></text>
+</martif>`
+
+const sampleTBX3 = `<?xml version="1.0" encoding="UTF-8"?>
+<tbx type="TBX-Core" style="dca" xml:lang="en" xmlns="urn:iso:std:iso:30042:ed-2">
+  <text><body>
+    <conceptEntry id="1">
+      <langSec xml:lang="en"><termSec><term>dosage</term></termSec></langSec>
+      <langSec xml:lang="vi"><termSec><term>liều dùng</term></termSec></langSec>
+    </conceptEntry>
+  </body></text>
+</tbx>`
+
+func TestParseTBX(t *testing.T) {
+	want := []entity.Term{
+		{Source: "Acme Cloud", Target: "Acme Cloud", Required: true},
+		{Source: "dosage", Target: "liều dùng", Required: true},
+	}
+
+	got, err := parseTBX([]byte(sampleTBX), "en", "vi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	got, err = parseTBX([]byte(sampleTBX3), "en", "vi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want[1:], got) {
+		t.Fatalf("expected %v, got %v", want[1:], got)
+	}
+
+	if _, err := parseTBX([]byte("<martif><termEntry>"), "en", "vi"); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected %v, got %v", ErrInvalid, err)
+	}
+}
@@ -0,0 +1,67 @@
+package mask
+
+import (
+	"doc-translate-go/pkg/docx"
+	"doc-translate-go/pkg/format"
+	"strings"
+)
+
+// maskDocx masks the protected spans of every run and whole runs in the
+// DoNotTranslate style, or in a paragraph of that style. Spans running
+// across differently formatted runs aren't found.
+func (r *Rules) maskDocx(b []byte) ([]byte, *Set, error) {
+	d, err := docx.Open(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	segments := d.Segments()
+
+	var texts []string
+	for _, seg := range segments {
+		texts = append(texts, seg.Text())
+	}
+	s := newSet(format.Docx, nextTag(texts))
+
+	for _, seg := range segments {
+		for i, run := range seg.Runs {
+			if isDoNotTranslate(seg.Style) || isDoNotTranslate(run.Style()) {
+				seg.Runs[i].Text = s.mask(run.Text)
+				continue
+			}
+			seg.Runs[i].Text = r.maskText(run.Text, s)
+		}
+	}
+
+	// Nothing to protect, the document goes as it came
+	if s.Len() == 0 {
+		return b, s, nil
+	}
+
+	masked, err := d.Assemble(segments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return masked, s, nil
+}
+
+func (s *Set) restoreDocx(translated []byte) ([]byte, error) {
+	d, err := docx.Open(translated)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := d.Segments()
+	for _, seg := range segments {
+		for i, run := range seg.Runs {
+			seg.Runs[i].Text = s.restoreText(run.Text)
+		}
+	}
+
+	return d.Assemble(segments)
+}
+
+func isDoNotTranslate(style string) bool {
+	return strings.EqualFold(style, DoNotTranslateStyle)
+}
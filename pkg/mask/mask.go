@@ -0,0 +1,217 @@
+package mask
+
+import (
+	"doc-translate-go/pkg/format"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrLost = errors.New("protected text was lost in translation")
+
+// DefaultProductCodes matches product codes such as AB-1234, X100 or
+// SKU-20-B: up to five capitals followed by two digits or more.
+const DefaultProductCodes = `\b[A-Z]{1,5}-?[0-9]{2,}(?:-[A-Z0-9]+)*\b`
+
+// DoNotTranslateStyle is the id of the DOCX paragraph or character style of
+// text never translated.
+const DoNotTranslateStyle = "DoNotTranslate"
+
+// builtins are always protected: {{placeholders}}, URLs and email addresses.
+// URLs leave out punctuation ending the sentence around them.
+var builtins = []*regexp.Regexp{
+	regexp.MustCompile(`\{\{[^{}]*\}\}`),
+	regexp.MustCompile(`\b(?:(?:https?|ftp)://|www\.)[^\s<>"]*[^\s<>"'.,;:!?)\]}]`),
+	regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}\b`),
+}
+
+// anyTag matches the numbered tags standing for markup or masks, <gN>, </gN>
+// and <xN/>, the latter as loosely as translators return it.
+var anyTag = regexp.MustCompile(`(?i)</?g(\d+)>|<x\s*(\d+)\s*/>`)
+
+var maskTag = regexp.MustCompile(`(?i)<x\s*(\d+)\s*/>`)
+
+// Rules tell the text that must come back from translation as it is.
+type Rules struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRules protects placeholders, URLs and email addresses along with the
+// text matching any of patterns. Empty patterns are skipped.
+func NewRules(patterns ...string) (*Rules, error) {
+	r := &Rules{patterns: builtins}
+
+	for _, p := range patterns {
+		if p == "" {
+			continue
+		}
+
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		r.patterns = append(r.patterns, re)
+	}
+
+	return r, nil
+}
+
+// spans returns where text is protected, in order and merged where spans
+// of different patterns overlap.
+func (r *Rules) spans(text string) [][2]int {
+	var spans [][2]int
+	for _, re := range r.patterns {
+		for _, m := range re.FindAllStringIndex(text, -1) {
+			if m[1] > m[0] {
+				spans = append(spans, [2]int{m[0], m[1]})
+			}
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+
+	var merged [][2]int
+	for _, s := range spans {
+		if n := len(merged); n > 0 && s[0] < merged[n-1][1] {
+			merged[n-1][1] = max(merged[n-1][1], s[1])
+			continue
+		}
+		merged = append(merged, s)
+	}
+
+	return merged
+}
+
+// maskText replaces the protected spans of text with masks of s.
+func (r *Rules) maskText(text string, s *Set) string {
+	spans := r.spans(text)
+	if len(spans) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	prev := 0
+	for _, span := range spans {
+		b.WriteString(text[prev:span[0]])
+		b.WriteString(s.mask(text[span[0]:span[1]]))
+		prev = span[1]
+	}
+	b.WriteString(text[prev:])
+
+	return b.String()
+}
+
+// Mask masks the protected text of a document of the given MIME type: text
+// segments, DOCX, where text in the DoNotTranslate style is masked too, and
+// the text elements of presentations and spreadsheets. Other documents are
+// returned as they are. The set returned restores the translation.
+func (r *Rules) Mask(b []byte, mimeType string) ([]byte, *Set, error) {
+	switch {
+	case mimeType == format.Text:
+		s := newSet(mimeType, nextTag([]string{string(b)}))
+		return []byte(r.maskText(string(b), s)), s, nil
+	case mimeType == format.Docx:
+		return r.maskDocx(b)
+	case mimeType == format.Pptx || mimeType == format.Xlsx:
+		return r.maskParts(b, mimeType)
+	}
+
+	return b, newSet(mimeType, 1), nil
+}
+
+// nextTag returns the number following every tag in texts, so masks never
+// take the number of markup or of text that merely looks like a mask.
+func nextTag(texts []string) int {
+	n := 0
+	for _, text := range texts {
+		for _, m := range anyTag.FindAllStringSubmatch(text, -1) {
+			i, _ := strconv.Atoi(m[1] + m[2])
+			n = max(n, i)
+		}
+	}
+	return n + 1
+}
+
+// Set is the text masked out of one document. Masks are numbered <xN/> tags,
+// which translators keep as they keep the tags standing for markup.
+type Set struct {
+	mimeType  string
+	first     int
+	originals []string
+	restored  []bool
+}
+
+func newSet(mimeType string, first int) *Set {
+	return &Set{mimeType: mimeType, first: first}
+}
+
+// Len returns how many spans of text are masked.
+func (s *Set) Len() int {
+	return len(s.originals)
+}
+
+func (s *Set) mask(original string) string {
+	s.originals = append(s.originals, original)
+	s.restored = append(s.restored, false)
+	return fmt.Sprintf("<x%d/>", s.first+len(s.originals)-1)
+}
+
+// restoreText puts the masked text back in text. Tags that aren't masks of
+// the set are left alone.
+func (s *Set) restoreText(text string) string {
+	return maskTag.ReplaceAllStringFunc(text, func(tag string) string {
+		n, _ := strconv.Atoi(maskTag.FindStringSubmatch(tag)[1])
+		i := n - s.first
+		if i < 0 || i >= len(s.originals) {
+			return tag
+		}
+
+		s.restored[i] = true
+		return s.originals[i]
+	})
+}
+
+// lost returns ErrLost naming the masked text the translation dropped, if any.
+func (s *Set) lost() error {
+	var lost []string
+	for i, original := range s.originals {
+		if !s.restored[i] {
+			lost = append(lost, strconv.Quote(original))
+		}
+	}
+
+	if len(lost) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrLost, strings.Join(lost, ", "))
+}
+
+// Restore puts the masked text back into the translation of the document the
+// set was made from. It fails with ErrLost if any mask didn't come back.
+func (s *Set) Restore(translated []byte) ([]byte, error) {
+	if s.Len() == 0 {
+		return translated, nil
+	}
+
+	var b []byte
+	var err error
+	switch s.mimeType {
+	case format.Docx:
+		b, err = s.restoreDocx(translated)
+	case format.Pptx, format.Xlsx:
+		b, err = s.restoreParts(translated)
+	default:
+		b = []byte(s.restoreText(string(translated)))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.lost(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,218 @@
+package mask
+
+import (
+	"archive/zip"
+	"bytes"
+	"doc-translate-go/pkg/docx"
+	"doc-translate-go/pkg/format"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestRules(t *testing.T) *Rules {
+	r, err := NewRules(DefaultProductCodes, `(?i)\bacme cloud\b`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRules_Mask_Text(t *testing.T) {
+	r := newTestRules(t)
+
+	text := "Hi {{name}}, <g1>Acme Cloud</g1> AB-1234 is at https://acme.example/docs?id=1. Mail help@acme.example or visit www.acme.example!"
+	masked, s, err := r.Mask([]byte(text), format.Text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Masks are numbered past the markup
+	want := "Hi <x2/>, <g1><x3/></g1> <x4/> is at <x5/>. Mail <x6/> or visit <x7/>!"
+	if string(masked) != want {
+		t.Fatalf("expected %q, got %q", want, masked)
+	}
+
+	// Translators may move masks and space them out
+	restored, err := s.Restore([]byte("<x7 /> <x6/> <x5/> <g1><x3/></g1> <X4/>: xin chào <x2/>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantRestored := "www.acme.example help@acme.example https://acme.example/docs?id=1 <g1>Acme Cloud</g1> AB-1234: xin chào {{name}}"
+	if string(restored) != wantRestored {
+		t.Fatalf("expected %q, got %q", wantRestored, restored)
+	}
+}
+
+func TestRules_Mask_Lost(t *testing.T) {
+	r := newTestRules(t)
+
+	_, s, err := r.Mask([]byte("Order AB-1234 for {{name}}"), format.Text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.Restore([]byte("Đặt hàng <x1/> cho"))
+	if !errors.Is(err, ErrLost) {
+		t.Fatalf("expected %v, got %v", ErrLost, err)
+	}
+	if !strings.Contains(err.Error(), `"{{name}}"`) || strings.Contains(err.Error(), "AB-1234") {
+		t.Fatalf("expected only {{name}} to be lost, got %v", err)
+	}
+}
+
+func TestRules_Mask_Unsupported(t *testing.T) {
+	r := newTestRules(t)
+
+	b := []byte("%PDF-1.7 https://acme.example")
+	masked, s, err := r.Mask(b, "application/pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, masked) || s.Len() != 0 {
+		t.Fatalf("expected the document as it is, got %q", masked)
+	}
+}
+
+func TestNewRules_Invalid(t *testing.T) {
+	if _, err := NewRules("("); err == nil {
+		t.Fatal("expected an invalid pattern to fail")
+	}
+}
+
+// newPackage zips files into a package.
+func newPackage(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func readPart(t *testing.T, b []byte, name string) string {
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := r.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func docxTexts(t *testing.T, b []byte) []string {
+	d, err := docx.Open(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result []string
+	for _, s := range d.Segments() {
+		for _, r := range s.Runs {
+			result = append(result, r.Text)
+		}
+	}
+	return result
+}
+
+func TestRules_Mask_Docx(t *testing.T) {
+	r := newTestRules(t)
+
+	b := newPackage(t, map[string]string{
+		"word/document.xml": `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+			`<w:p><w:pPr><w:pStyle w:val="DoNotTranslate"/></w:pPr><w:r><w:t>Keep me</w:t></w:r></w:p>` +
+			`<w:p><w:r><w:t xml:space="preserve">See {{link}} and </w:t></w:r><w:r><w:rPr><w:rStyle w:val="donottranslate"/></w:rPr><w:t>this run</w:t></w:r></w:p>` +
+			`</w:body></w:document>`,
+	})
+
+	masked, s, err := r.Mask(b, format.Docx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"<x1/>", "See <x2/> and ", "<x3/>"}
+	if got := docxTexts(t, masked); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	// Translate the masked document
+	document := strings.NewReplacer("See ", "Xem ", " and ", " và ").Replace(readPart(t, masked, "word/document.xml"))
+	translated := newPackage(t, map[string]string{"word/document.xml": document})
+
+	restored, err := s.Restore(translated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want = []string{"Keep me", "Xem {{link}} và ", "this run"}
+	if got := docxTexts(t, restored); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestRules_Mask_Docx_Nothing(t *testing.T) {
+	r := newTestRules(t)
+
+	b := newPackage(t, map[string]string{
+		"word/document.xml": `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>Hello</w:t></w:r></w:p></w:body></w:document>`,
+	})
+
+	masked, s, err := r.Mask(b, format.Docx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, masked) || s.Len() != 0 {
+		t.Fatal("expected the document as it is")
+	}
+}
+
+func TestRules_Mask_Pptx(t *testing.T) {
+	r := newTestRules(t)
+
+	slide := `<p:sld xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main">` +
+		`<a:p><a:r><a:t>Write to help@acme.example &amp; smile</a:t></a:r></a:p></p:sld>`
+	b := newPackage(t, map[string]string{
+		"ppt/slides/slide1.xml": slide,
+		"ppt/presentation.xml":  `<p:presentation xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"/>`,
+	})
+
+	masked, s, err := r.Mask(b, format.Pptx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readPart(t, masked, "ppt/slides/slide1.xml"); !strings.Contains(got, "<a:t>Write to &lt;x1/&gt; &amp; smile</a:t>") {
+		t.Fatalf("unexpected masked slide %s", got)
+	}
+
+	restored, err := s.Restore(masked)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readPart(t, restored, "ppt/slides/slide1.xml"); got != slide {
+		t.Fatalf("expected %s, got %s", slide, got)
+	}
+}
@@ -0,0 +1,31 @@
+package mask
+
+import "doc-translate-go/pkg/format"
+
+// maskParts masks the protected spans of every <t> element of a presentation
+// or a spreadsheet. Spans running across elements aren't found.
+func (r *Rules) maskParts(b []byte, mimeType string) ([]byte, *Set, error) {
+	texts, err := format.Paragraphs(b, mimeType)
+	if err != nil {
+		return nil, nil, err
+	}
+	s := newSet(mimeType, nextTag(texts))
+
+	masked, err := format.RewriteParts(b, mimeType, func(text string) string {
+		return r.maskText(text, s)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Nothing to protect, the document goes as it came
+	if s.Len() == 0 {
+		return b, s, nil
+	}
+
+	return masked, s, nil
+}
+
+func (s *Set) restoreParts(translated []byte) ([]byte, error) {
+	return format.RewriteParts(translated, s.mimeType, s.restoreText)
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// Unit is an approved translation of a segment from one language into
+// another. Segments may hold the numbered <gN>...</gN> and <xN/> tags standing
+// for their markup.
+type Unit struct {
+	Id         int       `json:"id"`
+	SourceLang string    `json:"source_lang"`
+	TargetLang string    `json:"target_lang"`
+	Source     string    `json:"source"`
+	Target     string    `json:"target"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	CreatedBy  string    `json:"created_by"`
+}
+
+// Match is a unit found for a segment, Score being how similar its source is
+// to the segment, from 0 to 1. Exact matches are used as the translation,
+// others are only passed on to the translator.
+type Match struct {
+	Source string  `json:"source"`
+	Target string  `json:"target"`
+	Score  float64 `json:"score"`
+	Exact  bool    `json:"exact"`
+}
@@ -0,0 +1,18 @@
+package repository
+
+import "doc-translate-go/pkg/memory/entity"
+
+// TranslationMemoryRepository operates against a database
+// or any data persistent layer.
+type TranslationMemoryRepository interface {
+	// Upsert stores units, replacing the target of those already stored with
+	// the same source and languages.
+	Upsert(units []*entity.Unit) error
+	// FindExact returns the targets of the units whose source is one of
+	// sources, by source.
+	FindExact(sourceLang string, targetLang string, sources []string) (map[string]string, error)
+	// FindFuzzy returns the unit whose source is the most similar to each of
+	// sources, by source. Sources no unit scores minScore for are left out.
+	FindFuzzy(sourceLang string, targetLang string, sources []string, minScore float64) (map[string]*entity.Match, error)
+	ListByLanguages(sourceLang string, targetLang string) ([]*entity.Unit, error)
+}
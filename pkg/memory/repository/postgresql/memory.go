@@ -0,0 +1,157 @@
+package postgresql
+
+import (
+	"doc-translate-go/pkg/db"
+	"doc-translate-go/pkg/memory/entity"
+	"doc-translate-go/pkg/memory/repository"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// upsertBatchSize is how many units are stored per statement, well below the
+// limit on parameters of a statement.
+const upsertBatchSize = 500
+
+type PostgresqlTranslationMemoryRepository struct {
+	querier db.Querier
+}
+
+func NewPostgresqlTranslationMemoryRepository(querier db.Querier) *PostgresqlTranslationMemoryRepository {
+	return &PostgresqlTranslationMemoryRepository{querier}
+}
+
+// Upsert expects the sources of units to differ, a statement can't update the
+// same row twice.
+func (r *PostgresqlTranslationMemoryRepository) Upsert(units []*entity.Unit) error {
+	for start := 0; start < len(units); start += upsertBatchSize {
+		batch := units[start:min(start+upsertBatchSize, len(units))]
+
+		arg_placeholders := make([]string, len(batch))
+		args := make([]any, 0, len(batch)*7)
+
+		for i, u := range batch {
+			n := i * 7
+			arg_placeholders[i] = fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7)
+			args = append(args, u.SourceLang, u.TargetLang, u.Source, u.Target, u.CreatedAt, u.UpdatedAt, u.CreatedBy)
+		}
+
+		cmd := fmt.Sprintf(`INSERT INTO translation_units (source_language, target_language, source_text, target_text, created_at, updated_at, created_by)
+                VALUES %s
+                ON CONFLICT (source_language, target_language, md5(source_text))
+                DO UPDATE SET target_text = EXCLUDED.target_text, updated_at = EXCLUDED.updated_at;`,
+			strings.Join(arg_placeholders, ", "),
+		)
+
+		if _, err := r.querier.Exec(cmd, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FindExact compares hashes of sources, so the unique index is used. Sources
+// are passed as one array, however many there are.
+func (r *PostgresqlTranslationMemoryRepository) FindExact(sourceLang string, targetLang string, sources []string) (map[string]string, error) {
+	out := make(map[string]string)
+	if len(sources) == 0 {
+		return out, nil
+	}
+
+	cmd := `SELECT source_text, target_text
+                FROM translation_units
+                WHERE source_language = $1 AND target_language = $2 AND md5(source_text) IN (SELECT md5(s) FROM unnest($3::text[]) AS s);`
+
+	rows, err := r.querier.Query(cmd, sourceLang, targetLang, pq.Array(sources))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var source, target string
+		if err := rows.Scan(&source, &target); err != nil {
+			return nil, err
+		}
+		out[source] = target
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// FindFuzzy scores units by trigram similarity. Only units past the trigram
+// index's threshold are considered, so minScore below it finds no more.
+func (r *PostgresqlTranslationMemoryRepository) FindFuzzy(sourceLang string, targetLang string, sources []string, minScore float64) (map[string]*entity.Match, error) {
+	out := make(map[string]*entity.Match)
+	if len(sources) == 0 {
+		return out, nil
+	}
+
+	cmd := `SELECT s.source, m.source_text, m.target_text, m.score
+                FROM unnest($3::text[]) AS s(source)
+                CROSS JOIN LATERAL (
+                    SELECT source_text, target_text, similarity(source_text, s.source) AS score
+                    FROM translation_units
+                    WHERE source_language = $1 AND target_language = $2 AND source_text % s.source AND similarity(source_text, s.source) >= $4
+                    ORDER BY score DESC, id
+                    LIMIT 1
+                ) AS m;`
+
+	rows, err := r.querier.Query(cmd, sourceLang, targetLang, pq.Array(sources), minScore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var source string
+		var m entity.Match
+		if err := rows.Scan(&source, &m.Source, &m.Target, &m.Score); err != nil {
+			return nil, err
+		}
+		out[source] = &m
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (r *PostgresqlTranslationMemoryRepository) ListByLanguages(sourceLang string, targetLang string) ([]*entity.Unit, error) {
+	cmd := `SELECT id, source_language, target_language, source_text, target_text, created_at, updated_at, created_by
+                FROM translation_units
+                WHERE source_language = $1 AND target_language = $2
+                ORDER BY id;`
+
+	rows, err := r.querier.Query(cmd, sourceLang, targetLang)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*entity.Unit
+	for rows.Next() {
+		var u entity.Unit
+		if err := rows.Scan(&u.Id, &u.SourceLang, &u.TargetLang, &u.Source, &u.Target, &u.CreatedAt, &u.UpdatedAt, &u.CreatedBy); err != nil {
+			return nil, err
+		}
+		out = append(out, &u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Ensure implementation
+var _ repository.TranslationMemoryRepository = (*PostgresqlTranslationMemoryRepository)(nil)
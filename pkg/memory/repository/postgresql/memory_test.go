@@ -0,0 +1,192 @@
+package postgresql
+
+import (
+	"doc-translate-go/pkg/memory/entity"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+func newTranslationMemoryMock(t *testing.T) (sqlmock.Sqlmock, *PostgresqlTranslationMemoryRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewPostgresqlTranslationMemoryRepository(db)
+
+	return mock, repo
+}
+
+func TestPostgresqlTranslationMemoryRepository_Upsert(t *testing.T) {
+	mock, repo := newTranslationMemoryMock(t)
+
+	// One unit more than a batch takes two statements
+	var units []*entity.Unit
+	for i := 0; i <= upsertBatchSize; i++ {
+		units = append(units, &entity.Unit{SourceLang: "en", TargetLang: "vi", Source: fmt.Sprintf("Hello %d", i), Target: fmt.Sprintf("Xin chào %d", i), CreatedBy: "isid"})
+	}
+
+	cmd := `INSERT INTO translation_units \(source_language, target_language, source_text, target_text, created_at, updated_at, created_by\)
+                VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7\), (.+)
+                ON CONFLICT \(source_language, target_language, md5\(source_text\)\)
+                DO UPDATE SET target_text = EXCLUDED.target_text, updated_at = EXCLUDED.updated_at;`
+	mock.ExpectExec(cmd).WillReturnResult(sqlmock.NewResult(0, upsertBatchSize))
+
+	last := `INSERT INTO translation_units \(source_language, target_language, source_text, target_text, created_at, updated_at, created_by\)
+                VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7\)
+                ON CONFLICT`
+	mock.ExpectExec(last).
+		WithArgs("en", "vi", "Hello 500", "Xin chào 500", sqlmock.AnyArg(), sqlmock.AnyArg(), "isid").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.Upsert(units); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlTranslationMemoryRepository_FindExact(t *testing.T) {
+	mock, repo := newTranslationMemoryMock(t)
+
+	cmd := `SELECT source_text, target_text
+                FROM translation_units
+                WHERE source_language = \$1 AND target_language = \$2 AND md5\(source_text\) IN \(SELECT md5\(s\) FROM unnest\(\$3::text\[\]\) AS s\);`
+	mock.ExpectQuery(cmd).
+		WithArgs("en", "vi", pq.Array([]string{"Hello", "Bye"})).
+		WillReturnRows(sqlmock.NewRows([]string{"source_text", "target_text"}).AddRow("Hello", "Xin chào"))
+
+	got, err := repo.FindExact("en", "vi", []string{"Hello", "Bye"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"Hello": "Xin chào"}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlTranslationMemoryRepository_FindExact_Many(t *testing.T) {
+	mock, repo := newTranslationMemoryMock(t)
+
+	// More sources than a statement takes parameters still make one query
+	sources := make([]string, 70000)
+	for i := range sources {
+		sources[i] = fmt.Sprintf("Segment %d", i)
+	}
+
+	mock.ExpectQuery(`SELECT source_text, target_text FROM translation_units`).
+		WithArgs("en", "vi", pq.Array(sources)).
+		WillReturnRows(sqlmock.NewRows([]string{"source_text", "target_text"}))
+
+	if _, err := repo.FindExact("en", "vi", sources); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlTranslationMemoryRepository_FindExact_Nothing(t *testing.T) {
+	mock, repo := newTranslationMemoryMock(t)
+
+	// No segments, no query
+	got, err := repo.FindExact("en", "vi", nil)
+	if err != nil || len(got) != 0 {
+		t.Fatalf("expected no match, got %v, %v", got, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlTranslationMemoryRepository_FindFuzzy(t *testing.T) {
+	mock, repo := newTranslationMemoryMock(t)
+
+	// All sources are scored in one query, Bye has no match
+	cmd := `SELECT s.source, m.source_text, m.target_text, m.score
+                FROM unnest\(\$3::text\[\]\) AS s\(source\)
+                CROSS JOIN LATERAL \(
+                    SELECT source_text, target_text, similarity\(source_text, s.source\) AS score
+                    FROM translation_units
+                    WHERE source_language = \$1 AND target_language = \$2 AND source_text % s.source AND similarity\(source_text, s.source\) >= \$4
+                    ORDER BY score DESC, id
+                    LIMIT 1
+                \) AS m;`
+	mock.ExpectQuery(cmd).
+		WithArgs("en", "vi", pq.Array([]string{"Hello there", "Bye"}), 0.75).
+		WillReturnRows(sqlmock.NewRows([]string{"source", "source_text", "target_text", "score"}).AddRow("Hello there", "Hello there!", "Xin chào!", 0.8))
+
+	got, err := repo.FindFuzzy("en", "vi", []string{"Hello there", "Bye"}, 0.75)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]*entity.Match{"Hello there": {Source: "Hello there!", Target: "Xin chào!", Score: 0.8}}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlTranslationMemoryRepository_FindFuzzy_Nothing(t *testing.T) {
+	mock, repo := newTranslationMemoryMock(t)
+
+	// No sources, no query
+	got, err := repo.FindFuzzy("en", "vi", nil, 0.75)
+	if err != nil || len(got) != 0 {
+		t.Fatalf("expected no match, got %v, %v", got, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresqlTranslationMemoryRepository_ListByLanguages(t *testing.T) {
+	mock, repo := newTranslationMemoryMock(t)
+
+	now := time.Now()
+	want := []*entity.Unit{{Id: 1, SourceLang: "en", TargetLang: "vi", Source: "Hello", Target: "Xin chào", CreatedAt: now, UpdatedAt: now, CreatedBy: "isid"}}
+
+	rows := sqlmock.NewRows([]string{"id", "source_language", "target_language", "source_text", "target_text", "created_at", "updated_at", "created_by"})
+	for _, u := range want {
+		rows.AddRow(u.Id, u.SourceLang, u.TargetLang, u.Source, u.Target, u.CreatedAt, u.UpdatedAt, u.CreatedBy)
+	}
+
+	cmd := `SELECT id, source_language, target_language, source_text, target_text, created_at, updated_at, created_by
+                FROM translation_units
+                WHERE source_language = \$1 AND target_language = \$2
+                ORDER BY id;`
+	mock.ExpectQuery(cmd).WithArgs("en", "vi").WillReturnRows(rows)
+
+	got, err := repo.ListByLanguages("en", "vi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
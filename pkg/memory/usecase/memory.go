@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"doc-translate-go/pkg/memory/entity"
+	"doc-translate-go/pkg/memory/repository"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrInvalid = errors.New("invalid translation memory")
+
+// segmentTag matches the numbered tags standing for markup in segments.
+var segmentTag = regexp.MustCompile(`</?g\d+>|<x\d+/>`)
+
+type TranslationMemoryUseCase struct {
+	repo           repository.TranslationMemoryRepository
+	fuzzyThreshold float64
+}
+
+// NewTranslationMemoryUseCase returns a use case finding close matches scoring
+// fuzzyThreshold or more, from 0 to 1. A threshold above 1 only finds exact
+// matches.
+func NewTranslationMemoryUseCase(repo repository.TranslationMemoryRepository, fuzzyThreshold float64) *TranslationMemoryUseCase {
+	return &TranslationMemoryUseCase{repo, fuzzyThreshold}
+}
+
+// Lookup returns the best match for every segment, nil for those with none.
+// Exact matches whose target lacks the tags of the segment can't be used as
+// its translation and are looked up again as close matches.
+func (uc *TranslationMemoryUseCase) Lookup(sourceLang string, targetLang string, segments []string) ([]*entity.Match, error) {
+	sourceLang, targetLang = normalizeLang(sourceLang), normalizeLang(targetLang)
+
+	exact, err := uc.repo.FindExact(sourceLang, targetLang, unique(segments))
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*entity.Match, len(segments))
+	var inexact []string
+	for i, s := range segments {
+		if target, ok := exact[s]; ok && sameTags(s, target) {
+			matches[i] = &entity.Match{Source: s, Target: target, Score: 1, Exact: true}
+			continue
+		}
+		inexact = append(inexact, s)
+	}
+
+	if uc.fuzzyThreshold > 1 || len(inexact) == 0 {
+		return matches, nil
+	}
+
+	fuzzy, err := uc.repo.FindFuzzy(sourceLang, targetLang, unique(inexact), uc.fuzzyThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, s := range segments {
+		if matches[i] == nil {
+			matches[i] = fuzzy[s]
+		}
+	}
+
+	return matches, nil
+}
+
+// Import stores the translations from sourceLang into targetLang of a TMX
+// file as approved by isid, replacing those stored for the same segments. It
+// returns how many translations were imported.
+func (uc *TranslationMemoryUseCase) Import(b []byte, sourceLang string, targetLang string, isid string) (int, error) {
+	sourceLang, targetLang = normalizeLang(sourceLang), normalizeLang(targetLang)
+	if sourceLang == "" || targetLang == "" {
+		return 0, fmt.Errorf("%w: source and target languages are required", ErrInvalid)
+	}
+
+	pairs, err := parseTMX(b, sourceLang, targetLang)
+	if err != nil {
+		return 0, err
+	}
+
+	// The last translation of a segment wins, as it would imported on its own
+	now := time.Now()
+	index := make(map[string]int)
+	var units []*entity.Unit
+	for _, p := range pairs {
+		u := &entity.Unit{SourceLang: sourceLang, TargetLang: targetLang, Source: p.source, Target: p.target, CreatedAt: now, UpdatedAt: now, CreatedBy: isid}
+
+		if i, ok := index[p.source]; ok {
+			units[i] = u
+			continue
+		}
+		index[p.source] = len(units)
+		units = append(units, u)
+	}
+
+	if err := uc.repo.Upsert(units); err != nil {
+		return 0, err
+	}
+
+	return len(units), nil
+}
+
+// Export returns the translations from sourceLang into targetLang as a TMX
+// file.
+func (uc *TranslationMemoryUseCase) Export(sourceLang string, targetLang string) ([]byte, error) {
+	sourceLang, targetLang = normalizeLang(sourceLang), normalizeLang(targetLang)
+	if sourceLang == "" || targetLang == "" {
+		return nil, fmt.Errorf("%w: source and target languages are required", ErrInvalid)
+	}
+
+	units, err := uc.repo.ListByLanguages(sourceLang, targetLang)
+	if err != nil {
+		return nil, err
+	}
+
+	return writeTMX(units, sourceLang)
+}
+
+func normalizeLang(lang string) string {
+	return strings.ToLower(strings.TrimSpace(lang))
+}
+
+func unique(segments []string) []string {
+	result := slices.Clone(segments)
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// sameTags tells whether target holds the same tags as source, in any order.
+func sameTags(source string, target string) bool {
+	s, t := segmentTag.FindAllString(source, -1), segmentTag.FindAllString(target, -1)
+	slices.Sort(s)
+	slices.Sort(t)
+	return slices.Equal(s, t)
+}
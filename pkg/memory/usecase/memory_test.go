@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"doc-translate-go/pkg/memory/entity"
+	"doc-translate-go/pkg/memory/repository/postgresql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+func newTestTranslationMemoryUseCase(t *testing.T, fuzzyThreshold float64) (*TranslationMemoryUseCase, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewTranslationMemoryUseCase(postgresql.NewPostgresqlTranslationMemoryRepository(db), fuzzyThreshold), mock
+}
+
+func TestTranslationMemoryUseCase_Lookup(t *testing.T) {
+	uc, mock := newTestTranslationMemoryUseCase(t, 0.75)
+
+	segments := []string{"Hello", "See <g1>the docs</g1>", "Hello", "Goodbye"}
+
+	// Segments are looked up once, with the languages as they are stored
+	mock.ExpectQuery(`SELECT source_text, target_text FROM translation_units`).
+		WithArgs("en", "vi", pq.Array([]string{"Goodbye", "Hello", "See <g1>the docs</g1>"})).
+		WillReturnRows(sqlmock.NewRows([]string{"source_text", "target_text"}).
+			AddRow("Hello", "Xin chào").
+			AddRow("See <g1>the docs</g1>", "Xem tài liệu"))
+	// The exact match lost its tags, so it only helps the translator. The
+	// others are scored together
+	mock.ExpectQuery(`SELECT (.+) FROM unnest`).
+		WithArgs("en", "vi", pq.Array([]string{"Goodbye", "See <g1>the docs</g1>"}), 0.75).
+		WillReturnRows(sqlmock.NewRows([]string{"source", "source_text", "target_text", "score"}).AddRow("See <g1>the docs</g1>", "See <g1>the docs</g1>", "Xem tài liệu", 1.0))
+
+	got, err := uc.Lookup("EN", "vi", segments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hello := &entity.Match{Source: "Hello", Target: "Xin chào", Score: 1, Exact: true}
+	want := []*entity.Match{
+		hello,
+		{Source: "See <g1>the docs</g1>", Target: "Xem tài liệu", Score: 1},
+		hello,
+		nil,
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslationMemoryUseCase_Lookup_ExactOnly(t *testing.T) {
+	uc, mock := newTestTranslationMemoryUseCase(t, 1.1)
+
+	mock.ExpectQuery(`SELECT source_text, target_text FROM translation_units`).
+		WithArgs("en", "vi", pq.Array([]string{"Goodbye"})).
+		WillReturnRows(sqlmock.NewRows([]string{"source_text", "target_text"}))
+
+	got, err := uc.Lookup("en", "vi", []string{"Goodbye"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []*entity.Match{nil}; !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslationMemoryUseCase_Import(t *testing.T) {
+	uc, mock := newTestTranslationMemoryUseCase(t, 0.75)
+
+	tmx := `<tmx version="1.4"><body>
+<tu><tuv xml:lang="en"><seg>Hello</seg></tuv><tuv xml:lang="vi"><seg>Chào</seg></tuv></tu>
+<tu><tuv xml:lang="en"><seg>Bye</seg></tuv><tuv xml:lang="vi"><seg>Tạm biệt</seg></tuv></tu>
+<tu><tuv xml:lang="en"><seg>Hello</seg></tuv><tuv xml:lang="vi"><seg>Xin chào</seg></tuv></tu>
+</body></tmx>`
+
+	// The last translation of Hello replaces the first
+	mock.ExpectExec(`INSERT INTO translation_units`).
+		WithArgs("en", "vi", "Hello", "Xin chào", sqlmock.AnyArg(), sqlmock.AnyArg(), "isid", "en", "vi", "Bye", "Tạm biệt", sqlmock.AnyArg(), sqlmock.AnyArg(), "isid").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	n, err := uc.Import([]byte(tmx), "en", "VI", "isid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("expected %v units, got %v", 2, n)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslationMemoryUseCase_Import_Invalid(t *testing.T) {
+	uc, mock := newTestTranslationMemoryUseCase(t, 0.75)
+
+	if _, err := uc.Import([]byte(sampleTMX), "en", "", "isid"); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected %v, got %v", ErrInvalid, err)
+	}
+	if _, err := uc.Import([]byte("en,vi\nHello,Xin chào\n"), "en", "vi", "isid"); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected %v, got %v", ErrInvalid, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranslationMemoryUseCase_Export(t *testing.T) {
+	uc, mock := newTestTranslationMemoryUseCase(t, 0.75)
+
+	mock.ExpectQuery(`SELECT (.+) FROM translation_units`).
+		WithArgs("en", "vi").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "source_language", "target_language", "source_text", "target_text", "created_at", "updated_at", "created_by"}))
+
+	b, err := uc.Export("en", "vi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An empty memory is still a TMX file
+	if got, err := parseTMX(b, "en", "vi"); err != nil || len(got) != 0 {
+		t.Fatalf("expected an empty memory, got %v, %v", got, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"bytes"
+	"doc-translate-go/pkg/memory/entity"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/encoding/unicode"
+)
+
+// tmxDate is the format of TMX dates, always in UTC.
+const tmxDate = "20060102T150405Z"
+
+// tmxPair is a segment and its translation read from a TMX file.
+type tmxPair struct {
+	source string
+	target string
+}
+
+// parseTMX reads the translations from sourceLang into targetLang of a TMX
+// file, UTF-8 or UTF-16 with a byte order mark. Paired codes, bpt and ept,
+// become <gN> and </gN> tags numbered by their i attribute and placeholders
+// numbered by their x attribute become <xN/> tags, as markup stands in
+// segments. The native code inside them is dropped.
+func parseTMX(b []byte, sourceLang string, targetLang string) ([]tmxPair, error) {
+	if bytes.HasPrefix(b, []byte{0xff, 0xfe}) || bytes.HasPrefix(b, []byte{0xfe, 0xff}) {
+		utf8, err := unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder().Bytes(b)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		b = utf8
+	}
+
+	d := xml.NewDecoder(bytes.NewReader(bytes.TrimPrefix(b, []byte("\ufeff"))))
+	// The content is UTF-8 by now, whatever the declaration says
+	d.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	var pairs []tmxPair
+	// Segments of the unit being read by language, and the language and segment being read
+	unit := make(map[string][]string)
+	lang := ""
+	var seg strings.Builder
+	inSeg, inCode, root := false, 0, true
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if root && t.Name.Local != "tmx" {
+				return nil, fmt.Errorf("%w: not a TMX file", ErrInvalid)
+			}
+			root = false
+
+			switch t.Name.Local {
+			case "tuv":
+				// TMX 1.1 named the language lang
+				if lang = xmlLang(t); lang == "" {
+					lang = attr(t, "lang")
+				}
+			case "seg":
+				inSeg = true
+				seg.Reset()
+			case "bpt", "ept", "ph", "it", "ut":
+				if inSeg && inCode == 0 {
+					seg.WriteString(tmxTag(t))
+				}
+				inCode++
+			}
+		case xml.CharData:
+			if inSeg && inCode == 0 {
+				seg.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "seg":
+				inSeg = false
+				unit[lang] = append(unit[lang], strings.TrimSpace(seg.String()))
+			case "bpt", "ept", "ph", "it", "ut":
+				inCode--
+			case "tu":
+				source, target := tmxFirst(unit, sourceLang), tmxFirst(unit, targetLang)
+				if source != "" && target != "" {
+					pairs = append(pairs, tmxPair{source, target})
+				}
+				clear(unit)
+			}
+		}
+	}
+
+	if root {
+		return nil, fmt.Errorf("%w: not a TMX file", ErrInvalid)
+	}
+
+	return pairs, nil
+}
+
+// tmxTag returns the segment tag standing for a code, empty when it has no
+// number to tell it by.
+func tmxTag(e xml.StartElement) string {
+	switch e.Name.Local {
+	case "bpt", "ept":
+		i, err := strconv.Atoi(attr(e, "i"))
+		if err != nil {
+			return ""
+		}
+		if e.Name.Local == "ept" {
+			return fmt.Sprintf("</g%d>", i)
+		}
+		return fmt.Sprintf("<g%d>", i)
+	}
+
+	x, err := strconv.Atoi(attr(e, "x"))
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("<x%d/>", x)
+}
+
+// tmxFirst returns the first segment of a unit in lang or, failing that, in
+// one of its regional variants.
+func tmxFirst(unit map[string][]string, lang string) string {
+	var variants []string
+	for l, segs := range unit {
+		if strings.EqualFold(l, lang) && len(segs) > 0 {
+			return segs[0]
+		}
+		if langMatches(l, lang) && len(segs) > 0 {
+			variants = append(variants, l)
+		}
+	}
+
+	if len(variants) == 0 {
+		return ""
+	}
+	slices.Sort(variants)
+	return unit[variants[0]][0]
+}
+
+// langMatches tells whether tag, such as en-US, is lang or a variant of it.
+func langMatches(tag string, lang string) bool {
+	tag, lang = strings.ToLower(tag), strings.ToLower(lang)
+	return tag == lang || strings.HasPrefix(tag, lang+"-") || strings.HasPrefix(tag, lang+"_")
+}
+
+func xmlLang(e xml.StartElement) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == "lang" && (a.Name.Space == "xml" || a.Name.Space == "http://www.w3.org/XML/1998/namespace") {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name && a.Name.Space == "" {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// writeTMX writes units as a TMX 1.4 file. Segment tags become empty codes,
+// bpt and ept for <gN> and </gN> and ph for <xN/>, which parseTMX reads back.
+func writeTMX(units []*entity.Unit, sourceLang string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<tmx version="1.4">` + "\n")
+	fmt.Fprintf(&buf, `  <header creationtool="doc-translate-go" creationtoolversion="1.0" datatype="plaintext" segtype="sentence" adminlang="en" srclang="%s" o-tmf="doc-translate-go"/>`+"\n", escape(sourceLang))
+	buf.WriteString("  <body>\n")
+
+	for _, u := range units {
+		fmt.Fprintf(&buf, `    <tu creationdate="%s" creationid="%s" changedate="%s">`+"\n",
+			u.CreatedAt.UTC().Format(tmxDate), escape(u.CreatedBy), u.UpdatedAt.UTC().Format(tmxDate))
+		fmt.Fprintf(&buf, `      <tuv xml:lang="%s"><seg>%s</seg></tuv>`+"\n", escape(u.SourceLang), tmxSeg(u.Source))
+		fmt.Fprintf(&buf, `      <tuv xml:lang="%s"><seg>%s</seg></tuv>`+"\n", escape(u.TargetLang), tmxSeg(u.Target))
+		buf.WriteString("    </tu>\n")
+	}
+
+	buf.WriteString("  </body>\n</tmx>\n")
+
+	return buf.Bytes(), nil
+}
+
+// tmxSeg returns the content of a seg element for a segment.
+func tmxSeg(segment string) string {
+	var b strings.Builder
+	prev := 0
+	for _, m := range segmentTag.FindAllStringIndex(segment, -1) {
+		b.WriteString(escape(segment[prev:m[0]]))
+
+		tag := segment[m[0]:m[1]]
+		n := strings.Trim(tag, "</gx>")
+		switch {
+		case strings.HasPrefix(tag, "</g"):
+			fmt.Fprintf(&b, `<ept i="%s"/>`, n)
+		case strings.HasPrefix(tag, "<g"):
+			fmt.Fprintf(&b, `<bpt i="%s"/>`, n)
+		default:
+			fmt.Fprintf(&b, `<ph x="%s"/>`, n)
+		}
+		prev = m[1]
+	}
+	b.WriteString(escape(segment[prev:]))
+
+	return b.String()
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
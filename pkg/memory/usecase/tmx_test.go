@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"doc-translate-go/pkg/memory/entity"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/text/encoding/unicode"
+)
+
+const sampleTMX = `<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header creationtool="Vendor" creationtoolversion="2" datatype="plaintext" segtype="sentence" adminlang="en-US" srclang="en-US" o-tmf="Vendor"/>
+  <body>
+    <tu>
+      <tuv xml:lang="en-US"><seg>Take one tablet &amp; rest.</seg></tuv>
+      <tuv xml:lang="vi-VN"><seg>Uống một viên &amp; nghỉ ngơi.</seg></tuv>
+    </tu>
+    <tu>
+      <tuv xml:lang="en-US"><seg>See <bpt i="1">&lt;a&gt;</bpt>the <hi>docs</hi><ept i="1">&lt;/a&gt;</ept><ph x="2">&lt;br/&gt;</ph></seg></tuv>
+      <tuv xml:lang="vi-VN"><seg>Xem <bpt i="1">&lt;a&gt;</bpt>tài liệu<ept i="1">&lt;/a&gt;</ept><ph x="2">&lt;br/&gt;</ph></seg></tuv>
+    </tu>
+    <tu>
+      <tuv xml:lang="en-US"><seg>Only English</seg></tuv>
+      <tuv xml:lang="fr"><seg>Seulement français</seg></tuv>
+    </tu>
+  </body>
+</tmx>
+`
+
+func TestParseTMX(t *testing.T) {
+	want := []tmxPair{
+		{"Take one tablet & rest.", "Uống một viên & nghỉ ngơi."},
+		{"See <g1>the docs</g1><x2/>", "Xem <g1>tài liệu</g1><x2/>"},
+	}
+
+	got, err := parseTMX([]byte(sampleTMX), "en", "vi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestParseTMX_UTF16(t *testing.T) {
+	b, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes([]byte(`<?xml version="1.0" encoding="UTF-16"?>
+<tmx version="1.4"><body><tu><tuv lang="en"><seg>Hello</seg></tuv><tuv lang="vi"><seg>Xin chào</seg></tuv></tu></body></tmx>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := parseTMX(b, "en", "vi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []tmxPair{{"Hello", "Xin chào"}}; !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestParseTMX_Invalid(t *testing.T) {
+	for _, b := range []string{"", "<martif/>", "<tmx><body>"} {
+		if _, err := parseTMX([]byte(b), "en", "vi"); !errors.Is(err, ErrInvalid) {
+			t.Fatalf("%q: expected %v, got %v", b, ErrInvalid, err)
+		}
+	}
+}
+
+func TestWriteTMX(t *testing.T) {
+	now := time.Date(2026, 10, 18, 6, 16, 7, 0, time.UTC)
+	units := []*entity.Unit{
+		{SourceLang: "en", TargetLang: "vi", Source: "See <g1>the docs</g1><x2/> & more", Target: "Xem <g1>tài liệu</g1><x2/> & thêm", CreatedAt: now, UpdatedAt: now, CreatedBy: "isid"},
+	}
+
+	b, err := writeTMX(units, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header creationtool="doc-translate-go" creationtoolversion="1.0" datatype="plaintext" segtype="sentence" adminlang="en" srclang="en" o-tmf="doc-translate-go"/>
+  <body>
+    <tu creationdate="20261018T061607Z" creationid="isid" changedate="20261018T061607Z">
+      <tuv xml:lang="en"><seg>See <bpt i="1"/>the docs<ept i="1"/><ph x="2"/> &amp; more</seg></tuv>
+      <tuv xml:lang="vi"><seg>Xem <bpt i="1"/>tài liệu<ept i="1"/><ph x="2"/> &amp; thêm</seg></tuv>
+    </tu>
+  </body>
+</tmx>
+`
+	if string(b) != want {
+		t.Fatalf("expected %s, got %s", want, b)
+	}
+
+	// What is exported is imported back as it was
+	got, err := parseTMX(b, "en", "vi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []tmxPair{{units[0].Source, units[0].Target}}; !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,39 @@
+package ooxml
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrPartTooLarge = errors.New("document part too large")
+
+// MaxPartSize is the most bytes a part of a package may decompress to. Parts
+// are read whole, so a small zip decompressing to gigabytes is refused.
+var MaxPartSize int64 = 64 << 20
+
+// ReadFile reads the part of r named name, failing with ErrPartTooLarge past
+// MaxPartSize.
+func ReadFile(r *zip.Reader, name string) ([]byte, error) {
+	f, err := r.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err == nil && info.Size() > MaxPartSize {
+		return nil, fmt.Errorf("%w: %s", ErrPartTooLarge, name)
+	}
+
+	// The limit holds while reading too, whatever the header says
+	b, err := io.ReadAll(io.LimitReader(f, MaxPartSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > MaxPartSize {
+		return nil, fmt.Errorf("%w: %s", ErrPartTooLarge, name)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,53 @@
+package ooxml
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func newZip(t *testing.T, write func(w *zip.Writer)) *zip.Reader {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	write(w)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func withMaxPartSize(t *testing.T, n int64) {
+	prev := MaxPartSize
+	MaxPartSize = n
+	t.Cleanup(func() { MaxPartSize = prev })
+}
+
+func TestReadFile(t *testing.T) {
+	withMaxPartSize(t, 1024)
+
+	// A kilobyte of zeros compresses to next to nothing, one byte more is refused
+	r := newZip(t, func(w *zip.Writer) {
+		for name, size := range map[string]int{"fits.xml": 1024, "bomb.xml": 1025} {
+			f, err := w.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.Write(make([]byte, size))
+		}
+	})
+
+	b, err := ReadFile(r, "fits.xml")
+	if err != nil || len(b) != 1024 {
+		t.Fatalf("expected 1024 bytes, got %v, %v", len(b), err)
+	}
+
+	if _, err := ReadFile(r, "bomb.xml"); !errors.Is(err, ErrPartTooLarge) {
+		t.Fatalf("expected %v, got %v", ErrPartTooLarge, err)
+	}
+}
@@ -0,0 +1,124 @@
+package subtitle
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LineLengths are the most characters a cue line may hold by target
+// language, e.g. 16 for ja, with * for any other language.
+type LineLengths map[string]int
+
+// For returns the line length of a language, falling back to its base
+// language, zh for zh-TW, then to *. It's 0, for no reflow, when none is set.
+func (l LineLengths) For(lang string) int {
+	if n, ok := l[lang]; ok {
+		return n
+	}
+	if base, _, ok := strings.Cut(lang, "-"); ok {
+		if n, ok := l[base]; ok {
+			return n
+		}
+	}
+	return l["*"]
+}
+
+var tag = regexp.MustCompile(`^<[^<>]*>`)
+
+// Reflow wraps text into lines of at most width characters, tags not
+// counted. Lines break between words, or anywhere in a word too long for a
+// line, as text written without spaces is. A width of 0 leaves text as it is.
+func Reflow(text string, width int) string {
+	if width <= 0 {
+		return text
+	}
+
+	var lines []string
+	line := ""
+	for _, word := range words(text) {
+		for _, piece := range split(word, width) {
+			if line != "" && length(line)+1+length(piece) > width {
+				lines = append(lines, line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += piece
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// words splits text at whitespace outside tags.
+func words(text string) []string {
+	var result []string
+	start := -1
+	for i := 0; i < len(text); {
+		if loc := tag.FindStringIndex(text[i:]); loc != nil {
+			if start < 0 {
+				start = i
+			}
+			i += loc[1]
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case unicode.IsSpace(r) && start >= 0:
+			result = append(result, text[start:i])
+			start = -1
+		case !unicode.IsSpace(r) && start < 0:
+			start = i
+		}
+		i += size
+	}
+	if start >= 0 {
+		result = append(result, text[start:])
+	}
+
+	return result
+}
+
+// split cuts a word into pieces of at most width characters.
+func split(word string, width int) []string {
+	var pieces []string
+	start, n := 0, 0
+	for i := 0; i < len(word); {
+		if loc := tag.FindStringIndex(word[i:]); loc != nil {
+			i += loc[1]
+			continue
+		}
+
+		if n == width {
+			pieces = append(pieces, word[start:i])
+			start, n = i, 0
+		}
+		_, size := utf8.DecodeRuneInString(word[i:])
+		i += size
+		n++
+	}
+
+	return append(pieces, word[start:])
+}
+
+// length counts the characters of s, tags not counted.
+func length(s string) int {
+	n := 0
+	for i := 0; i < len(s); {
+		if loc := tag.FindStringIndex(s[i:]); loc != nil {
+			i += loc[1]
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		n++
+	}
+	return n
+}
@@ -0,0 +1,38 @@
+package subtitle
+
+import "testing"
+
+func TestReflow(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  string
+	}{
+		{"Hello there,\nmy old friend.", 0, "Hello there,\nmy old friend."},
+		{"Hello there, my old friend.", 16, "Hello there, my\nold friend."},
+		// Tags don't count and are never broken
+		{"<font color=\"red\">Hello</font> there, my old friend.", 16, "<font color=\"red\">Hello</font> there, my\nold friend."},
+		// Text without spaces breaks anywhere
+		{"こんにちは、元気ですか", 5, "こんにちは\n、元気です\nか"},
+	}
+
+	for _, tt := range tests {
+		if got := Reflow(tt.text, tt.width); got != tt.want {
+			t.Fatalf("%q: expected %q, got %q", tt.text, tt.want, got)
+		}
+	}
+}
+
+func TestLineLengths_For(t *testing.T) {
+	l := LineLengths{"*": 42, "zh": 16, "zh-TW": 14}
+
+	for lang, want := range map[string]int{"vi": 42, "zh": 16, "zh-CN": 16, "zh-TW": 14} {
+		if got := l.For(lang); got != want {
+			t.Fatalf("%v: expected %v, got %v", lang, want, got)
+		}
+	}
+
+	if got := LineLengths(nil).For("vi"); got != 0 {
+		t.Fatalf("expected no line length, got %v", got)
+	}
+}
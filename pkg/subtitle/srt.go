@@ -0,0 +1,9 @@
+package subtitle
+
+// ParseSRT parses a SubRip file: cues of an index, a timing line and text,
+// separated by blank lines.
+func ParseSRT(b []byte) (*File, error) {
+	return parse(b, func(lines []string, first bool) (*Cue, error) {
+		return newCue(lines)
+	})
+}
@@ -0,0 +1,213 @@
+package subtitle
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrNotText = errors.New("not utf-8 text")
+	ErrInvalid = errors.New("invalid subtitle file")
+)
+
+const bom = "\ufeff"
+
+// Cue is text shown between two timestamps.
+type Cue struct {
+	// SRT index or WebVTT identifier, which may be empty in WebVTT
+	ID string
+	// Timing line as written, with any WebVTT cue settings
+	Timing string
+	Start  time.Duration
+	End    time.Duration
+	// Lines of the cue, separated by "\n"
+	Text string
+}
+
+// block is a piece of the file, either a cue or kept as it is, such as blank
+// lines, the WebVTT header, notes and styles.
+type block struct {
+	raw string
+	cue *Cue
+	// Line break ending the cue, if the file doesn't end on it
+	eol string
+}
+
+// File is a subtitle file. Writing it back only changes the text of its
+// cues, everything else is kept as it was read.
+type File struct {
+	bom    string
+	eol    string
+	blocks []block
+}
+
+// Cues returns the cues in file order, to be changed in place.
+func (f *File) Cues() []*Cue {
+	var result []*Cue
+	for _, b := range f.blocks {
+		if b.cue != nil {
+			result = append(result, b.cue)
+		}
+	}
+	return result
+}
+
+// Bytes writes the file back, with the cues' text as it is now.
+func (f *File) Bytes() []byte {
+	var b strings.Builder
+	b.WriteString(f.bom)
+
+	for _, bl := range f.blocks {
+		if bl.cue == nil {
+			b.WriteString(bl.raw)
+			continue
+		}
+
+		c := bl.cue
+		if c.ID != "" {
+			b.WriteString(c.ID + f.eol)
+		}
+		b.WriteString(c.Timing)
+
+		// A blank line would end the cue
+		for _, line := range strings.Split(strings.ReplaceAll(c.Text, "\r\n", "\n"), "\n") {
+			if strings.TrimSpace(line) != "" {
+				b.WriteString(f.eol + line)
+			}
+		}
+		b.WriteString(bl.eol)
+	}
+
+	return []byte(b.String())
+}
+
+// parse splits b into blocks of lines separated by blank lines, and makes
+// each into a cue with newBlock, which returns nil for blocks to keep.
+func parse(b []byte, newBlock func(lines []string, first bool) (*Cue, error)) (*File, error) {
+	if !utf8.Valid(b) {
+		return nil, ErrNotText
+	}
+
+	s := string(b)
+	f := &File{eol: "\n"}
+	if strings.HasPrefix(s, bom) {
+		f.bom = bom
+		s = s[len(bom):]
+	}
+	if strings.Contains(s, "\r\n") {
+		f.eol = "\r\n"
+	}
+
+	// Lines of the block being read, with their line breaks, and where it starts
+	var group []string
+	start := 0
+
+	end := func() error {
+		if len(group) == 0 {
+			return nil
+		}
+
+		lines := make([]string, len(group))
+		for i, line := range group {
+			lines[i] = strings.TrimRight(line, "\r\n")
+		}
+
+		c, err := newBlock(lines, len(f.blocks) == 0)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", start, err)
+		}
+
+		if c == nil {
+			f.blocks = append(f.blocks, block{raw: strings.Join(group, "")})
+		} else {
+			last := group[len(group)-1]
+			f.blocks = append(f.blocks, block{cue: c, eol: last[len(lines[len(lines)-1]):]})
+		}
+
+		group = nil
+		return nil
+	}
+
+	for i, line := range strings.SplitAfter(s, "\n") {
+		if line == "" {
+			continue
+		}
+
+		if strings.TrimSpace(line) != "" {
+			if len(group) == 0 {
+				start = i + 1
+			}
+			group = append(group, line)
+			continue
+		}
+
+		if err := end(); err != nil {
+			return nil, err
+		}
+		f.blocks = append(f.blocks, block{raw: line})
+	}
+
+	if err := end(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+var timing = regexp.MustCompile(`^\s*(\S+)\s+-->\s+(\S+)(\s.*)?$`)
+
+// newCue makes a cue of a block's lines: an optional identifier, the timing
+// line and the text.
+func newCue(lines []string) (*Cue, error) {
+	c := &Cue{}
+	if !timing.MatchString(lines[0]) {
+		c.ID = lines[0]
+		lines = lines[1:]
+	}
+
+	if len(lines) == 0 || !timing.MatchString(lines[0]) {
+		return nil, fmt.Errorf("%w: cue without a timing line", ErrInvalid)
+	}
+
+	m := timing.FindStringSubmatch(lines[0])
+
+	var err error
+	if c.Start, err = parseTimestamp(m[1]); err != nil {
+		return nil, err
+	}
+	if c.End, err = parseTimestamp(m[2]); err != nil {
+		return nil, err
+	}
+
+	c.Timing = lines[0]
+	c.Text = strings.Join(lines[1:], "\n")
+
+	return c, nil
+}
+
+var timestamp = regexp.MustCompile(`^(?:(\d+):)?(\d{2}):(\d{2})[,.](\d{3})$`)
+
+// parseTimestamp parses hh:mm:ss,mmm as SRT writes it, or WebVTT's
+// hh:mm:ss.mmm where the hours may be left out.
+func parseTimestamp(s string) (time.Duration, error) {
+	m := timestamp.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("%w: bad timestamp %q", ErrInvalid, s)
+	}
+
+	var d time.Duration
+	for i, unit := range []time.Duration{time.Hour, time.Minute, time.Second, time.Millisecond} {
+		if m[i+1] == "" {
+			continue
+		}
+		n, _ := strconv.Atoi(m[i+1])
+		d += time.Duration(n) * unit
+	}
+
+	return d, nil
+}
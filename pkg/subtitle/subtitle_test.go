@@ -0,0 +1,92 @@
+package subtitle
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+const sampleSRT = "\ufeff1\r\n00:00:01,000 --> 00:00:03,500\r\nHello <i>there</i>,\r\nfriend.\r\n\r\n" +
+	"2\r\n00:01:02,250 --> 00:01:04,000  X1:40 X2:600 Y1:20 Y2:50\r\n♪ ♪\r\n\r\n\r\n" +
+	"3\r\n01:00:00,000 --> 01:00:02,000\r\nBye."
+
+const sampleVTT = "WEBVTT - Sample\n\n" +
+	"STYLE\n::cue { color: yellow; }\n\n" +
+	"NOTE this is\nkept as it is\n\n" +
+	"intro\n00:01.000 --> 00:03.500 align:start line:0\n<v Bob>Hello there\n\n" +
+	"00:00:05.000 --> 00:00:06.000\nBye.\n"
+
+func TestParseSRT(t *testing.T) {
+	f, err := ParseSRT([]byte(sampleSRT))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cues := f.Cues()
+	if len(cues) != 3 {
+		t.Fatalf("expected 3 cues, got %v", len(cues))
+	}
+
+	want := Cue{ID: "2", Timing: "00:01:02,250 --> 00:01:04,000  X1:40 X2:600 Y1:20 Y2:50", Start: time.Minute + 2250*time.Millisecond, End: time.Minute + 4*time.Second, Text: "♪ ♪"}
+	if *cues[1] != want {
+		t.Fatalf("expected %+v, got %+v", want, *cues[1])
+	}
+	if cues[0].Text != "Hello <i>there</i>,\nfriend." {
+		t.Fatalf("unexpected text %q", cues[0].Text)
+	}
+
+	if got := string(f.Bytes()); got != sampleSRT {
+		t.Fatalf("expected %q, got %q", sampleSRT, got)
+	}
+
+	cues[0].Text = "Xin chào <i>bạn</i>\n\nthân mến."
+	cues[2].Text = "Tạm biệt."
+	want2 := "\ufeff1\r\n00:00:01,000 --> 00:00:03,500\r\nXin chào <i>bạn</i>\r\nthân mến.\r\n\r\n" +
+		"2\r\n00:01:02,250 --> 00:01:04,000  X1:40 X2:600 Y1:20 Y2:50\r\n♪ ♪\r\n\r\n\r\n" +
+		"3\r\n01:00:00,000 --> 01:00:02,000\r\nTạm biệt."
+	if got := string(f.Bytes()); got != want2 {
+		t.Fatalf("expected %q, got %q", want2, got)
+	}
+}
+
+func TestParseVTT(t *testing.T) {
+	f, err := ParseVTT([]byte(sampleVTT))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cues := f.Cues()
+	if len(cues) != 2 {
+		t.Fatalf("expected 2 cues, got %v", len(cues))
+	}
+
+	want := Cue{ID: "intro", Timing: "00:01.000 --> 00:03.500 align:start line:0", Start: time.Second, End: 3500 * time.Millisecond, Text: "<v Bob>Hello there"}
+	if *cues[0] != want {
+		t.Fatalf("expected %+v, got %+v", want, *cues[0])
+	}
+	if cues[1].ID != "" || cues[1].Start != 5*time.Second {
+		t.Fatalf("unexpected cue %+v", *cues[1])
+	}
+
+	if got := string(f.Bytes()); got != sampleVTT {
+		t.Fatalf("expected %q, got %q", sampleVTT, got)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		parse func([]byte) (*File, error)
+		b     string
+	}{
+		{ParseVTT, "1\n00:00:01.000 --> 00:00:02.000\nNo header\n"},
+		{ParseVTT, "WEBVTT\n\nJust text\n"},
+		{ParseSRT, "1\n00:00:01,000 --> 00:00:02,000\nText\n\nMore text\n"},
+		{ParseSRT, "1\n00:00:01 --> 00:00:02\nText\n"},
+	}
+
+	for _, tt := range tests {
+		if _, err := tt.parse([]byte(tt.b)); !errors.Is(err, ErrInvalid) {
+			t.Fatalf("%q: expected %v, got %v", tt.b, ErrInvalid, err)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package subtitle
+
+import (
+	"fmt"
+	"strings"
+)
+
+// vttKept are the blocks a WebVTT file holds besides cues, kept as they are.
+var vttKept = []string{"NOTE", "STYLE", "REGION"}
+
+// ParseVTT parses a WebVTT file: a WEBVTT header, then cues, notes, styles
+// and regions, separated by blank lines.
+func ParseVTT(b []byte) (*File, error) {
+	return parse(b, func(lines []string, first bool) (*Cue, error) {
+		if first {
+			if !isKeyword(lines[0], "WEBVTT") {
+				return nil, fmt.Errorf("%w: missing WEBVTT header", ErrInvalid)
+			}
+			return nil, nil
+		}
+
+		for _, keyword := range vttKept {
+			if isKeyword(lines[0], keyword) {
+				return nil, nil
+			}
+		}
+
+		return newCue(lines)
+	})
+}
+
+// isKeyword tells whether a line is keyword, alone or followed by a space.
+func isKeyword(line string, keyword string) bool {
+	rest, ok := strings.CutPrefix(line, keyword)
+	return ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t')
+}
@@ -0,0 +1,64 @@
+package textdoc
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// cueTag matches the tags of subtitle cue text: <i>, <font color="red">,
+// WebVTT voices and classes such as <v Bob> and <c.yellow>, and timestamps.
+var cueTag = regexp.MustCompile(`<(/?)([^<>\s.]*)[^<>]*>`)
+
+// ParseCue makes a segment of a subtitle cue's text. Tags closed in the cue
+// stand as <gN> around their text and any other tag, such as a timestamp or a
+// voice left open, as <xN/>.
+func ParseCue(b []byte) (*Document, error) {
+	if !utf8.Valid(b) {
+		return nil, ErrNotText
+	}
+
+	text := string(b)
+	tags := cueTag.FindAllStringSubmatchIndex(text, -1)
+
+	// Pair every closing tag with the tag it closes, if it's still the last
+	// one open
+	name := func(i int) string {
+		return strings.ToLower(text[tags[i][4]:tags[i][5]])
+	}
+
+	closes := make(map[int]int)
+	var open []int
+	for i, m := range tags {
+		n := len(open)
+		switch {
+		case m[3] == m[2]:
+			open = append(open, i)
+		case n > 0 && name(open[n-1]) == name(i):
+			closes[i] = open[n-1]
+			open = open[:n-1]
+		}
+	}
+	closed := make(map[int]bool)
+	for _, o := range closes {
+		closed[o] = true
+	}
+
+	var d builder
+	items := make(map[int]int)
+	prev := 0
+	for i, m := range tags {
+		d.addText(text[prev:m[0]])
+		prev = m[1]
+
+		tag := text[m[0]:m[1]]
+		if o, ok := closes[i]; ok {
+			d.closeItem(items[o], tag)
+			continue
+		}
+		items[i] = d.addItem(tag, closed[i])
+	}
+	d.addText(text[prev:])
+
+	return d.document(), nil
+}
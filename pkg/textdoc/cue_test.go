@@ -0,0 +1,38 @@
+package textdoc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCue(t *testing.T) {
+	tests := []struct {
+		cue  string
+		want []string
+	}{
+		{"Hello <i>world</i>", []string{"Hello <g1>world</g1>"}},
+		{"<v Bob>Where <c.yellow>are</c> you?", []string{"<x1/>Where <g2>are</g2> you?"}},
+		{"<00:00:01.000>Karaoke <00:00:02.000>style", []string{"<x1/>Karaoke <x2/>style"}},
+		{"<font color=\"red\">Line one\nline two</font>", []string{"<g1>Line one\nline two</g1>"}},
+		{"♪ ♪", nil},
+	}
+
+	for _, tt := range tests {
+		d, err := ParseCue([]byte(tt.cue))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := d.Segments(); !reflect.DeepEqual(tt.want, got) {
+			t.Fatalf("%q: expected %q, got %q", tt.cue, tt.want, got)
+		}
+
+		b, err := d.Assemble(d.Segments())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.cue {
+			t.Fatalf("expected %q, got %q", tt.cue, b)
+		}
+	}
+}
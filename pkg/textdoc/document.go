@@ -0,0 +1,262 @@
+package textdoc
+
+import (
+	"doc-translate-go/pkg/wordcount"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var ErrSegmentCount = errors.New("segment count doesn't match the document")
+
+// item is markup protected from translation inside a segment. Markup wrapping
+// text, such as a link, stands as <gN>text</gN> in the segment and anything
+// else, such as inline code, as <xN/>.
+type item struct {
+	open  string
+	close string
+	wraps bool
+}
+
+// segment is text to translate along with the markup it holds. Formats
+// escaping their text, such as HTML, give escape to escape a translation with.
+type segment struct {
+	text   string
+	items  []item
+	escape func(string) string
+}
+
+// part is a piece of the document, either kept as it is or translated.
+type part struct {
+	raw string
+	seg *segment
+}
+
+// Document is a text file split into segments to translate and the markup
+// around them, which is never sent for translation.
+type Document struct {
+	parts []part
+}
+
+// Segments returns the text to translate, in document order. Markup inside a
+// segment is replaced by numbered tags, <g1>linked</g1> or <x1/>, which must
+// come back with the translation.
+func (d *Document) Segments() []string {
+	var result []string
+	for _, p := range d.parts {
+		if p.seg != nil {
+			result = append(result, p.seg.text)
+		}
+	}
+	return result
+}
+
+// Texts returns the text of every segment, without the markup in it.
+func (d *Document) Texts() []string {
+	var result []string
+	for _, p := range d.parts {
+		if p.seg != nil {
+			result = append(result, itemTag.ReplaceAllString(p.seg.text, " "))
+		}
+	}
+	return result
+}
+
+// Count counts the text of every segment, without the markup in it.
+func (d *Document) Count() wordcount.Count {
+	var c wordcount.Count
+	for _, text := range d.Texts() {
+		c = c.Add(wordcount.Text(text))
+	}
+	return c
+}
+
+// Assemble returns the document with every segment replaced by its
+// translation, one per segment returned by Segments and in the same order.
+func (d *Document) Assemble(segments []string) ([]byte, error) {
+	if len(segments) != len(d.Segments()) {
+		return nil, ErrSegmentCount
+	}
+
+	var b strings.Builder
+	i := 0
+	for _, p := range d.parts {
+		if p.seg == nil {
+			b.WriteString(p.raw)
+			continue
+		}
+
+		b.WriteString(p.seg.restore(segments[i]))
+		i++
+	}
+
+	return []byte(b.String()), nil
+}
+
+var itemTag = regexp.MustCompile(`(?i)<(/?)g(\d+)>|<x(\d+)/>`)
+
+// restore puts the segment's markup back into its translation. Unknown tags
+// are dropped and markup the translation lost is added at its end, so no
+// markup goes missing from the document.
+func (s *segment) restore(translated string) string {
+	opened := make([]bool, len(s.items))
+	closed := make([]bool, len(s.items))
+
+	markup := func(tag string) string {
+		m := itemTag.FindStringSubmatch(tag)
+
+		n := m[3]
+		if n == "" {
+			n = m[2]
+		}
+		i, _ := strconv.Atoi(n)
+		if i < 1 || i > len(s.items) {
+			return ""
+		}
+
+		// Tags of the wrong kind or repeated are dropped too
+		it := s.items[i-1]
+		atomic := m[3] != ""
+		switch {
+		case atomic == !it.wraps && m[1] == "" && !opened[i-1]:
+			opened[i-1] = true
+			return it.open
+		case !atomic && it.wraps && m[1] == "/" && !closed[i-1]:
+			closed[i-1] = true
+			return it.close
+		}
+		return ""
+	}
+
+	var out strings.Builder
+	tags := itemTag.FindAllString(translated, -1)
+	for i, text := range itemTag.Split(translated, -1) {
+		if s.escape != nil {
+			text = s.escape(text)
+		}
+		out.WriteString(text)
+
+		if i < len(tags) {
+			out.WriteString(markup(tags[i]))
+		}
+	}
+
+	for i, it := range s.items {
+		if !opened[i] {
+			out.WriteString(it.open)
+		}
+		if it.wraps && !closed[i] {
+			out.WriteString(it.close)
+		}
+	}
+
+	return out.String()
+}
+
+// builder collects the parts of a document as it's parsed.
+type builder struct {
+	parts []part
+	// The segment being built, if any, and what it holds so far
+	text  strings.Builder
+	items []item
+	open  bool
+	// The source of the segment being built, as it was in the document
+	raw strings.Builder
+	// escape, if set, escapes translated text for the document
+	escape func(string) string
+}
+
+// keep adds markup kept as it is, ending the segment being built.
+func (b *builder) keep(raw string) {
+	b.flush()
+	b.keepRaw(raw)
+}
+
+func (b *builder) keepRaw(raw string) {
+	if raw == "" {
+		return
+	}
+	if n := len(b.parts); n > 0 && b.parts[n-1].seg == nil {
+		b.parts[n-1].raw += raw
+		return
+	}
+	b.parts = append(b.parts, part{raw: raw})
+}
+
+// addText adds text to the segment being built.
+func (b *builder) addText(text string) {
+	b.addEscapedText(text, text)
+}
+
+// addEscapedText adds text to the segment being built, which the document
+// has escaped as raw.
+func (b *builder) addEscapedText(text string, raw string) {
+	b.open = true
+	b.text.WriteString(text)
+	b.raw.WriteString(raw)
+}
+
+// addItem adds markup to the segment being built. Wrapping markup opens with
+// open and closes with a later call to closeItem.
+func (b *builder) addItem(open string, wraps bool) int {
+	b.open = true
+	b.items = append(b.items, item{open: open, wraps: wraps})
+	b.raw.WriteString(open)
+	n := len(b.items)
+
+	if wraps {
+		fmt.Fprintf(&b.text, "<g%d>", n)
+	} else {
+		fmt.Fprintf(&b.text, "<x%d/>", n)
+	}
+
+	return n
+}
+
+// closeItem closes the wrapping markup n with close.
+func (b *builder) closeItem(n int, close string) {
+	b.items[n-1].close = close
+	b.raw.WriteString(close)
+	fmt.Fprintf(&b.text, "</g%d>", n)
+}
+
+// flush ends the segment being built. Whitespace around it is kept out of the
+// segment and a segment without a letter to translate is kept as it is.
+func (b *builder) flush() {
+	if !b.open {
+		return
+	}
+
+	s := &segment{text: b.text.String(), items: b.items, escape: b.escape}
+	raw := b.raw.String()
+	b.text.Reset()
+	b.raw.Reset()
+	b.items = nil
+	b.open = false
+
+	if !hasLetter(itemTag.ReplaceAllString(s.text, "")) {
+		b.keepRaw(raw)
+		return
+	}
+
+	trimmed := strings.TrimLeftFunc(s.text, unicode.IsSpace)
+	b.keepRaw(s.text[:len(s.text)-len(trimmed)])
+
+	s.text = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+	trailing := trimmed[len(s.text):]
+
+	b.parts = append(b.parts, part{seg: s})
+	b.keepRaw(trailing)
+}
+
+func (b *builder) document() *Document {
+	b.flush()
+	return &Document{b.parts}
+}
+
+func hasLetter(s string) bool {
+	return strings.IndexFunc(s, unicode.IsLetter) >= 0
+}
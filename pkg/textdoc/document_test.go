@@ -0,0 +1,81 @@
+package textdoc
+
+import (
+	"doc-translate-go/pkg/wordcount"
+	"reflect"
+	"testing"
+)
+
+func TestSegment_Restore(t *testing.T) {
+	s := &segment{
+		text: "Run <x1/> from <g2>the site</g2>.",
+		items: []item{
+			{open: "`make`"},
+			{open: "[", close: "](https://example.com)", wraps: true},
+		},
+	}
+
+	tests := []struct {
+		translated string
+		want       string
+	}{
+		{"Chạy <x1/> từ <g2>trang web</g2>.", "Chạy `make` từ [trang web](https://example.com)."},
+		// Reordered
+		{"<g2>Trang web</g2>: chạy <x1/>.", "[Trang web](https://example.com): chạy `make`."},
+		// Lost, unknown and repeated tags
+		{"Chạy <x3/>từ trang web<x1/><x1/>.", "Chạy từ trang web`make`.[](https://example.com)"},
+	}
+
+	for _, tt := range tests {
+		if got := s.restore(tt.translated); got != tt.want {
+			t.Fatalf("%s: expected %s, got %s", tt.translated, tt.want, got)
+		}
+	}
+}
+
+func TestDocument_Assemble(t *testing.T) {
+	d, err := ParseText([]byte("Hello\nworld\n\n  Second paragraph  \n\n42\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Whitespace around paragraphs and text without letters stay out of segments
+	want := []string{"Hello\nworld", "Second paragraph"}
+	if got := d.Segments(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	b, err := d.Assemble([]string{"Xin chào\nthế giới", "Đoạn thứ hai"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := string(b), "Xin chào\nthế giới\n\n  Đoạn thứ hai  \n\n42\n"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	if _, err := d.Assemble(want[:1]); err != ErrSegmentCount {
+		t.Fatalf("expected %v, got %v", ErrSegmentCount, err)
+	}
+}
+
+func TestDocument_Count(t *testing.T) {
+	d, err := ParseMarkdown([]byte("Run `make build` from [the site](https://example.com).\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Run from the site .
+	want := wordcount.Count{Words: 4, Characters: 15, Tokens: 5}
+	if got := d.Count(); got != want {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestParse_NotText(t *testing.T) {
+	for _, parse := range []func([]byte) (*Document, error){ParseText, ParseMarkdown, ParseHTML} {
+		if _, err := parse([]byte{0xff, 0xfe, 'a'}); err != ErrNotText {
+			t.Fatalf("expected %v, got %v", ErrNotText, err)
+		}
+	}
+}
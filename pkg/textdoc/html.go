@@ -0,0 +1,147 @@
+package textdoc
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// htmlInline are the elements that sit inside a sentence, so they stay in
+// their paragraph's segment as tags rather than ending it.
+var htmlInline = map[string]bool{
+	"a": true, "abbr": true, "b": true, "bdi": true, "bdo": true, "cite": true,
+	"data": true, "del": true, "dfn": true, "em": true, "font": true, "i": true,
+	"ins": true, "kbd": true, "mark": true, "q": true, "s": true, "samp": true,
+	"small": true, "span": true, "strong": true, "sub": true, "sup": true,
+	"time": true, "u": true, "var": true,
+	"br": true, "img": true, "wbr": true,
+}
+
+// htmlVoid are the inline elements without content or an end tag.
+var htmlVoid = map[string]bool{
+	"br":  true,
+	"img": true,
+	"wbr": true,
+}
+
+// htmlVerbatim are the elements whose content is never translated.
+var htmlVerbatim = map[string]bool{
+	"code":     true,
+	"pre":      true,
+	"script":   true,
+	"style":    true,
+	"svg":      true,
+	"math":     true,
+	"textarea": true,
+	"template": true,
+}
+
+// htmlTextEscaper escapes translated text for where it's put back, between
+// tags. Quotes only need escaping in attributes, which are never translated.
+var htmlTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// openTag is an inline element standing as the segment's item n.
+type openTag struct {
+	name string
+	n    int
+}
+
+// ParseHTML splits HTML into the text of its block elements. Tags and
+// comments are kept out of the segments, inline elements stand as numbered
+// tags in them, and code, scripts and styles are kept as they are. Segments
+// hold text with its character references resolved, and translations are
+// escaped as they're put back.
+func ParseHTML(b []byte) (*Document, error) {
+	if !utf8.Valid(b) {
+		return nil, ErrNotText
+	}
+
+	d := builder{escape: htmlTextEscaper.Replace}
+	z := html.NewTokenizer(strings.NewReader(string(b)))
+
+	// Inline elements open in the segment being built
+	var open []openTag
+	// The verbatim element being skipped, with how deep it's nested in itself
+	verbatim := ""
+	depth := 0
+	var raw strings.Builder
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if z.Err() != io.EOF {
+				return nil, z.Err()
+			}
+			break
+		}
+
+		token := string(z.Raw())
+		name, _ := z.TagName()
+		tag := string(name)
+
+		if verbatim != "" {
+			raw.WriteString(token)
+			switch {
+			case tt == html.StartTagToken && tag == verbatim:
+				depth++
+			case tt == html.EndTagToken && tag == verbatim:
+				depth--
+			}
+			if depth > 0 {
+				continue
+			}
+
+			// Code inside a sentence stays in it, other verbatim elements end it
+			if verbatim == "code" && d.open {
+				d.addItem(raw.String(), false)
+			} else {
+				d.keep(raw.String())
+			}
+			verbatim = ""
+			raw.Reset()
+			continue
+		}
+
+		switch tt {
+		case html.TextToken:
+			d.addEscapedText(html.UnescapeString(token), token)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			switch {
+			case htmlVerbatim[tag] && tt == html.StartTagToken:
+				verbatim, depth = tag, 1
+				raw.WriteString(token)
+			case htmlInline[tag] && tt == html.StartTagToken && !htmlVoid[tag]:
+				open = append(open, openTag{tag, d.addItem(token, true)})
+			case htmlInline[tag]:
+				d.addItem(token, false)
+			default:
+				open = nil
+				d.keep(token)
+			}
+		case html.EndTagToken:
+			if n := len(open); htmlInline[tag] && n > 0 && open[n-1].name == tag {
+				d.closeItem(open[n-1].n, token)
+				open = open[:n-1]
+				continue
+			}
+			if htmlInline[tag] {
+				d.addItem(token, false)
+				continue
+			}
+			open = nil
+			d.keep(token)
+		default:
+			// Comments, doctypes
+			open = nil
+			d.keep(token)
+		}
+	}
+
+	if raw.Len() > 0 {
+		d.keep(raw.String())
+	}
+
+	return d.document(), nil
+}
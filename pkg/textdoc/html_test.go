@@ -0,0 +1,110 @@
+package textdoc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleHTML = `<!DOCTYPE html>
+<html>
+<head><title>Docs</title><style>p { color: red; }</style></head>
+<body>
+<h1>Hello <em>world</em></h1>
+<!-- not translated -->
+<p>Run <code>make <b>build</b></code> now.<br>Then <a href="/next">click here</a> &amp; wait.</p>
+<pre>keep
+  me</pre>
+<ul><li>One</li><li>42</li></ul>
+<script>document.title = "Docs";</script>
+</body>
+</html>
+`
+
+func TestParseHTML(t *testing.T) {
+	d, err := ParseHTML([]byte(sampleHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"Docs",
+		"Hello <g1>world</g1>",
+		"Run <x1/> now.<x2/>Then <g3>click here</g3> & wait.",
+		"One",
+	}
+	if got := d.Segments(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	b, err := d.Assemble(d.Segments())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != sampleHTML {
+		t.Fatalf("expected %q, got %q", sampleHTML, b)
+	}
+}
+
+func TestParseHTML_Assemble(t *testing.T) {
+	d, err := ParseHTML([]byte(sampleHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := d.Assemble([]string{
+		"Tài liệu",
+		"Xin chào <g1>thế giới</g1>",
+		"Chạy <x1/> ngay.<x2/>Sau đó <g3>bấm vào đây</g3> & chờ.",
+		"Một",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{
+		"<title>Tài liệu</title><style>p { color: red; }</style>",
+		"<h1>Xin chào <em>thế giới</em></h1>",
+		`<p>Chạy <code>make <b>build</b></code> ngay.<br>Sau đó <a href="/next">bấm vào đây</a> &amp; chờ.</p>`,
+		"<pre>keep\n  me</pre>",
+		"<li>Một</li><li>42</li>",
+		`<script>document.title = "Docs";</script>`,
+	} {
+		if !strings.Contains(string(b), s) {
+			t.Fatalf("expected %q in %q", s, b)
+		}
+	}
+}
+
+func TestParseHTML_Entities(t *testing.T) {
+	content := `<p>Use &lt;div&gt; &amp; <b>caf&eacute;</b>&nbsp;&#39;s tags.</p><p>&copy; &#169;</p>`
+
+	d, err := ParseHTML([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The translator sees the text, not how it's escaped
+	want := []string{"Use <div> & <g1>café</g1>\u00a0's tags."}
+	if got := d.Segments(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	// Text without a letter is kept as it was written
+	b, err := d.Assemble(d.Segments())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "<p>Use &lt;div&gt; &amp; <b>café</b>\u00a0's tags.</p><p>&copy; &#169;</p>"; string(b) != want {
+		t.Fatalf("expected %q, got %q", want, b)
+	}
+
+	// Translations are escaped, their tags are not
+	b, err = d.Assemble([]string{"Dùng <div> & <g1>cà phê</g1> <3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "<p>Dùng &lt;div&gt; &amp; <b>cà phê</b> &lt;3</p><p>&copy; &#169;</p>"; string(b) != want {
+		t.Fatalf("expected %q, got %q", want, b)
+	}
+}
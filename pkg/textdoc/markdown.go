@@ -0,0 +1,138 @@
+package textdoc
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	mdFence        = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+	mdBreak        = regexp.MustCompile(`^ {0,3}([-*_])( *[-*_]){2,}\s*$`)
+	mdHeading      = regexp.MustCompile(`^ {0,3}#{1,6}(\s+|$)`)
+	mdPrefix       = regexp.MustCompile(`^ {0,3}((>\s?)*\s*([-*+]|\d{1,9}[.)])\s+(\[[ xX]\]\s+)?|(>\s?)+)`)
+	mdListItem     = regexp.MustCompile(`^ {0,3}(>\s?)*\s*([-*+]|\d{1,9}[.)])\s`)
+	mdIndented     = regexp.MustCompile(`^( {4}|\t)`)
+	mdTableRow     = regexp.MustCompile(`^\s*\|`)
+	mdTableDivider = regexp.MustCompile(`^\s*\|?(\s*:?-+:?\s*\|)+\s*(:?-+:?\s*)?$`)
+	mdHtmlLine     = regexp.MustCompile(`^\s*<[A-Za-z!/?].*>\s*$`)
+	mdReference    = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:\s*\S`)
+
+	// Inline markup: code spans, links and images, autolinks and HTML tags
+	mdInline = regexp.MustCompile("``[^\\n]*?``|`[^`\\n]*`" +
+		`|(!?\[)([^\]\n]*)(\]\([^)\n]*\)|\]\[[^\]\n]*\])` +
+		`|<[A-Za-z][A-Za-z0-9+.-]*:[^<>\s]*>` +
+		`|</?[A-Za-z][A-Za-z0-9-]*(\s[^<>]*)?/?>`)
+)
+
+// ParseMarkdown splits Markdown into headings, paragraphs, list items, quotes
+// and table cells. Code blocks, front matter, HTML blocks, link destinations,
+// code spans and HTML tags are kept out of the segments.
+func ParseMarkdown(b []byte) (*Document, error) {
+	if !utf8.Valid(b) {
+		return nil, ErrNotText
+	}
+
+	var d builder
+	fence := ""
+	frontMatter := false
+	afterBlank := true
+	inList := false
+
+	for i, line := range lines(string(b)) {
+		content := strings.TrimRight(line, "\r\n")
+		eol := line[len(content):]
+
+		switch {
+		case fence != "":
+			d.keep(line)
+			if strings.HasPrefix(strings.TrimSpace(content), fence) {
+				fence = ""
+			}
+			continue
+		case i == 0 && content == "---":
+			frontMatter = true
+			d.keep(line)
+			continue
+		case frontMatter:
+			d.keep(line)
+			if content == "---" || content == "..." {
+				frontMatter = false
+			}
+			continue
+		}
+
+		blank := strings.TrimSpace(content) == ""
+
+		// Indented lines in a list belong to its items, anything else after a
+		// blank line ends the list
+		if mdListItem.MatchString(content) {
+			inList = true
+		} else if afterBlank && !blank && !mdIndented.MatchString(content) {
+			inList = false
+		}
+
+		switch {
+		case blank:
+			d.keep(line)
+		case mdFence.MatchString(content):
+			m := mdFence.FindStringSubmatch(content)
+			fence = m[1]
+			d.keep(line)
+		case afterBlank && !inList && mdIndented.MatchString(content):
+			d.keep(line)
+		case mdBreak.MatchString(content), mdHtmlLine.MatchString(content), mdReference.MatchString(content), mdTableDivider.MatchString(content):
+			d.keep(line)
+		case mdTableRow.MatchString(content):
+			d.tableRow(content)
+			d.keep(eol)
+		case mdHeading.MatchString(content):
+			prefix := mdHeading.FindString(content)
+			d.keep(prefix)
+			d.inline(content[len(prefix):])
+			d.keep(eol)
+		case mdPrefix.MatchString(content):
+			prefix := mdPrefix.FindString(content)
+			d.keep(prefix)
+			d.inline(content[len(prefix):] + eol)
+		default:
+			d.inline(content + eol)
+		}
+
+		afterBlank = blank
+	}
+
+	return d.document(), nil
+}
+
+// tableRow adds every cell of a table row as a segment of its own.
+func (d *builder) tableRow(row string) {
+	cells := strings.Split(row, "|")
+	for i, cell := range cells {
+		if i > 0 {
+			d.keep("|")
+		}
+		d.inline(cell)
+		d.flush()
+	}
+}
+
+// inline adds text to the segment being built, keeping inline markup out of it.
+func (d *builder) inline(text string) {
+	prev := 0
+	for _, m := range mdInline.FindAllStringSubmatchIndex(text, -1) {
+		d.addText(text[prev:m[0]])
+		prev = m[1]
+
+		// A link or an image, whose text is translated
+		if m[2] >= 0 {
+			n := d.addItem(text[m[2]:m[3]], true)
+			d.inline(text[m[4]:m[5]])
+			d.closeItem(n, text[m[6]:m[7]])
+			continue
+		}
+
+		d.addItem(text[m[0]:m[1]], false)
+	}
+	d.addText(text[prev:])
+}
@@ -0,0 +1,84 @@
+package textdoc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleMarkdown = "---\ntitle: Getting started\n---\n" +
+	"# Getting started\n\n" +
+	"Install the `cli` tool from [our site](https://example.com) and\nrun it.\n\n" +
+	"```sh\nmake build\n```\n\n" +
+	"- First item\n- [x] Done item\n\n    still the done item\n\n" +
+	"> Quoted **text**\n\n" +
+	"| Name | Value |\n|------|-------|\n| Size | 42 |\n\n" +
+	"    indented code\n\n" +
+	"<div align=\"center\">\n\n" +
+	"See <b>this</b> ![the logo](logo.png).\n\n" +
+	"---\n\n" +
+	"[ref]: https://example.com\n"
+
+func TestParseMarkdown(t *testing.T) {
+	d, err := ParseMarkdown([]byte(sampleMarkdown))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"Getting started",
+		"Install the <x1/> tool from <g2>our site</g2> and\nrun it.",
+		"First item",
+		"Done item",
+		"still the done item",
+		"Quoted **text**",
+		"Name",
+		"Value",
+		"Size",
+		"See <x1/>this<x2/> <g3>the logo</g3>.",
+	}
+	if got := d.Segments(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	// Assembling the segments untranslated gives the document back
+	b, err := d.Assemble(d.Segments())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != sampleMarkdown {
+		t.Fatalf("expected %q, got %q", sampleMarkdown, b)
+	}
+}
+
+func TestParseMarkdown_Assemble(t *testing.T) {
+	d, err := ParseMarkdown([]byte(sampleMarkdown))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	segments := d.Segments()
+	for i := range segments {
+		segments[i] = strings.ToUpper(segments[i])
+	}
+
+	b, err := d.Assemble(segments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{
+		"title: Getting started\n",
+		"# GETTING STARTED\n",
+		"INSTALL THE `cli` TOOL FROM [OUR SITE](https://example.com) AND\nRUN IT.",
+		"```sh\nmake build\n```",
+		"- [x] DONE ITEM\n",
+		"| SIZE | 42 |",
+		"    indented code\n",
+		"SEE <b>THIS</b> ![THE LOGO](logo.png).",
+	} {
+		if !strings.Contains(string(b), s) {
+			t.Fatalf("expected %q in %q", s, b)
+		}
+	}
+}
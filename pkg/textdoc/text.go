@@ -0,0 +1,33 @@
+package textdoc
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrNotText = errors.New("not utf-8 text")
+
+// ParseText splits plain text into paragraphs, separated by blank lines.
+// Line breaks inside a paragraph are part of its segment.
+func ParseText(b []byte) (*Document, error) {
+	if !utf8.Valid(b) {
+		return nil, ErrNotText
+	}
+
+	var d builder
+	for _, line := range lines(string(b)) {
+		if strings.TrimSpace(line) == "" {
+			d.keep(line)
+			continue
+		}
+		d.addText(line)
+	}
+
+	return d.document(), nil
+}
+
+// lines splits s after every line break, keeping them.
+func lines(s string) []string {
+	return strings.SplitAfter(s, "\n")
+}
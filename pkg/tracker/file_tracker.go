@@ -1,6 +1,32 @@
 package tracker
 
-import "time"
+import (
+	"context"
+	"time"
+)
+
+// Stage is the step of the translate pipeline a file is at.
+type Stage string
+
+const (
+	StageQueued      Stage = "queued"
+	StageDownloading Stage = "downloading"
+	StageTranslating Stage = "translating"
+	StageUploading   Stage = "uploading"
+	StageDone        Stage = "done"
+	StageFailed      Stage = "failed"
+)
+
+const (
+	// StatusDone is published once a file is translated, right before its status is deleted
+	StatusDone = "done"
+	// StatusRemoved is published when a status is deleted
+	StatusRemoved = "removed"
+	// StatusInterrupted is set on files whose translation was cut short, by a
+	// shutdown or a worker dying. It expires like other failures, or is replaced
+	// once the task is redelivered.
+	StatusInterrupted = "fail:interrupted"
+)
 
 type FileTrackerInput struct {
 	Status     string    `json:"status"`
@@ -12,15 +38,27 @@ type FileTrackerInput struct {
 type FileStatus struct {
 	Key        string    `json:"key"`
 	Status     string    `json:"status"`
+	Stage      Stage     `json:"stage"`
+	Progress   int       `json:"progress"`
 	SourceLang string    `json:"source_lang"`
 	TargetLang string    `json:"target_lang"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
 
 type FileTracker interface {
-	Get(key string) (*FileStatus, error)
-	Create(status *FileStatus) error
-	Delete(key string) error
-	List(pat string) ([]*FileStatus, error)
-	Clear() error
+	Get(ctx context.Context, key string) (*FileStatus, error)
+	Create(ctx context.Context, status *FileStatus) error
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context, pat string) ([]*FileStatus, error)
+	Clear(ctx context.Context) error
+	// Subscribe streams every status created or deleted under a key matching
+	// pat until ctx is done, after which the channel is closed.
+	Subscribe(ctx context.Context, pat string) (<-chan *FileStatus, error)
+}
+
+// Locker is implemented by file trackers shared by several processes, so that
+// only one of them does what needs doing once, such as sweeping stale statuses.
+type Locker interface {
+	// TryLock takes the lock called name for ttl, unless it's already taken.
+	TryLock(ctx context.Context, name string, ttl time.Duration) (bool, error)
 }
@@ -0,0 +1,214 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+var ErrNotFound = errors.New("file status not found")
+
+type memoryEntry struct {
+	status    FileStatus
+	expiresAt time.Time
+}
+
+type memorySubscriber struct {
+	pat      string
+	statuses chan *FileStatus
+}
+
+// MemoryFileTracker keeps file statuses in process memory, for running the
+// service without redis. Statuses are lost on restart.
+type MemoryFileTracker struct {
+	mu          sync.RWMutex
+	entries     map[string]memoryEntry
+	subscribers map[*memorySubscriber]struct{}
+	expiration  int
+	now         func() time.Time
+}
+
+func NewMemoryFileTracker(invalidationTime int) *MemoryFileTracker {
+	return &MemoryFileTracker{
+		entries:     make(map[string]memoryEntry),
+		subscribers: make(map[*memorySubscriber]struct{}),
+		expiration:  invalidationTime,
+		now:         time.Now,
+	}
+}
+
+func (t *MemoryFileTracker) Create(ctx context.Context, status *FileStatus) error {
+	status.UpdatedAt = t.now()
+
+	entry := memoryEntry{status: *status}
+
+	// Same as redis, failures expire so they don't linger in the progress list
+	if strings.HasPrefix(status.Status, "fail:") && t.expiration > 0 {
+		entry.expiresAt = status.UpdatedAt.Add(time.Duration(t.expiration) * time.Second)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.entries[status.Key] = entry
+	t.publish(entry.status)
+
+	return nil
+}
+
+func (t *MemoryFileTracker) Delete(ctx context.Context, key string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+
+	delete(t.entries, key)
+
+	entry.status.Status = StatusRemoved
+	entry.status.UpdatedAt = t.now()
+	t.publish(entry.status)
+
+	return nil
+}
+
+func (t *MemoryFileTracker) Subscribe(ctx context.Context, pat string) (<-chan *FileStatus, error) {
+	sub := &memorySubscriber{pat, make(chan *FileStatus, 64)}
+
+	t.mu.Lock()
+	t.subscribers[sub] = struct{}{}
+	t.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		delete(t.subscribers, sub)
+		close(sub.statuses)
+	}()
+
+	return sub.statuses, nil
+}
+
+// publish must be called with mu held. Like redis pub/sub, a subscriber that
+// isn't keeping up misses statuses rather than blocking the tracker.
+func (t *MemoryFileTracker) publish(status FileStatus) {
+	for sub := range t.subscribers {
+		if !globMatch(sub.pat, status.Key) {
+			continue
+		}
+
+		s := status
+		select {
+		case sub.statuses <- &s:
+		default:
+		}
+	}
+}
+
+func (t *MemoryFileTracker) Clear(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.entries = make(map[string]memoryEntry)
+
+	return nil
+}
+
+func (t *MemoryFileTracker) Get(ctx context.Context, key string) (*FileStatus, error) {
+	t.mu.RLock()
+	entry, ok := t.entries[key]
+	t.mu.RUnlock()
+
+	if !ok || t.expired(entry) {
+		return nil, ErrNotFound
+	}
+
+	status := entry.status
+	return &status, nil
+}
+
+// List returns the statuses whose key matches the redis style glob pat.
+// Expired statuses are removed along the way.
+func (t *MemoryFileTracker) List(ctx context.Context, pat string) ([]*FileStatus, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var result []*FileStatus
+
+	for key, entry := range t.entries {
+		if t.expired(entry) {
+			delete(t.entries, key)
+			continue
+		}
+
+		if !globMatch(pat, key) {
+			continue
+		}
+
+		status := entry.status
+		result = append(result, &status)
+	}
+
+	return result, nil
+}
+
+func (t *MemoryFileTracker) expired(entry memoryEntry) bool {
+	return !entry.expiresAt.IsZero() && !t.now().Before(entry.expiresAt)
+}
+
+// globMatch matches s against a redis KEYS/SCAN pattern. Unlike path.Match,
+// * and ? also match "/", which can appear in keys.
+func globMatch(pat string, s string) bool {
+	for len(pat) > 0 {
+		switch pat[0] {
+		case '*':
+			for i := 0; i <= len(s); i++ {
+				if globMatch(pat[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if s == "" {
+				return false
+			}
+			_, n := utf8.DecodeRuneInString(s)
+			pat, s = pat[1:], s[n:]
+		case '[':
+			end := strings.IndexByte(pat[1:], ']')
+			if end < 0 || s == "" {
+				return false
+			}
+			r, n := utf8.DecodeRuneInString(s)
+			// A character class matches a single rune, which path.Match handles
+			if ok, err := path.Match(pat[:end+2], string(r)); err != nil || !ok {
+				return false
+			}
+			pat, s = pat[end+2:], s[n:]
+		case '\\':
+			if len(pat) > 1 {
+				pat = pat[1:]
+			}
+			fallthrough
+		default:
+			if s == "" || s[0] != pat[0] {
+				return false
+			}
+			pat, s = pat[1:], s[1:]
+		}
+	}
+
+	return s == ""
+}
+
+// Ensure implementation
+var _ FileTracker = (*MemoryFileTracker)(nil)
@@ -0,0 +1,151 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryFileTracker_CreateGet(t *testing.T) {
+	tracker := NewMemoryFileTracker(0)
+
+	if err := tracker.Create(context.Background(), &FileStatus{Key: "isid_vi_file.docx", Status: "in progress"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := tracker.Get(context.Background(), "isid_vi_file.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Status != "in progress" || got.UpdatedAt.IsZero() {
+		t.Fatalf("unexpected status %v", got)
+	}
+
+	if err := tracker.Delete(context.Background(), "isid_vi_file.docx"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tracker.Get(context.Background(), "isid_vi_file.docx"); err != ErrNotFound {
+		t.Fatalf("expected %v, got %v", ErrNotFound, err)
+	}
+}
+
+func TestMemoryFileTracker_FailExpires(t *testing.T) {
+	now := time.Now()
+
+	tracker := NewMemoryFileTracker(20)
+	tracker.now = func() time.Time { return now }
+
+	tracker.Create(context.Background(), &FileStatus{Key: "failed", Status: "fail:translate"})
+	tracker.Create(context.Background(), &FileStatus{Key: "running", Status: "in progress"})
+
+	now = now.Add(20 * time.Second)
+
+	if _, err := tracker.Get(context.Background(), "failed"); err != ErrNotFound {
+		t.Fatalf("expected %v, got %v", ErrNotFound, err)
+	}
+
+	got, err := tracker.List(context.Background(), "*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 1 || got[0].Key != "running" {
+		t.Fatalf("expected only running, got %v", got)
+	}
+}
+
+func TestMemoryFileTracker_List(t *testing.T) {
+	tracker := NewMemoryFileTracker(0)
+
+	for _, key := range []string{"isid_vi_a.docx", "isid_en_b/c.docx", "other_vi_a.docx"} {
+		tracker.Create(context.Background(), &FileStatus{Key: key, Status: "in progress"})
+	}
+
+	tests := []struct {
+		pat  string
+		want []string
+	}{
+		{"isid_*", []string{"isid_en_b/c.docx", "isid_vi_a.docx"}},
+		{"*_vi_*", []string{"isid_vi_a.docx", "other_vi_a.docx"}},
+		{"isid_[ev]?_*", []string{"isid_en_b/c.docx", "isid_vi_a.docx"}},
+		{"isid_[^e]*", []string{"isid_vi_a.docx"}},
+		{"isid\\_vi_a.docx", []string{"isid_vi_a.docx"}},
+		{"missing_*", nil},
+	}
+
+	for _, tt := range tests {
+		statuses, err := tracker.List(context.Background(), tt.pat)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for _, s := range statuses {
+			got = append(got, s.Key)
+		}
+		sort.Strings(got)
+
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Fatalf("%s: expected %v, got %v", tt.pat, tt.want, got)
+		}
+	}
+}
+
+func TestMemoryFileTracker_Concurrent(t *testing.T) {
+	tracker := NewMemoryFileTracker(1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			key := fmt.Sprintf("isid_vi_%d.docx", i)
+			for j := 0; j < 100; j++ {
+				tracker.Create(context.Background(), &FileStatus{Key: key, Status: "fail:translate"})
+				tracker.Get(context.Background(), key)
+				tracker.List(context.Background(), "isid_*")
+				tracker.Delete(context.Background(), key)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	got, _ := tracker.List(context.Background(), "*")
+	if len(got) != 0 {
+		t.Fatalf("expected no statuses, got %v", got)
+	}
+}
+
+func TestMemoryFileTracker_Subscribe(t *testing.T) {
+	tracker := NewMemoryFileTracker(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	statuses, err := tracker.Subscribe(ctx, "isid_*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracker.Create(context.Background(), &FileStatus{Key: "other_vi_a.docx", Status: "in progress"})
+	tracker.Create(context.Background(), &FileStatus{Key: "isid_vi_a.docx", Status: "in progress", TargetLang: "vi"})
+	tracker.Create(context.Background(), &FileStatus{Key: "isid_vi_a.docx", Status: StatusDone, TargetLang: "vi"})
+	tracker.Delete(context.Background(), "isid_vi_a.docx")
+
+	for _, want := range []string{"in progress", StatusDone, StatusRemoved} {
+		got := <-statuses
+		if got.Key != "isid_vi_a.docx" || got.Status != want || got.TargetLang != "vi" {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+
+	cancel()
+
+	if _, ok := <-statuses; ok {
+		t.Fatal("expected channel to be closed")
+	}
+}
@@ -9,6 +9,16 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// channelPrefix namespaces the pub/sub channels status changes are published on
+const channelPrefix = "file-tracker:"
+
+// lockPrefix namespaces the keys of locks
+const lockPrefix = "file-tracker-lock:"
+
+// keyPrefix namespaces the keys of statuses, so listing them doesn't go
+// through whatever else the cluster holds
+const keyPrefix = "file-status:"
+
 type RedisFileTracker struct {
 	r          *redis.ClusterClient
 	expiration int
@@ -18,7 +28,7 @@ func NewRedisFileTracker(r *redis.ClusterClient, invalidationTime int) *RedisFil
 	return &RedisFileTracker{r, invalidationTime}
 }
 
-func (t *RedisFileTracker) Create(status *FileStatus) error {
+func (t *RedisFileTracker) Create(ctx context.Context, status *FileStatus) error {
 	duration := time.Duration(0)
 
 	if strings.HasPrefix(status.Status, "fail:") {
@@ -32,23 +42,106 @@ func (t *RedisFileTracker) Create(status *FileStatus) error {
 		return err
 	}
 
-	if err := t.r.Set(context.Background(), status.Key, progressJson, duration).Err(); err != nil {
+	if err := t.r.Set(ctx, keyPrefix+status.Key, progressJson, duration).Err(); err != nil {
 		return err
 	}
 
-	return nil
+	return t.r.Publish(ctx, channelPrefix+status.Key, progressJson).Err()
 }
 
-func (t *RedisFileTracker) Delete(key string) error {
-	return t.r.Del(context.Background(), key).Err()
+func (t *RedisFileTracker) Delete(ctx context.Context, key string) error {
+	v, err := t.r.GetDel(ctx, keyPrefix+key).Result()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Keep the languages so subscribers can tell which file was removed
+	var status FileStatus
+	if err := json.Unmarshal([]byte(v), &status); err != nil {
+		return err
+	}
+
+	status.Status = StatusRemoved
+	status.UpdatedAt = time.Now()
+
+	removedJson, err := json.Marshal(&status)
+	if err != nil {
+		return err
+	}
+
+	return t.r.Publish(ctx, channelPrefix+key, removedJson).Err()
+}
+
+func (t *RedisFileTracker) Subscribe(ctx context.Context, pat string) (<-chan *FileStatus, error) {
+	pubsub := t.r.PSubscribe(ctx, channelPrefix+pat)
+
+	// Wait for the subscription to be confirmed so no status published after we return is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	statuses := make(chan *FileStatus)
+
+	go func() {
+		defer close(statuses)
+		defer pubsub.Close()
+
+		msgs := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+
+				var status FileStatus
+				if err := json.Unmarshal([]byte(msg.Payload), &status); err != nil {
+					continue
+				}
+
+				select {
+				case statuses <- &status:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return statuses, nil
 }
 
-func (t *RedisFileTracker) Clear() error {
-	return t.r.FlushAll(context.Background()).Err()
+// Clear deletes every status, leaving the rest of the cluster alone.
+func (t *RedisFileTracker) Clear(ctx context.Context) error {
+	var cursor uint64
+
+	for {
+		keys, next, err := t.r.Scan(ctx, cursor, keyPrefix+"*", 10).Result()
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			if err := t.r.Del(ctx, key).Err(); err != nil {
+				return err
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			return nil
+		}
+	}
 }
 
-func (t *RedisFileTracker) Get(key string) (*FileStatus, error) {
-	v, err := t.r.Get(context.Background(), key).Result()
+func (t *RedisFileTracker) Get(ctx context.Context, key string) (*FileStatus, error) {
+	v, err := t.r.Get(ctx, keyPrefix+key).Result()
 	if err != nil {
 		return nil, err
 	}
@@ -62,27 +155,26 @@ func (t *RedisFileTracker) Get(key string) (*FileStatus, error) {
 	return &fileProgress, nil
 }
 
-func (t *RedisFileTracker) List(pat string) ([]*FileStatus, error) {
+func (t *RedisFileTracker) List(ctx context.Context, pat string) ([]*FileStatus, error) {
 	var cursor uint64
 	var n int
 	var result []*FileStatus
 
-	ctx := context.Background()
-
 	for {
-		keys, cursor, err := t.r.Scan(ctx, cursor, pat, 10).Result()
+		keys, next, err := t.r.Scan(ctx, cursor, keyPrefix+pat, 10).Result()
 		if err != nil {
 			return nil, err
 		}
 
 		for _, key := range keys {
-			p, err := t.Get(key)
+			p, err := t.Get(ctx, strings.TrimPrefix(key, keyPrefix))
 			if err != nil {
 				continue
 			}
 			result = append(result, p)
 		}
 
+		cursor = next
 		if cursor == 0 {
 			break
 		}
@@ -93,5 +185,10 @@ func (t *RedisFileTracker) List(pat string) ([]*FileStatus, error) {
 	return result, nil
 }
 
+func (t *RedisFileTracker) TryLock(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	return t.r.SetNX(ctx, lockPrefix+name, 1, ttl).Result()
+}
+
 // Ensure implementation
 var _ FileTracker = (*RedisFileTracker)(nil)
+var _ Locker = (*RedisFileTracker)(nil)
@@ -1,6 +1,7 @@
 package tracker
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -20,6 +21,10 @@ func TestRedisFileTracker_Create(t *testing.T) {
 
 	mock.
 		CustomMatch(func(expected, actual []interface{}) error {
+			if expected[1] != actual[1] {
+				return fmt.Errorf("expected %v, got %v", expected[1], actual[1])
+			}
+
 			var want, got FileStatus
 
 			if err := json.Unmarshal([]byte(expected[2].(string)), &want); err != nil {
@@ -37,12 +42,32 @@ func TestRedisFileTracker_Create(t *testing.T) {
 
 			return nil
 		}).
-		ExpectSet(key, string(val), time.Duration(0)).
+		ExpectSet(keyPrefix+key, string(val), time.Duration(0)).
 		SetVal(string(val))
 
+	mock.
+		CustomMatch(func(expected, actual []interface{}) error {
+			if expected[1] != actual[1] {
+				return fmt.Errorf("expected %v, got %v", expected[1], actual[1])
+			}
+
+			var got FileStatus
+			if err := json.Unmarshal(actual[2].([]byte), &got); err != nil {
+				return err
+			}
+
+			if got.Key != key || got.Status != status.Status {
+				return fmt.Errorf("expected %v, got %v", status, got)
+			}
+
+			return nil
+		}).
+		ExpectPublish(channelPrefix+key, string(val)).
+		SetVal(1)
+
 	tracker := NewRedisFileTracker(client, 0)
 
-	err := tracker.Create(status)
+	err := tracker.Create(context.Background(), status)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -79,11 +104,11 @@ func TestRedisFileTracker_Create_Err(t *testing.T) {
 
 			return nil
 		}).
-		ExpectSet(key, string(val), time.Duration(0)).SetErr(e)
+		ExpectSet(keyPrefix+key, string(val), time.Duration(0)).SetErr(e)
 
 	tracker := NewRedisFileTracker(client, 0)
 
-	err := tracker.Create(status)
+	err := tracker.Create(context.Background(), status)
 	if err != e {
 		t.Fatalf("expected %v, got %v", e, err)
 	}
@@ -100,11 +125,11 @@ func TestRedisFileTracker_Get(t *testing.T) {
 	want := &FileStatus{Key: key, Status: "status"}
 	val, _ := json.Marshal(want)
 
-	mock.ExpectGet(key).SetVal(string(val))
+	mock.ExpectGet(keyPrefix + key).SetVal(string(val))
 
 	tracker := NewRedisFileTracker(client, 0)
 
-	got, err := tracker.Get(key)
+	got, err := tracker.Get(context.Background(), key)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -117,3 +142,104 @@ func TestRedisFileTracker_Get(t *testing.T) {
 		t.Fatal(t)
 	}
 }
+
+func TestRedisFileTracker_List(t *testing.T) {
+	client, mock := redismock.NewClusterMock()
+
+	key := "isid_vi_file.docx"
+	want := []*FileStatus{{Key: key, Status: "in progress"}}
+	val, _ := json.Marshal(want[0])
+
+	// Only the keys of statuses are scanned
+	mock.ExpectScan(0, keyPrefix+"isid_*", 10).SetVal([]string{keyPrefix + key}, 0)
+	mock.ExpectGet(keyPrefix + key).SetVal(string(val))
+
+	tracker := NewRedisFileTracker(client, 0)
+
+	got, err := tracker.List(context.Background(), "isid_*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedisFileTracker_Delete(t *testing.T) {
+	client, mock := redismock.NewClusterMock()
+
+	key := "isid_vi_file.docx"
+	val, _ := json.Marshal(&FileStatus{Key: key, Status: "in progress", SourceLang: "en", TargetLang: "vi"})
+
+	mock.ExpectGetDel(keyPrefix + key).SetVal(string(val))
+	mock.
+		CustomMatch(func(expected, actual []interface{}) error {
+			var got FileStatus
+			if err := json.Unmarshal(actual[2].([]byte), &got); err != nil {
+				return err
+			}
+
+			if got.Key != key || got.Status != StatusRemoved || got.TargetLang != "vi" {
+				return fmt.Errorf("unexpected removed status %v", got)
+			}
+
+			return nil
+		}).
+		ExpectPublish(channelPrefix+key, "").
+		SetVal(1)
+
+	tracker := NewRedisFileTracker(client, 0)
+
+	if err := tracker.Delete(context.Background(), key); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedisFileTracker_Delete_Missing(t *testing.T) {
+	client, mock := redismock.NewClusterMock()
+
+	mock.ExpectGetDel(keyPrefix + "missing").RedisNil()
+
+	tracker := NewRedisFileTracker(client, 0)
+
+	if err := tracker.Delete(context.Background(), "missing"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedisFileTracker_TryLock(t *testing.T) {
+	client, mock := redismock.NewClusterMock()
+
+	mock.ExpectSetNX(lockPrefix+"sweep", 1, time.Minute).SetVal(true)
+	mock.ExpectSetNX(lockPrefix+"sweep", 1, time.Minute).SetVal(false)
+
+	tracker := NewRedisFileTracker(client, 0)
+
+	// Only the first to try takes the lock
+	for _, want := range []bool{true, false} {
+		locked, err := tracker.TryLock(context.Background(), "sweep", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if locked != want {
+			t.Fatalf("expected %v, got %v", want, locked)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,126 @@
+package translator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Cache stores translated documents. Implementations decide how long entries
+// live and how much is kept, a miss is never an error.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, b []byte) error
+}
+
+// cacheCall is a translation in flight that identical requests wait on.
+type cacheCall struct {
+	done chan struct{}
+	b    []byte
+	err  error
+}
+
+// CachingTranslator decorates a translator with a cache keyed by the document's
+// hash, languages, glossary and translation memory matches. Concurrent identical requests share a single translation.
+type CachingTranslator struct {
+	translator  Translator
+	cache       Cache
+	maxItemSize int
+
+	mu    sync.Mutex
+	calls map[string]*cacheCall
+}
+
+// NewCachingTranslator returns a translator caching results of translator in cache.
+// Translations larger than maxItemSize bytes are not cached.
+func NewCachingTranslator(translator Translator, cache Cache, maxItemSize int) *CachingTranslator {
+	return &CachingTranslator{
+		translator:  translator,
+		cache:       cache,
+		maxItemSize: maxItemSize,
+		calls:       make(map[string]*cacheCall),
+	}
+}
+
+func (t *CachingTranslator) Translate(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+	return t.TranslateWithProgress(ctx, b, mimeType, sourceLang, targetLang, nil)
+}
+
+// TranslateWithProgress reports progress only to the request that actually
+// translates, requests waiting on it or served from the cache get none.
+func (t *CachingTranslator) TranslateWithProgress(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string, onProgress ProgressFunc) ([]byte, error) {
+	key := cacheKey(b, mimeType, sourceLang, targetLang, Glossary(ctx), Memory(ctx))
+
+	// The cache is best effort, a failing backend must not fail translations
+	if cached, ok, err := t.cache.Get(ctx, key); err == nil && ok {
+		return cached, nil
+	}
+
+	t.mu.Lock()
+	if c, ok := t.calls[key]; ok {
+		t.mu.Unlock()
+
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		// The request translating was cancelled, not this one, so try again
+		if ctx.Err() == nil && (errors.Is(c.err, context.Canceled) || status.Code(c.err) == codes.Canceled) {
+			return t.TranslateWithProgress(ctx, b, mimeType, sourceLang, targetLang, onProgress)
+		}
+
+		return c.b, c.err
+	}
+
+	c := &cacheCall{done: make(chan struct{})}
+	t.calls[key] = c
+	t.mu.Unlock()
+
+	c.b, c.err = t.translate(ctx, b, mimeType, sourceLang, targetLang, onProgress)
+	if c.err == nil && len(c.b) <= t.maxItemSize {
+		t.cache.Set(ctx, key, c.b)
+	}
+
+	t.mu.Lock()
+	delete(t.calls, key)
+	t.mu.Unlock()
+	close(c.done)
+
+	return c.b, c.err
+}
+
+func (t *CachingTranslator) translate(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string, onProgress ProgressFunc) ([]byte, error) {
+	if pt, ok := t.translator.(ProgressTranslator); ok && onProgress != nil {
+		return pt.TranslateWithProgress(ctx, b, mimeType, sourceLang, targetLang, onProgress)
+	}
+
+	return t.translator.Translate(ctx, b, mimeType, sourceLang, targetLang)
+}
+
+// cacheKey identifies a translation. Translations with a glossary or memory
+// matches are told apart by their hash, those without keep the keys they had
+// before either.
+func cacheKey(b []byte, mimeType string, sourceLang string, targetLang string, glossary []Term, memory []MemoryMatch) string {
+	sum := sha256.Sum256(b)
+	key := fmt.Sprintf("translation:%s:%s:%s:%s", mimeType, sourceLang, targetLang, hex.EncodeToString(sum[:]))
+
+	if h := glossaryHash(glossary); h != "" {
+		key += ":" + h
+	}
+	if h := memoryHash(memory); h != "" {
+		key += ":m" + h
+	}
+
+	return key
+}
+
+// Ensure implementation
+var _ ProgressTranslator = (*CachingTranslator)(nil)
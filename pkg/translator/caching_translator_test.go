@@ -0,0 +1,182 @@
+package translator
+
+import (
+	"context"
+	"doc-translate-go/pkg/format"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingTranslator reverses documents, counting calls and optionally
+// blocking until release is closed.
+type countingTranslator struct {
+	calls   atomic.Int32
+	release chan struct{}
+	err     error
+}
+
+func (t *countingTranslator) Translate(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+	t.calls.Add(1)
+	if t.release != nil {
+		select {
+		case <-t.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if t.err != nil {
+		return nil, t.err
+	}
+	return reverse(b), nil
+}
+
+func TestCachingTranslator(t *testing.T) {
+	inner := &countingTranslator{}
+	translatr := NewCachingTranslator(inner, NewLRUCache(1<<10, 0), 1<<10)
+
+	for i := 0; i < 3; i++ {
+		got, err := translatr.Translate(context.Background(), []byte("doc"), format.Docx, "en", "vi")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual([]byte("cod"), got) {
+			t.Fatalf("expected %s, got %s", "cod", got)
+		}
+	}
+
+	// A different target language is a different translation
+	if _, err := translatr.Translate(context.Background(), []byte("doc"), format.Docx, "en", "fr"); err != nil {
+		t.Fatal(err)
+	}
+
+	if inner.calls.Load() != 2 {
+		t.Fatalf("expected %v calls, got %v", 2, inner.calls.Load())
+	}
+
+	// So is one with a glossary
+	ctx := WithGlossary(context.Background(), []Term{{Source: "doc", Target: "tài liệu"}})
+	for i := 0; i < 2; i++ {
+		if _, err := translatr.Translate(ctx, []byte("doc"), format.Docx, "en", "vi"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if inner.calls.Load() != 3 {
+		t.Fatalf("expected %v calls, got %v", 3, inner.calls.Load())
+	}
+
+	// And so is one with translation memory matches
+	ctx = WithMemory(context.Background(), []MemoryMatch{{Source: "docs", Target: "tài liệu", Score: 0.8}})
+	if _, err := translatr.Translate(ctx, []byte("doc"), format.Docx, "en", "vi"); err != nil {
+		t.Fatal(err)
+	}
+
+	if inner.calls.Load() != 4 {
+		t.Fatalf("expected %v calls, got %v", 4, inner.calls.Load())
+	}
+}
+
+func TestCachingTranslator_SingleFlight(t *testing.T) {
+	inner := &countingTranslator{release: make(chan struct{})}
+	translatr := NewCachingTranslator(inner, NewLRUCache(1<<10, 0), 1<<10)
+
+	var wg sync.WaitGroup
+	results := make([][]byte, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = translatr.Translate(context.Background(), []byte("doc"), format.Docx, "en", "vi")
+		}(i)
+	}
+
+	// Let every request reach the translator or join the one in flight
+	for inner.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(inner.release)
+	wg.Wait()
+
+	if inner.calls.Load() != 1 {
+		t.Fatalf("expected %v call, got %v", 1, inner.calls.Load())
+	}
+
+	for _, got := range results {
+		if !reflect.DeepEqual([]byte("cod"), got) {
+			t.Fatalf("expected %s, got %s", "cod", got)
+		}
+	}
+}
+
+func TestCachingTranslator_MaxItemSize(t *testing.T) {
+	inner := &countingTranslator{}
+	translatr := NewCachingTranslator(inner, NewLRUCache(1<<10, 0), 2)
+
+	translatr.Translate(context.Background(), []byte("doc"), format.Docx, "en", "vi")
+	translatr.Translate(context.Background(), []byte("doc"), format.Docx, "en", "vi")
+
+	if inner.calls.Load() != 2 {
+		t.Fatalf("expected %v calls, got %v", 2, inner.calls.Load())
+	}
+}
+
+func TestCachingTranslator_Err(t *testing.T) {
+	e := errors.New("translate error")
+	inner := &countingTranslator{err: e}
+	translatr := NewCachingTranslator(inner, NewLRUCache(1<<10, 0), 1<<10)
+
+	for i := 0; i < 2; i++ {
+		if _, err := translatr.Translate(context.Background(), []byte("doc"), format.Docx, "en", "vi"); err != e {
+			t.Fatalf("expected %v, got %v", e, err)
+		}
+	}
+
+	// Failures are not cached
+	if inner.calls.Load() != 2 {
+		t.Fatalf("expected %v calls, got %v", 2, inner.calls.Load())
+	}
+}
+
+func TestCachingTranslator_LeaderCancelled(t *testing.T) {
+	inner := &countingTranslator{release: make(chan struct{})}
+	translatr := NewCachingTranslator(inner, NewLRUCache(1<<10, 0), 1<<10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := translatr.Translate(ctx, []byte("doc"), format.Docx, "en", "vi")
+		leaderErr <- err
+	}()
+
+	for inner.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	followerErr := make(chan error, 1)
+	go func() {
+		_, err := translatr.Translate(context.Background(), []byte("doc"), format.Docx, "en", "vi")
+		followerErr <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+
+	// The follower translates on its own rather than failing with the leader
+	for inner.calls.Load() != 2 {
+		time.Sleep(time.Millisecond)
+	}
+	close(inner.release)
+
+	if err := <-followerErr; err != nil {
+		t.Fatal(err)
+	}
+}
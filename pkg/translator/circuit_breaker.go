@@ -0,0 +1,107 @@
+package translator
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// BreakerState is a snapshot of a backend's circuit breaker for monitoring.
+type BreakerState struct {
+	Name     string    `json:"name"`
+	State    string    `json:"state" enums:"closed,open,half-open"`
+	Failures int       `json:"failures"`
+	OpenedAt time.Time `json:"opened_at"`
+}
+
+// CircuitBreaker stops calling a backend after threshold consecutive failures.
+// Once openTimeout has passed a single probe is let through, closing the
+// breaker again if it succeeds.
+type CircuitBreaker struct {
+	mu          sync.Mutex
+	threshold   int
+	openTimeout time.Duration
+	state       string
+	failures    int
+	openedAt    time.Time
+	probing     bool
+	now         func() time.Time
+}
+
+func NewCircuitBreaker(threshold int, openTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold:   max(threshold, 1),
+		openTimeout: openTimeout,
+		state:       BreakerClosed,
+		now:         time.Now,
+	}
+}
+
+// Allow returns ErrCircuitOpen when the backend must not be called.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+	case BreakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+	default:
+		return nil
+	}
+
+	b.probing = true
+	return nil
+}
+
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = BreakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Release ends a call that says nothing about the backend's health, such as
+// one the caller cancelled, so a half-open breaker lets another probe through.
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+func (b *CircuitBreaker) State(name string) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return BreakerState{Name: name, State: b.state, Failures: b.failures, OpenedAt: b.openedAt}
+}
@@ -0,0 +1,82 @@
+package translator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+
+	b := NewCircuitBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	b.Failure()
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected closed breaker, got %v", err)
+	}
+
+	b.Failure()
+	if err := b.Allow(); err != ErrCircuitOpen {
+		t.Fatalf("expected %v, got %v", ErrCircuitOpen, err)
+	}
+
+	now = now.Add(time.Minute)
+
+	// A single probe is let through once half-open
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected probe to be allowed, got %v", err)
+	}
+
+	if err := b.Allow(); err != ErrCircuitOpen {
+		t.Fatalf("expected %v, got %v", ErrCircuitOpen, err)
+	}
+
+	if state := b.State("grpc").State; state != BreakerHalfOpen {
+		t.Fatalf("expected %v, got %v", BreakerHalfOpen, state)
+	}
+
+	// A failed probe opens it straight away
+	b.Failure()
+	if err := b.Allow(); err != ErrCircuitOpen {
+		t.Fatalf("expected %v, got %v", ErrCircuitOpen, err)
+	}
+
+	now = now.Add(time.Minute)
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected probe to be allowed, got %v", err)
+	}
+
+	b.Success()
+
+	state := b.State("grpc")
+	if state.State != BreakerClosed || state.Failures != 0 {
+		t.Fatalf("expected closed breaker without failures, got %v", state)
+	}
+}
+
+func TestCircuitBreaker_Release(t *testing.T) {
+	now := time.Now()
+
+	b := NewCircuitBreaker(1, time.Minute)
+	b.now = func() time.Time { return now }
+
+	b.Failure()
+	now = now.Add(time.Minute)
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected probe to be allowed, got %v", err)
+	}
+
+	// A released probe neither closes nor reopens the breaker
+	b.Release()
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected another probe to be allowed, got %v", err)
+	}
+
+	if state := b.State("grpc").State; state != BreakerHalfOpen {
+		t.Fatalf("expected %v, got %v", BreakerHalfOpen, state)
+	}
+}
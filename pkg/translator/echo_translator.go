@@ -1,5 +1,7 @@
 package translator
 
+import "context"
+
 // GrpcTranslator makes gRPC call to another service to translate documents
 type EchoTranslator struct{}
 
@@ -7,7 +9,7 @@ func NewEchoTranslator() *EchoTranslator {
 	return &EchoTranslator{}
 }
 
-func (t *EchoTranslator) Translate(b []byte, sourceLang string, targetLang string) ([]byte, error) {
+func (t *EchoTranslator) Translate(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
 	return b, nil
 }
 
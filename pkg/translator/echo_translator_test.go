@@ -1,6 +1,8 @@
 package translator
 
 import (
+	"context"
+	"doc-translate-go/pkg/format"
 	"reflect"
 	"testing"
 )
@@ -10,7 +12,7 @@ func TestEchoTranslator(t *testing.T) {
 
 	want := []byte{}
 
-	got, err := translatr.Translate([]byte{}, "sourceLang", "targetLang")
+	got, err := translatr.Translate(context.Background(), []byte{}, format.Docx, "sourceLang", "targetLang")
 	if err != nil {
 		t.Fatal(err)
 	}
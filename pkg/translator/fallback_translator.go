@@ -0,0 +1,109 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrTimeout = errors.New("translator timed out")
+
+// Backend is one translator of a FallbackTranslator chain.
+type Backend struct {
+	Name       string
+	Translator Translator
+	// Timeout bounds a single translation, 0 means no limit
+	Timeout time.Duration
+	Breaker *CircuitBreaker
+}
+
+func NewBackend(name string, translator Translator, timeout time.Duration, breaker *CircuitBreaker) *Backend {
+	return &Backend{name, translator, timeout, breaker}
+}
+
+func (b *Backend) translate(ctx context.Context, doc []byte, mimeType string, sourceLang string, targetLang string, onProgress ProgressFunc) ([]byte, error) {
+	if b.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.Timeout)
+		defer cancel()
+	}
+
+	var out []byte
+	var err error
+	if pt, ok := b.Translator.(ProgressTranslator); ok && onProgress != nil {
+		out, err = pt.TranslateWithProgress(ctx, doc, mimeType, sourceLang, targetLang, onProgress)
+	} else {
+		out, err = b.Translator.Translate(ctx, doc, mimeType, sourceLang, targetLang)
+	}
+
+	// Tell the backend timing out apart from the caller giving up
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, errors.Join(ErrTimeout, err)
+	}
+
+	return out, err
+}
+
+// FallbackTranslator tries its backends in order until one succeeds. Backends
+// whose breaker is open are skipped. Errors isFailure rejects, such as a
+// corrupt document, are returned straight away as every backend would fail too.
+type FallbackTranslator struct {
+	backends  []*Backend
+	isFailure func(error) bool
+}
+
+func NewFallbackTranslator(backends []*Backend, isFailure func(error) bool) *FallbackTranslator {
+	return &FallbackTranslator{backends, isFailure}
+}
+
+func (t *FallbackTranslator) Translate(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+	return t.TranslateWithProgress(ctx, b, mimeType, sourceLang, targetLang, nil)
+}
+
+func (t *FallbackTranslator) TranslateWithProgress(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string, onProgress ProgressFunc) ([]byte, error) {
+	var errs []error
+
+	for _, backend := range t.backends {
+		if err := backend.Breaker.Allow(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", backend.Name, err))
+			continue
+		}
+
+		out, err := backend.translate(ctx, b, mimeType, sourceLang, targetLang, onProgress)
+		if err == nil {
+			backend.Breaker.Success()
+			return out, nil
+		}
+
+		// Cancelled by the caller, which says nothing about the backend
+		if ctx.Err() != nil {
+			backend.Breaker.Release()
+			return nil, err
+		}
+
+		if !errors.Is(err, ErrTimeout) && !t.isFailure(err) {
+			// The backend answered, it's the request that is wrong
+			backend.Breaker.Success()
+			return nil, err
+		}
+
+		backend.Breaker.Failure()
+		errs = append(errs, fmt.Errorf("%s: %w", backend.Name, err))
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// BreakerStates returns the breaker state of every backend in order.
+func (t *FallbackTranslator) BreakerStates() []BreakerState {
+	states := make([]BreakerState, len(t.backends))
+	for i, backend := range t.backends {
+		states[i] = backend.Breaker.State(backend.Name)
+	}
+
+	return states
+}
+
+// Ensure implementation
+var _ ProgressTranslator = (*FallbackTranslator)(nil)
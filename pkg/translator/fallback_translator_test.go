@@ -0,0 +1,119 @@
+package translator
+
+import (
+	"context"
+	"doc-translate-go/pkg/format"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func isUnavailable(err error) bool {
+	return status.Code(err) == codes.Unavailable
+}
+
+func TestFallbackTranslator(t *testing.T) {
+	down := &countingTranslator{err: status.Error(codes.Unavailable, "down")}
+	up := &countingTranslator{}
+
+	translatr := NewFallbackTranslator([]*Backend{
+		NewBackend("primary", down, 0, NewCircuitBreaker(2, time.Minute)),
+		NewBackend("secondary", up, 0, NewCircuitBreaker(2, time.Minute)),
+	}, isUnavailable)
+
+	for i := 0; i < 3; i++ {
+		got, err := translatr.Translate(context.Background(), []byte("doc"), format.Docx, "en", "vi")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual([]byte("cod"), got) {
+			t.Fatalf("expected %s, got %s", "cod", got)
+		}
+	}
+
+	// The primary's breaker opened after two failures
+	if down.calls.Load() != 2 || up.calls.Load() != 3 {
+		t.Fatalf("expected 2 and 3 calls, got %v and %v", down.calls.Load(), up.calls.Load())
+	}
+
+	states := translatr.BreakerStates()
+	if states[0].Name != "primary" || states[0].State != BreakerOpen || states[1].State != BreakerClosed {
+		t.Fatalf("unexpected breaker states %v", states)
+	}
+}
+
+func TestFallbackTranslator_PermanentErr(t *testing.T) {
+	e := status.Error(codes.InvalidArgument, "corrupt docx")
+	primary := &countingTranslator{err: e}
+	secondary := &countingTranslator{}
+
+	translatr := NewFallbackTranslator([]*Backend{
+		NewBackend("primary", primary, 0, NewCircuitBreaker(1, time.Minute)),
+		NewBackend("secondary", secondary, 0, NewCircuitBreaker(1, time.Minute)),
+	}, isUnavailable)
+
+	if _, err := translatr.Translate(context.Background(), []byte("doc"), format.Docx, "en", "vi"); err != e {
+		t.Fatalf("expected %v, got %v", e, err)
+	}
+
+	if secondary.calls.Load() != 0 {
+		t.Fatal("expected no fallback on a permanent error")
+	}
+
+	if state := translatr.BreakerStates()[0].State; state != BreakerClosed {
+		t.Fatalf("expected %v, got %v", BreakerClosed, state)
+	}
+}
+
+func TestFallbackTranslator_Timeout(t *testing.T) {
+	slow := &countingTranslator{release: make(chan struct{})}
+	defer close(slow.release)
+
+	translatr := NewFallbackTranslator([]*Backend{
+		NewBackend("slow", slow, 10*time.Millisecond, NewCircuitBreaker(1, time.Minute)),
+	}, isUnavailable)
+
+	_, err := translatr.Translate(context.Background(), []byte("doc"), format.Docx, "en", "vi")
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected %v, got %v", ErrTimeout, err)
+	}
+
+	// Every backend failing is reported together, here the open breaker
+	_, err = translatr.Translate(context.Background(), []byte("doc"), format.Docx, "en", "vi")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected %v, got %v", ErrCircuitOpen, err)
+	}
+}
+
+func TestFallbackTranslator_Cancelled(t *testing.T) {
+	slow := &countingTranslator{release: make(chan struct{})}
+	defer close(slow.release)
+	secondary := &countingTranslator{}
+
+	translatr := NewFallbackTranslator([]*Backend{
+		NewBackend("slow", slow, time.Minute, NewCircuitBreaker(1, time.Minute)),
+		NewBackend("secondary", secondary, 0, NewCircuitBreaker(1, time.Minute)),
+	}, isUnavailable)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err := translatr.Translate(ctx, []byte("doc"), format.Docx, "en", "vi")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+
+	// The caller giving up is neither the backend's fault nor a reason to fall back
+	if secondary.calls.Load() != 0 {
+		t.Fatal("expected no fallback once cancelled")
+	}
+
+	if state := translatr.BreakerStates()[0]; state.State != BreakerClosed || state.Failures != 0 {
+		t.Fatalf("expected closed breaker without failures, got %v", state)
+	}
+}
@@ -0,0 +1,59 @@
+package translator
+
+import (
+	"context"
+	"doc-translate-go/pkg/file/repository"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// FileCache stores translations in a FileRepository under prefix, for caching
+// large documents cheaply. Each file starts with its expiry as unix seconds.
+type FileCache struct {
+	repo   repository.FileRepository
+	prefix string
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewFileCache returns a cache whose entries expire after ttl, or never when ttl is 0.
+func NewFileCache(repo repository.FileRepository, prefix string, ttl time.Duration) *FileCache {
+	return &FileCache{repo, prefix, ttl, time.Now}
+}
+
+// Get treats any error from the repository as a miss as they can't tell a
+// missing file apart from other failures.
+func (c *FileCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	b, err := c.repo.Get(ctx, c.filepath(key))
+	if err != nil || len(b) < 8 {
+		return nil, false, nil
+	}
+
+	expiresAt := int64(binary.BigEndian.Uint64(b[:8]))
+	if expiresAt > 0 && c.now().Unix() >= expiresAt {
+		c.repo.Delete(ctx, c.filepath(key))
+		return nil, false, nil
+	}
+
+	return b[8:], true, nil
+}
+
+func (c *FileCache) Set(ctx context.Context, key string, b []byte) error {
+	var expiresAt int64
+	if c.ttl > 0 {
+		expiresAt = c.now().Add(c.ttl).Unix()
+	}
+
+	out := make([]byte, 8, 8+len(b))
+	binary.BigEndian.PutUint64(out, uint64(expiresAt))
+
+	return c.repo.Persist(ctx, append(out, b...), c.filepath(key))
+}
+
+func (c *FileCache) filepath(key string) string {
+	return fmt.Sprintf("%s/%s", c.prefix, key)
+}
+
+// Ensure implementation
+var _ Cache = (*FileCache)(nil)
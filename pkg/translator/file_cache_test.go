@@ -0,0 +1,44 @@
+package translator
+
+import (
+	"context"
+	"doc-translate-go/pkg/file/repository/filesystem"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFileCache(t *testing.T) {
+	now := time.Now()
+
+	repo := filesystem.NewFilesystemFileRepository(t.TempDir(), []byte("secret"), "", time.Minute)
+	c := NewFileCache(repo, "translation-cache", time.Minute)
+	c.now = func() time.Time { return now }
+
+	if _, ok, err := c.Get(context.Background(), "key"); ok || err != nil {
+		t.Fatalf("expected miss, got %v, %v", ok, err)
+	}
+
+	if err := c.Set(context.Background(), "key", []byte("doc")); err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok, err := c.Get(context.Background(), "key")
+	if err != nil || !ok {
+		t.Fatalf("expected hit, got %v, %v", ok, err)
+	}
+
+	if !reflect.DeepEqual([]byte("doc"), got) {
+		t.Fatalf("expected %s, got %s", "doc", got)
+	}
+
+	now = now.Add(time.Minute)
+
+	if _, ok, _ := c.Get(context.Background(), "key"); ok {
+		t.Fatal("expected key to have expired")
+	}
+
+	if _, err := repo.Get(context.Background(), "translation-cache/key"); err == nil {
+		t.Fatal("expected expired file to be deleted")
+	}
+}
@@ -0,0 +1,40 @@
+package translator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// Term is how a source term must be translated.
+type Term struct {
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	Required bool   `json:"required"`
+}
+
+type glossaryKey struct{}
+
+// WithGlossary returns a context carrying the glossary translators pass on
+// to their backend along with the document.
+func WithGlossary(ctx context.Context, terms []Term) context.Context {
+	return context.WithValue(ctx, glossaryKey{}, terms)
+}
+
+// Glossary returns the glossary of ctx, if any.
+func Glossary(ctx context.Context) []Term {
+	terms, _ := ctx.Value(glossaryKey{}).([]Term)
+	return terms
+}
+
+// glossaryHash tells glossaries apart in cache keys, empty for no glossary.
+func glossaryHash(terms []Term) string {
+	if len(terms) == 0 {
+		return ""
+	}
+
+	b, _ := json.Marshal(terms)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -1,24 +1,42 @@
 package translator
 
 import (
+	"bytes"
 	"context"
-	"time"
+	"io"
 
 	documentproto "doc-translate-go/gen/go/proto/documentprocessor"
 )
 
 // GrpcTranslator makes gRPC call to another service to translate documents
 type GrpcTranslator struct {
-	client documentproto.DocumentProcessorClient
+	client          documentproto.DocumentProcessorClient
+	streamThreshold int
+	chunkSize       int
+	progress        bool
 }
 
-func NewGrpcTranslator(client documentproto.DocumentProcessorClient) *GrpcTranslator {
-	return &GrpcTranslator{client}
+// NewGrpcTranslator returns a translator that sends documents larger than
+// streamThreshold bytes over ProcessDocumentStream in chunks of chunkSize bytes.
+// Smaller documents go over ProcessDocumentWithProgress when progress is set,
+// which needs a document processor implementing it. Calls are bounded by ctx,
+// which a Backend gives its timeout.
+func NewGrpcTranslator(client documentproto.DocumentProcessorClient, streamThreshold int, chunkSize int, progress bool) *GrpcTranslator {
+	return &GrpcTranslator{client, streamThreshold, chunkSize, progress}
 }
 
-func (t *GrpcTranslator) Translate(b []byte, sourceLang string, targetLang string) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10000*time.Second)
-	defer cancel()
+func (t *GrpcTranslator) Translate(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+	return t.TranslateWithProgress(ctx, b, mimeType, sourceLang, targetLang, nil)
+}
+
+func (t *GrpcTranslator) TranslateWithProgress(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string, onProgress ProgressFunc) ([]byte, error) {
+	if len(b) > t.streamThreshold {
+		return t.translateStream(ctx, b, mimeType, sourceLang, targetLang, onProgress)
+	}
+
+	if t.progress {
+		return t.translateWithProgress(ctx, b, mimeType, sourceLang, targetLang, onProgress)
+	}
 
 	resp, err := t.client.ProcessDocument(
 		ctx,
@@ -26,14 +44,145 @@ func (t *GrpcTranslator) Translate(b []byte, sourceLang string, targetLang strin
 			Document:   b,
 			SourceLang: sourceLang,
 			TargetLang: targetLang,
+			MimeType:   mimeType,
+			Glossary:   glossaryProto(ctx),
+			Memory:     memoryProto(ctx),
 		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	RecordUsage(ctx, resp.GetBilledCharacters(), resp.GetBilledTokens())
+
 	return resp.GetDocument(), nil
 }
 
+func (t *GrpcTranslator) translateWithProgress(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string, onProgress ProgressFunc) ([]byte, error) {
+	stream, err := t.client.ProcessDocumentWithProgress(
+		ctx,
+		&documentproto.DocumentRequest{
+			Document:   b,
+			SourceLang: sourceLang,
+			TargetLang: targetLang,
+			MimeType:   mimeType,
+			Glossary:   glossaryProto(ctx),
+			Memory:     memoryProto(ctx),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.Document != nil {
+			RecordUsage(ctx, resp.GetBilledCharacters(), resp.GetBilledTokens())
+			return resp.GetDocument(), nil
+		}
+
+		report(onProgress, resp.GetPercent())
+	}
+}
+
+func (t *GrpcTranslator) translateStream(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string, onProgress ProgressFunc) ([]byte, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := t.client.ProcessDocumentStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send concurrently so a server that replies before reading everything
+	// can't block on flow control.
+	sendErr := make(chan error, 1)
+	go func() {
+		sendErr <- t.sendChunks(stream, b, mimeType, sourceLang, targetLang, glossaryProto(ctx), memoryProto(ctx))
+	}()
+
+	var buf bytes.Buffer
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		report(onProgress, resp.GetPercent())
+		RecordUsage(ctx, resp.GetBilledCharacters(), resp.GetBilledTokens())
+		buf.Write(resp.GetChunk())
+	}
+
+	if err := <-sendErr; err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (t *GrpcTranslator) sendChunks(stream documentproto.DocumentProcessor_ProcessDocumentStreamClient, b []byte, mimeType string, sourceLang string, targetLang string, glossary []*documentproto.GlossaryTerm, memory []*documentproto.MemoryMatch) error {
+	for offset := 0; offset < len(b); offset += t.chunkSize {
+		req := &documentproto.DocumentChunkRequest{
+			Chunk: b[offset:min(offset+t.chunkSize, len(b))],
+		}
+
+		if offset == 0 {
+			req.SourceLang = sourceLang
+			req.TargetLang = targetLang
+			req.MimeType = mimeType
+			req.Glossary = glossary
+			req.Memory = memory
+		}
+
+		if err := stream.Send(req); err != nil {
+			return err
+		}
+	}
+
+	return stream.CloseSend()
+}
+
+// glossaryProto returns the glossary of ctx as sent to the document processor.
+func glossaryProto(ctx context.Context) []*documentproto.GlossaryTerm {
+	var result []*documentproto.GlossaryTerm
+	for _, term := range Glossary(ctx) {
+		result = append(result, &documentproto.GlossaryTerm{
+			Source:   term.Source,
+			Target:   term.Target,
+			Required: term.Required,
+		})
+	}
+	return result
+}
+
+// memoryProto returns the translation memory matches of ctx as sent to the
+// document processor.
+func memoryProto(ctx context.Context) []*documentproto.MemoryMatch {
+	var result []*documentproto.MemoryMatch
+	for _, m := range Memory(ctx) {
+		result = append(result, &documentproto.MemoryMatch{
+			Source: m.Source,
+			Target: m.Target,
+			Score:  m.Score,
+		})
+	}
+	return result
+}
+
+func report(onProgress ProgressFunc, percent int32) {
+	if onProgress != nil && percent > 0 {
+		onProgress(int(min(percent, 100)))
+	}
+}
+
 // Ensure implementation
-var _ Translator = (*GrpcTranslator)(nil)
+var _ ProgressTranslator = (*GrpcTranslator)(nil)
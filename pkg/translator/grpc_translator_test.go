@@ -1,22 +1,156 @@
 package translator
 
 import (
+	"bytes"
+	"context"
 	"doc-translate-go/mocks"
+	"doc-translate-go/pkg/format"
 	"errors"
+	"io"
+	"net"
+	"reflect"
 	"testing"
 
+	documentproto "doc-translate-go/gen/go/proto/documentprocessor"
+
 	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
 )
 
+// bufconnServer reverses documents so tests can tell translated bytes apart
+// and records how each document arrived. It bills a character per byte and a
+// token per two.
+type bufconnServer struct {
+	documentproto.UnimplementedDocumentProcessorServer
+
+	unaryCalls    int
+	streamCalls   int
+	progressCalls int
+	chunks        int
+	sourceLang    string
+	targetLang    string
+	mimeType      string
+	glossary      []*documentproto.GlossaryTerm
+	memory        []*documentproto.MemoryMatch
+}
+
+func (s *bufconnServer) ProcessDocument(ctx context.Context, req *documentproto.DocumentRequest) (*documentproto.DocumentResponse, error) {
+	s.unaryCalls++
+	s.sourceLang = req.GetSourceLang()
+	s.targetLang = req.GetTargetLang()
+	s.mimeType = req.GetMimeType()
+	s.glossary = req.GetGlossary()
+	s.memory = req.GetMemory()
+	n := int64(len(req.GetDocument()))
+	return &documentproto.DocumentResponse{Document: reverse(req.GetDocument()), BilledCharacters: n, BilledTokens: n / 2}, nil
+}
+
+func (s *bufconnServer) ProcessDocumentStream(stream documentproto.DocumentProcessor_ProcessDocumentStreamServer) error {
+	s.streamCalls++
+
+	var buf bytes.Buffer
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if s.chunks == 0 {
+			s.sourceLang = req.GetSourceLang()
+			s.targetLang = req.GetTargetLang()
+			s.mimeType = req.GetMimeType()
+			s.glossary = req.GetGlossary()
+			s.memory = req.GetMemory()
+		} else if len(req.GetGlossary()) > 0 {
+			return errors.New("glossary sent past the first chunk")
+		}
+		s.chunks++
+		buf.Write(req.GetChunk())
+	}
+
+	if err := stream.Send(&documentproto.DocumentChunkResponse{Percent: 50}); err != nil {
+		return err
+	}
+
+	out := reverse(buf.Bytes())
+	for offset := 0; offset < len(out); offset += 3 {
+		resp := &documentproto.DocumentChunkResponse{Chunk: out[offset:min(offset+3, len(out))]}
+		if offset+3 >= len(out) {
+			resp.BilledCharacters, resp.BilledTokens = int64(len(out)), int64(len(out)/2)
+		}
+
+		err := stream.Send(resp)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *bufconnServer) ProcessDocumentWithProgress(req *documentproto.DocumentRequest, stream documentproto.DocumentProcessor_ProcessDocumentWithProgressServer) error {
+	s.progressCalls++
+	s.sourceLang = req.GetSourceLang()
+	s.targetLang = req.GetTargetLang()
+
+	for _, percent := range []int32{25, 75} {
+		if err := stream.Send(&documentproto.DocumentProgressResponse{Percent: percent}); err != nil {
+			return err
+		}
+	}
+
+	n := int64(len(req.GetDocument()))
+	return stream.Send(&documentproto.DocumentProgressResponse{
+		Percent:          100,
+		Document:         reverse(req.GetDocument()),
+		BilledCharacters: n,
+		BilledTokens:     n / 2,
+	})
+}
+
+func reverse(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i, c := range b {
+		out[len(b)-1-i] = c
+	}
+	return out
+}
+
+func newBufconnClient(t *testing.T, srv documentproto.DocumentProcessorServer) documentproto.DocumentProcessorClient {
+	lis := bufconn.Listen(1 << 20)
+
+	s := grpc.NewServer()
+	documentproto.RegisterDocumentProcessorServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial(
+		"bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return documentproto.NewDocumentProcessorClient(conn)
+}
+
 func TestGrpcTranslator(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	c := mocks.NewMockDocumentProcessorClient(ctrl)
 
-	translatr := NewGrpcTranslator(c)
+	translatr := NewGrpcTranslator(c, 10, 4, false)
 
 	c.EXPECT().ProcessDocument(gomock.Any(), gomock.Any()).Times(1)
 
-	_, err := translatr.Translate([]byte{}, "sourceLang", "targetLang")
+	_, err := translatr.Translate(context.Background(), []byte{}, format.Docx, "sourceLang", "targetLang")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -26,12 +160,12 @@ func TestGrpcTranslator__Err(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	c := mocks.NewMockDocumentProcessorClient(ctrl)
 
-	translatr := NewGrpcTranslator(c)
+	translatr := NewGrpcTranslator(c, 10, 4, false)
 
 	e := errors.New("process error")
 	c.EXPECT().ProcessDocument(gomock.Any(), gomock.Any()).Return(nil, e).Times(1)
 
-	b, err := translatr.Translate([]byte{}, "sourceLang", "targetLang")
+	b, err := translatr.Translate(context.Background(), []byte{}, format.Docx, "sourceLang", "targetLang")
 	if err == nil {
 		t.Fatal("expect error, got nil")
 	}
@@ -40,3 +174,189 @@ func TestGrpcTranslator__Err(t *testing.T) {
 		t.Fatalf("expect nil, got %v", b)
 	}
 }
+
+func TestGrpcTranslator_Stream_Err(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c := mocks.NewMockDocumentProcessorClient(ctrl)
+
+	translatr := NewGrpcTranslator(c, 2, 4, false)
+
+	e := errors.New("stream error")
+	c.EXPECT().ProcessDocumentStream(gomock.Any()).Return(nil, e).Times(1)
+
+	b, err := translatr.Translate(context.Background(), []byte("large"), format.Docx, "sourceLang", "targetLang")
+	if err != e {
+		t.Fatalf("expected %v, got %v", e, err)
+	}
+
+	if b != nil {
+		t.Fatalf("expect nil, got %v", b)
+	}
+}
+
+func TestGrpcTranslator_Bufconn_Unary(t *testing.T) {
+	srv := &bufconnServer{}
+	translatr := NewGrpcTranslator(newBufconnClient(t, srv), 10, 4, false)
+
+	got, err := translatr.Translate(context.Background(), []byte("small"), format.Docx, "en", "fr")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte("llams")
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+
+	if srv.unaryCalls != 1 || srv.streamCalls != 0 {
+		t.Fatalf("expected 1 unary and 0 stream calls, got %v and %v", srv.unaryCalls, srv.streamCalls)
+	}
+
+	if srv.sourceLang != "en" || srv.targetLang != "fr" {
+		t.Fatalf("expected en to fr, got %v to %v", srv.sourceLang, srv.targetLang)
+	}
+
+	if srv.mimeType != format.Docx {
+		t.Fatalf("expected %v, got %v", format.Docx, srv.mimeType)
+	}
+}
+
+func TestGrpcTranslator_Bufconn_Stream(t *testing.T) {
+	srv := &bufconnServer{}
+	translatr := NewGrpcTranslator(newBufconnClient(t, srv), 10, 4, false)
+
+	got, err := translatr.Translate(context.Background(), []byte("a much larger document"), format.Docx, "en", "fr")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte("tnemucod regral hcum a")
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+
+	if srv.unaryCalls != 0 || srv.streamCalls != 1 {
+		t.Fatalf("expected 0 unary and 1 stream calls, got %v and %v", srv.unaryCalls, srv.streamCalls)
+	}
+
+	if srv.chunks != 6 {
+		t.Fatalf("expected %v chunks, got %v", 6, srv.chunks)
+	}
+
+	if srv.sourceLang != "en" || srv.targetLang != "fr" {
+		t.Fatalf("expected en to fr, got %v to %v", srv.sourceLang, srv.targetLang)
+	}
+
+	if srv.mimeType != format.Docx {
+		t.Fatalf("expected %v, got %v", format.Docx, srv.mimeType)
+	}
+}
+
+func TestGrpcTranslator_Bufconn_Progress(t *testing.T) {
+	srv := &bufconnServer{}
+	translatr := NewGrpcTranslator(newBufconnClient(t, srv), 10, 4, true)
+
+	var percents []int
+	got, err := translatr.TranslateWithProgress(context.Background(), []byte("small"), format.Docx, "en", "fr", func(percent int) {
+		percents = append(percents, percent)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte("llams")
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+
+	if srv.progressCalls != 1 || srv.unaryCalls != 0 {
+		t.Fatalf("expected 1 progress and 0 unary calls, got %v and %v", srv.progressCalls, srv.unaryCalls)
+	}
+
+	if !reflect.DeepEqual([]int{25, 75}, percents) {
+		t.Fatalf("expected %v, got %v", []int{25, 75}, percents)
+	}
+}
+
+func TestGrpcTranslator_Bufconn_Stream_Progress(t *testing.T) {
+	srv := &bufconnServer{}
+	translatr := NewGrpcTranslator(newBufconnClient(t, srv), 10, 4, true)
+
+	var percents []int
+	got, err := translatr.TranslateWithProgress(context.Background(), []byte("a much larger document"), format.Docx, "en", "fr", func(percent int) {
+		percents = append(percents, percent)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte("tnemucod regral hcum a")
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+
+	if !reflect.DeepEqual([]int{50}, percents) {
+		t.Fatalf("expected %v, got %v", []int{50}, percents)
+	}
+}
+
+func TestGrpcTranslator_Bufconn_Usage(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress bool
+		doc      string
+	}{
+		{"unary", false, "small"},
+		{"progress", true, "small"},
+		{"stream", false, "a much larger document"},
+	}
+
+	for _, tt := range tests {
+		translatr := NewGrpcTranslator(newBufconnClient(t, &bufconnServer{}), 10, 4, tt.progress)
+
+		ctx, usage := WithUsage(context.Background())
+		if _, err := translatr.Translate(ctx, []byte(tt.doc), format.Docx, "en", "fr"); err != nil {
+			t.Fatal(err)
+		}
+
+		if usage.Characters() != len(tt.doc) || usage.Tokens() != len(tt.doc)/2 {
+			t.Fatalf("%s: expected %v characters and %v tokens, got %v and %v", tt.name, len(tt.doc), len(tt.doc)/2, usage.Characters(), usage.Tokens())
+		}
+	}
+}
+
+func TestGrpcTranslator_Bufconn_Glossary(t *testing.T) {
+	srv := &bufconnServer{}
+	translatr := NewGrpcTranslator(newBufconnClient(t, srv), 10, 4, false)
+
+	ctx := WithGlossary(context.Background(), []Term{{Source: "Acme Cloud", Target: "Acme Cloud", Required: true}})
+
+	for _, doc := range []string{"doc", "a much larger document"} {
+		srv.glossary = nil
+		if _, err := translatr.Translate(ctx, []byte(doc), format.Docx, "en", "vi"); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(srv.glossary) != 1 || srv.glossary[0].GetSource() != "Acme Cloud" || !srv.glossary[0].GetRequired() {
+			t.Fatalf("%q: unexpected glossary %v", doc, srv.glossary)
+		}
+	}
+}
+
+func TestGrpcTranslator_Bufconn_Memory(t *testing.T) {
+	srv := &bufconnServer{}
+	translatr := NewGrpcTranslator(newBufconnClient(t, srv), 10, 4, false)
+
+	ctx := WithMemory(context.Background(), []MemoryMatch{{Source: "Read the docs!", Target: "Đọc tài liệu!", Score: 0.8}})
+
+	for _, doc := range []string{"doc", "a much larger document"} {
+		srv.memory = nil
+		if _, err := translatr.Translate(ctx, []byte(doc), format.Text, "en", "vi"); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(srv.memory) != 1 || srv.memory[0].GetTarget() != "Đọc tài liệu!" || srv.memory[0].GetScore() != 0.8 {
+			t.Fatalf("%q: unexpected memory %v", doc, srv.memory)
+		}
+	}
+}
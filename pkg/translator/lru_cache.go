@@ -0,0 +1,94 @@
+package translator
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type lruEntry struct {
+	key       string
+	b         []byte
+	expiresAt time.Time
+}
+
+// LRUCache keeps translations in process memory up to maxBytes in total,
+// evicting the least recently used first.
+type LRUCache struct {
+	mu       sync.Mutex
+	maxBytes int
+	ttl      time.Duration
+	size     int
+	order    *list.List
+	entries  map[string]*list.Element
+	now      func() time.Time
+}
+
+// NewLRUCache returns a cache of at most maxBytes whose entries expire after
+// ttl, or never when ttl is 0.
+func NewLRUCache(maxBytes int, ttl time.Duration) *LRUCache {
+	return &LRUCache{
+		maxBytes: maxBytes,
+		ttl:      ttl,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+func (c *LRUCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := el.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && !c.now().Before(entry.expiresAt) {
+		c.remove(el)
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(el)
+
+	return entry.b, true, nil
+}
+
+func (c *LRUCache) Set(ctx context.Context, key string, b []byte) error {
+	if len(b) > c.maxBytes {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+
+	entry := &lruEntry{key: key, b: b}
+	if c.ttl > 0 {
+		entry.expiresAt = c.now().Add(c.ttl)
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	c.size += len(b)
+
+	for c.size > c.maxBytes {
+		c.remove(c.order.Back())
+	}
+
+	return nil
+}
+
+func (c *LRUCache) remove(el *list.Element) {
+	entry := c.order.Remove(el).(*lruEntry)
+	delete(c.entries, entry.key)
+	c.size -= len(entry.b)
+}
+
+// Ensure implementation
+var _ Cache = (*LRUCache)(nil)
@@ -0,0 +1,61 @@
+package translator
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRUCache_Evict(t *testing.T) {
+	c := NewLRUCache(6, 0)
+
+	c.Set(context.Background(), "a", []byte("aa"))
+	c.Set(context.Background(), "b", []byte("bb"))
+	c.Set(context.Background(), "c", []byte("cc"))
+
+	// a becomes the most recently used, so b is evicted next
+	if _, ok, _ := c.Get(context.Background(), "a"); !ok {
+		t.Fatal("expected a to be cached")
+	}
+
+	c.Set(context.Background(), "d", []byte("dd"))
+
+	if _, ok, _ := c.Get(context.Background(), "b"); ok {
+		t.Fatal("expected b to be evicted")
+	}
+
+	for _, key := range []string{"a", "c", "d"} {
+		if _, ok, _ := c.Get(context.Background(), key); !ok {
+			t.Fatalf("expected %v to be cached", key)
+		}
+	}
+
+	// Larger than the whole cache
+	c.Set(context.Background(), "e", []byte("eeeeeee"))
+	if _, ok, _ := c.Get(context.Background(), "e"); ok {
+		t.Fatal("expected e not to be cached")
+	}
+}
+
+func TestLRUCache_TTL(t *testing.T) {
+	now := time.Now()
+
+	c := NewLRUCache(10, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set(context.Background(), "a", []byte("aa"))
+
+	if _, ok, _ := c.Get(context.Background(), "a"); !ok {
+		t.Fatal("expected a to be cached")
+	}
+
+	now = now.Add(time.Minute)
+
+	if _, ok, _ := c.Get(context.Background(), "a"); ok {
+		t.Fatal("expected a to have expired")
+	}
+
+	if c.size != 0 {
+		t.Fatalf("expected size %v, got %v", 0, c.size)
+	}
+}
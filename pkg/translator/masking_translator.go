@@ -0,0 +1,46 @@
+package translator
+
+import (
+	"context"
+	"doc-translate-go/pkg/mask"
+)
+
+// MaskingTranslator decorates a translator so protected text, placeholders,
+// URLs, product codes and the like, comes back from translation as it is.
+type MaskingTranslator struct {
+	translator Translator
+	rules      *mask.Rules
+}
+
+// NewMaskingTranslator returns a translator masking the text rules protect
+// before translator sees it and restoring it afterwards. Translations losing
+// any of it fail with mask.ErrLost.
+func NewMaskingTranslator(translator Translator, rules *mask.Rules) *MaskingTranslator {
+	return &MaskingTranslator{translator, rules}
+}
+
+func (t *MaskingTranslator) Translate(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+	return t.TranslateWithProgress(ctx, b, mimeType, sourceLang, targetLang, nil)
+}
+
+func (t *MaskingTranslator) TranslateWithProgress(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string, onProgress ProgressFunc) ([]byte, error) {
+	masked, set, err := t.rules.Mask(b, mimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	var translated []byte
+	if pt, ok := t.translator.(ProgressTranslator); ok && onProgress != nil {
+		translated, err = pt.TranslateWithProgress(ctx, masked, mimeType, sourceLang, targetLang, onProgress)
+	} else {
+		translated, err = t.translator.Translate(ctx, masked, mimeType, sourceLang, targetLang)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return set.Restore(translated)
+}
+
+// Ensure implementation
+var _ ProgressTranslator = (*MaskingTranslator)(nil)
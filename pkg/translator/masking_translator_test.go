@@ -0,0 +1,62 @@
+package translator
+
+import (
+	"context"
+	"doc-translate-go/pkg/format"
+	"doc-translate-go/pkg/mask"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// funcTranslator translates with a function.
+type funcTranslator func(b []byte) []byte
+
+func (t funcTranslator) Translate(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error) {
+	return t(b), nil
+}
+
+func TestMaskingTranslator(t *testing.T) {
+	rules, err := mask.NewRules(mask.DefaultProductCodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var seen string
+	inner := funcTranslator(func(b []byte) []byte {
+		seen = string(b)
+		return []byte(strings.Replace(string(b), "Order", "Đặt hàng", 1))
+	})
+	translatr := NewMaskingTranslator(inner, rules)
+
+	got, err := translatr.Translate(context.Background(), []byte("Order AB-1234 at https://acme.example"), format.Text, "en", "vi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The translator never sees the protected text
+	if want := "Order <x1/> at <x2/>"; seen != want {
+		t.Fatalf("expected %q, got %q", want, seen)
+	}
+
+	if want := "Đặt hàng AB-1234 at https://acme.example"; string(got) != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestMaskingTranslator_Lost(t *testing.T) {
+	rules, err := mask.NewRules()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inner := funcTranslator(func(b []byte) []byte {
+		return []byte("Xin chào")
+	})
+	translatr := NewMaskingTranslator(inner, rules)
+
+	_, err = translatr.Translate(context.Background(), []byte("Hello {{name}}"), format.Text, "en", "vi")
+	if !errors.Is(err, mask.ErrLost) {
+		t.Fatalf("expected %v, got %v", mask.ErrLost, err)
+	}
+}
@@ -0,0 +1,41 @@
+package translator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// MemoryMatch is an approved translation of text close to the document, Score
+// being how close, from 0 to 1.
+type MemoryMatch struct {
+	Source string  `json:"source"`
+	Target string  `json:"target"`
+	Score  float64 `json:"score"`
+}
+
+type memoryKey struct{}
+
+// WithMemory returns a context carrying translation memory matches translators
+// pass on to their backend along with the document.
+func WithMemory(ctx context.Context, matches []MemoryMatch) context.Context {
+	return context.WithValue(ctx, memoryKey{}, matches)
+}
+
+// Memory returns the translation memory matches of ctx, if any.
+func Memory(ctx context.Context) []MemoryMatch {
+	matches, _ := ctx.Value(memoryKey{}).([]MemoryMatch)
+	return matches
+}
+
+// memoryHash tells matches apart in cache keys, empty for no match.
+func memoryHash(matches []MemoryMatch) string {
+	if len(matches) == 0 {
+		return ""
+	}
+
+	b, _ := json.Marshal(matches)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,39 @@
+package translator
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisCache shares translations between every instance of the service.
+// Size is bounded by redis' own maxmemory policy.
+type RedisCache struct {
+	r   *redis.ClusterClient
+	ttl time.Duration
+}
+
+// NewRedisCache returns a cache whose entries expire after ttl, or never when ttl is 0.
+func NewRedisCache(r *redis.ClusterClient, ttl time.Duration) *RedisCache {
+	return &RedisCache{r, ttl}
+}
+
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	b, err := c.r.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return b, true, nil
+}
+
+func (c *RedisCache) Set(ctx context.Context, key string, b []byte) error {
+	return c.r.Set(ctx, key, b, c.ttl).Err()
+}
+
+// Ensure implementation
+var _ Cache = (*RedisCache)(nil)
@@ -0,0 +1,40 @@
+package translator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+)
+
+func TestRedisCache(t *testing.T) {
+	client, mock := redismock.NewClusterMock()
+	c := NewRedisCache(client, time.Hour)
+
+	mock.ExpectSet("key", []byte("doc"), time.Hour).SetVal("OK")
+	mock.ExpectGet("key").SetVal("doc")
+	mock.ExpectGet("missing").RedisNil()
+
+	if err := c.Set(context.Background(), "key", []byte("doc")); err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok, err := c.Get(context.Background(), "key")
+	if err != nil || !ok {
+		t.Fatalf("expected hit, got %v, %v", ok, err)
+	}
+
+	if !reflect.DeepEqual([]byte("doc"), got) {
+		t.Fatalf("expected %s, got %s", "doc", got)
+	}
+
+	if _, ok, err := c.Get(context.Background(), "missing"); ok || err != nil {
+		t.Fatalf("expected miss, got %v, %v", ok, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,5 +1,17 @@
 package translator
 
+import "context"
+
 type Translator interface {
-	Translate(b []byte, sourceLang string, targetLang string) ([]byte, error)
+	Translate(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string) ([]byte, error)
+}
+
+// ProgressFunc receives how far along a translation is, from 0 to 100.
+type ProgressFunc func(percent int)
+
+// ProgressTranslator is implemented by translators able to report progress
+// while translating.
+type ProgressTranslator interface {
+	Translator
+	TranslateWithProgress(ctx context.Context, b []byte, mimeType string, sourceLang string, targetLang string, onProgress ProgressFunc) ([]byte, error)
 }
@@ -0,0 +1,52 @@
+package translator
+
+import (
+	"context"
+	"sync"
+)
+
+// Usage is what translating was billed for by the backends that did it.
+type Usage struct {
+	mu         sync.Mutex
+	characters int
+	tokens     int
+}
+
+// Characters returns the billed characters recorded so far.
+func (u *Usage) Characters() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.characters
+}
+
+// Tokens returns the billed tokens recorded so far.
+func (u *Usage) Tokens() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.tokens
+}
+
+func (u *Usage) add(characters int, tokens int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.characters += characters
+	u.tokens += tokens
+}
+
+type usageKey struct{}
+
+// WithUsage returns a context translators record their billed usage in.
+// Decorators pass the context along, so usage reaches the caller whichever
+// backend translated. Translations served from a cache record none.
+func WithUsage(ctx context.Context) (context.Context, *Usage) {
+	u := &Usage{}
+	return context.WithValue(ctx, usageKey{}, u), u
+}
+
+// RecordUsage adds to the usage of ctx, if it records any. Translators call it
+// with what the backend billed once a translation succeeds.
+func RecordUsage(ctx context.Context, characters int64, tokens int64) {
+	if u, ok := ctx.Value(usageKey{}).(*Usage); ok {
+		u.add(int(characters), int(tokens))
+	}
+}
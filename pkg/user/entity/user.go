@@ -3,10 +3,12 @@ package entity
 import "time"
 
 type User struct {
-	Id        int
-	Isid      string
-	Role      string
-	Email     string
+	Id    int
+	Isid  string
+	Role  string
+	Email string
+	// Team shares glossaries among its members, empty for none
+	Team      string
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
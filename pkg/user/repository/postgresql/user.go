@@ -15,11 +15,11 @@ func NewPostgresqlUserRepository(querier db.Querier) *PostgresqlUserRepository {
 }
 
 func (r *PostgresqlUserRepository) Create(u *entity.User) (int, error) {
-	cmd := `INSERT INTO users (isid, role, email, created_at, updated_at)
-                VALUES ($1, $2, $3, $4, $5)
+	cmd := `INSERT INTO users (isid, role, email, team, created_at, updated_at)
+                VALUES ($1, $2, $3, $4, $5, $6)
                 RETURNING id;`
 
-	row := r.querier.QueryRow(cmd, u.Isid, u.Role, u.Email, u.CreatedAt, u.UpdatedAt)
+	row := r.querier.QueryRow(cmd, u.Isid, u.Role, u.Email, u.Team, u.CreatedAt, u.UpdatedAt)
 
 	var id int
 	if err := row.Scan(&id); err != nil {
@@ -30,12 +30,12 @@ func (r *PostgresqlUserRepository) Create(u *entity.User) (int, error) {
 }
 
 func (r *PostgresqlUserRepository) GetByIsid(isid string) (*entity.User, error) {
-	cmd := `SELECT id, isid, role, email, created_at, updated_at FROM users WHERE isid = $1;`
+	cmd := `SELECT id, isid, role, email, team, created_at, updated_at FROM users WHERE isid = $1;`
 
 	row := r.querier.QueryRow(cmd, isid)
 
 	var u entity.User
-	err := row.Scan(&u.Id, &u.Isid, &u.Role, &u.Email, &u.CreatedAt, &u.UpdatedAt)
+	err := row.Scan(&u.Id, &u.Isid, &u.Role, &u.Email, &u.Team, &u.CreatedAt, &u.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -44,8 +44,8 @@ func (r *PostgresqlUserRepository) GetByIsid(isid string) (*entity.User, error)
 }
 
 func (r *PostgresqlUserRepository) Update(u *entity.User) error {
-	cmd := `UPDATE users SET isid = $1, role = $2, email = $3, updated_at = $4 WHERE id = $5;`
-	_, err := r.querier.Exec(cmd, u.Isid, u.Role, u.Email, u.UpdatedAt, u.Id)
+	cmd := `UPDATE users SET isid = $1, role = $2, email = $3, team = $4, updated_at = $5 WHERE id = $6;`
+	_, err := r.querier.Exec(cmd, u.Isid, u.Role, u.Email, u.Team, u.UpdatedAt, u.Id)
 	return err
 }
 
@@ -19,8 +19,8 @@ func TestPostgresqlOriginalFileMetadataRepository_Create(t *testing.T) {
 	rows := sqlmock.NewRows([]string{"id"}).
 		AddRow(ent.Id)
 
-	cmd := `INSERT INTO users \(isid, role, email, created_at, updated_at\)
-                VALUES \(\$1, \$2, \$3, \$4, \$5\)
+	cmd := `INSERT INTO users \(isid, role, email, team, created_at, updated_at\)
+                VALUES \(\$1, \$2, \$3, \$4, \$5, \$6\)
                 RETURNING id;`
 
 	mock.ExpectQuery(cmd).WillReturnRows(rows)
@@ -43,8 +43,8 @@ func TestPostgresqlOriginalFileMetadataRepository_Create_ScanErr(t *testing.T) {
 	repo := NewPostgresqlUserRepository(db)
 	ent := &entity.User{Id: 1}
 
-	cmd := `INSERT INTO users \(isid, role, email, created_at, updated_at\)
-                VALUES \(\$1, \$2, \$3, \$4, \$5\)
+	cmd := `INSERT INTO users \(isid, role, email, team, created_at, updated_at\)
+                VALUES \(\$1, \$2, \$3, \$4, \$5, \$6\)
                 RETURNING id;`
 
 	e := sql.ErrNoRows
@@ -70,10 +70,10 @@ func TestPostgresqlOriginalFileMetadataRepository_GetByIsid(t *testing.T) {
 	repo := NewPostgresqlUserRepository(db)
 	ent := &entity.User{Isid: "1"}
 
-	rows := sqlmock.NewRows([]string{"id", "isid", "role", "email", "created_at", "updated_at"}).
-		AddRow(ent.Id, ent.Isid, ent.Role, ent.Email, ent.CreatedAt, ent.UpdatedAt)
+	rows := sqlmock.NewRows([]string{"id", "isid", "role", "email", "team", "created_at", "updated_at"}).
+		AddRow(ent.Id, ent.Isid, ent.Role, ent.Email, ent.Team, ent.CreatedAt, ent.UpdatedAt)
 
-	cmd := `SELECT id, isid, role, email, created_at, updated_at FROM users WHERE isid = \$1;`
+	cmd := `SELECT id, isid, role, email, team, created_at, updated_at FROM users WHERE isid = \$1;`
 
 	mock.ExpectQuery(cmd).WillReturnRows(rows)
 
@@ -95,9 +95,9 @@ func TestPostgresqlOriginalFileMetadataRepository_GetByIsid_ScanErr(t *testing.T
 	repo := NewPostgresqlUserRepository(db)
 	ent := &entity.User{Isid: "1"}
 
-	rows := sqlmock.NewRows([]string{"id", "isid", "role", "email", "created_at", "updated_at"})
+	rows := sqlmock.NewRows([]string{"id", "isid", "role", "email", "team", "created_at", "updated_at"})
 
-	cmd := `SELECT id, isid, role, email, created_at, updated_at FROM users WHERE isid = \$1;`
+	cmd := `SELECT id, isid, role, email, team, created_at, updated_at FROM users WHERE isid = \$1;`
 
 	e := sql.ErrNoRows
 	mock.ExpectQuery(cmd).WillReturnRows(rows).WillReturnError(e)
@@ -120,7 +120,7 @@ func TestPostgresqlOriginalFileMetadataRepository_Update(t *testing.T) {
 	repo := NewPostgresqlUserRepository(db)
 	ent := &entity.User{}
 
-	cmd := `UPDATE users SET isid = \$1, role = \$2, email = \$3, updated_at = \$4 WHERE id = \$5;`
+	cmd := `UPDATE users SET isid = \$1, role = \$2, email = \$3, team = \$4, updated_at = \$5 WHERE id = \$6;`
 
 	mock.
 		ExpectExec(cmd).
@@ -139,7 +139,7 @@ func TestPostgresqlOriginalFileMetadataRepository_Update_Err(t *testing.T) {
 	repo := NewPostgresqlUserRepository(db)
 	ent := &entity.User{}
 
-	cmd := `UPDATE users SET isid = \$1, role = \$2, email = \$3, updated_at = \$4 WHERE id = \$5;`
+	cmd := `UPDATE users SET isid = \$1, role = \$2, email = \$3, team = \$4, updated_at = \$5 WHERE id = \$6;`
 
 	e := errors.New("exec error")
 	mock.
@@ -0,0 +1,69 @@
+package wordcount
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// runesPerToken is roughly how many characters of alphabetic text a
+// translation model reads as one token.
+const runesPerToken = 4
+
+// Count is the size of a text as translations are priced.
+type Count struct {
+	Words      int `json:"words"`
+	Characters int `json:"characters"`
+	Tokens     int `json:"tokens"`
+}
+
+// Text counts the words, non-space characters and estimated tokens of s.
+// Scripts written without spaces, such as Chinese or Japanese, count every
+// character as a word and a token.
+func Text(s string) Count {
+	var c Count
+	wordRunes := 0
+
+	endWord := func() {
+		if wordRunes > 0 {
+			c.Words++
+			c.Tokens += (wordRunes + runesPerToken - 1) / runesPerToken
+			wordRunes = 0
+		}
+	}
+
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r):
+			endWord()
+			continue
+		case isUnspaced(r):
+			endWord()
+			c.Words++
+			c.Tokens++
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			// Punctuation is a token of its own and ends the word before it
+			endWord()
+			c.Tokens++
+		default:
+			wordRunes++
+		}
+
+		c.Characters++
+	}
+	endWord()
+
+	return c
+}
+
+// Add returns the sum of both counts.
+func (c Count) Add(other Count) Count {
+	return Count{
+		Words:      c.Words + other.Words,
+		Characters: c.Characters + other.Characters,
+		Tokens:     c.Tokens + other.Tokens,
+	}
+}
+
+func isUnspaced(r rune) bool {
+	return r >= utf8.RuneSelf && unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar)
+}
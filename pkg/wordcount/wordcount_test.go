@@ -0,0 +1,30 @@
+package wordcount
+
+import "testing"
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		text string
+		want Count
+	}{
+		{"", Count{}},
+		{"Hello world", Count{Words: 2, Characters: 10, Tokens: 4}},
+		{"  Hello,  world!\n", Count{Words: 2, Characters: 12, Tokens: 6}},
+		{"Xin chào thế giới", Count{Words: 4, Characters: 14, Tokens: 4}},
+		{"你好世界", Count{Words: 4, Characters: 4, Tokens: 4}},
+		{"internationalization", Count{Words: 1, Characters: 20, Tokens: 5}},
+	}
+
+	for _, tt := range tests {
+		if got := Text(tt.text); got != tt.want {
+			t.Fatalf("%q: expected %v, got %v", tt.text, tt.want, got)
+		}
+	}
+}
+
+func TestCount_Add(t *testing.T) {
+	got := Count{1, 2, 3}.Add(Count{4, 5, 6})
+	if want := (Count{5, 7, 9}); got != want {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
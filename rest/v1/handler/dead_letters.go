@@ -0,0 +1,67 @@
+package handler
+
+import (
+	_ "doc-translate-go/pkg/file/entity"
+	"doc-translate-go/pkg/file/usecase"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RequeueDeadLettersRequest struct {
+	Ids []int `json:"ids"`
+}
+
+// ListDeadLetters - List dead-lettered translate tasks
+//
+// @Summary List dead-lettered translate tasks
+// @Description List translate tasks that failed permanently or ran out of attempts. Admin only.
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Authorization"
+// @Success 200 {array} entity.DeadLetter
+// @Failure 403 {string} string "Forbidden"
+// @Router /dead-letters [get]
+func ListDeadLetters(c echo.Context, deadLetterUseCase *usecase.DeadLetterUseCase) error {
+	deadLetters, err := deadLetterUseCase.List()
+	if err != nil {
+		c.Logger().Errorf("failed to list dead letters: %v", err)
+		return echo.ErrInternalServerError
+	}
+
+	return c.JSON(http.StatusOK, deadLetters)
+}
+
+// RequeueDeadLetters - Requeue dead-lettered translate tasks
+//
+// @Summary Requeue dead-lettered translate tasks
+// @Description Put dead-lettered translate tasks back on the translate queue. Admin only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Authorization"
+// @Param requeue_request body RequeueDeadLettersRequest true "Requeue Request"
+// @Success 200 {string} string "ok"
+// @Failure 400 {string} string "Bad request"
+// @Failure 403 {string} string "Forbidden"
+// @Router /dead-letters/requeue [post]
+func RequeueDeadLetters(c echo.Context, deadLetterUseCase *usecase.DeadLetterUseCase) error {
+	var req RequeueDeadLettersRequest
+	if err := c.Bind(&req); err != nil {
+		c.Logger().Errorf("failed to parse request: %v", err)
+		return echo.ErrBadRequest
+	}
+
+	if len(req.Ids) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "no dead letter ids"})
+	}
+
+	if err := deadLetterUseCase.Requeue(c.Request().Context(), req.Ids); err != nil {
+		c.Logger().Errorf("failed to requeue dead letters: %v", err)
+		return echo.ErrInternalServerError
+	}
+
+	return c.String(http.StatusOK, "ok")
+}
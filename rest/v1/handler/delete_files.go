@@ -64,7 +64,7 @@ func DeleteFiles(
 		origFilepaths = append(origFilepaths, fmt.Sprintf("%s/%s", user.Isid, m.Filename))
 	}
 
-	err = fileUseCase.DeleteMany(origFilepaths)
+	err = fileUseCase.DeleteMany(c.Request().Context(), origFilepaths)
 	if err != nil {
 		c.Logger().Errorf("failed to delete original files on S3: %v", err)
 		return echo.ErrInternalServerError
@@ -81,7 +81,7 @@ func DeleteFiles(
 		translFilepaths = append(translFilepaths, fmt.Sprintf("%s/%s", user.Isid, m.Filename))
 	}
 
-	err = fileUseCase.DeleteMany(translFilepaths)
+	err = fileUseCase.DeleteMany(c.Request().Context(), translFilepaths)
 	if err != nil {
 		c.Logger().Errorf("failed to delete translated files on S3: %v", err)
 		return echo.ErrInternalServerError
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"doc-translate-go/pkg/file/repository/filesystem"
+	"errors"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DownloadFile - Download a file from the filesystem store
+//
+// @Summary Download a file
+// @Description Serves a file from the local filesystem store through a signed, expiring url returned by the file store. Only available when FILE_STORE is filesystem.
+// @Tags Files
+// @Produce octet-stream
+// @Param path query string true "File path"
+// @Param expires query string true "Expiry as unix seconds"
+// @Param signature query string true "Url signature"
+// @Success 200 {file} binary
+// @Failure 403 {object} map[string]any "Invalid or expired url"
+// @Failure 404 {object} map[string]any "File not found"
+// @Failure 500 {object} map[string]any "Internal Server Error"
+// @Router /files [get]
+func DownloadFile(c echo.Context, fileRepo *filesystem.FilesystemFileRepository) error {
+	filepath := c.QueryParam("path")
+
+	if err := fileRepo.Verify(filepath, c.QueryParam("expires"), c.QueryParam("signature")); err != nil {
+		c.Logger().Errorf("rejected file url: %v", err)
+		return echo.ErrForbidden
+	}
+
+	b, err := fileRepo.Get(c.Request().Context(), filepath)
+	if errors.Is(err, os.ErrNotExist) {
+		return echo.ErrNotFound
+	}
+	if err != nil {
+		c.Logger().Errorf("failed to get file: %v", err)
+		return echo.ErrInternalServerError
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(filepath))
+	if contentType == "" {
+		contentType = echo.MIMEOctetStream
+	}
+
+	// Files are only ever downloaded, never rendered as whatever they look like
+	c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(filepath)}))
+	c.Response().Header().Set(echo.HeaderXContentTypeOptions, "nosniff")
+
+	return c.Blob(http.StatusOK, contentType, b)
+}
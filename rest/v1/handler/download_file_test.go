@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"context"
+	"doc-translate-go/pkg/file/repository/filesystem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestDownloadFile(t *testing.T) {
+	fileRepo := filesystem.NewFilesystemFileRepository(t.TempDir(), []byte("secret"), "/files", time.Minute)
+
+	for key, want := range map[string]string{
+		"isid/report.docx": `attachment; filename=report.docx`,
+		// Names needing it are quoted or encoded, never breaking out of the header
+		`isid/q"1 report.html`: `attachment; filename="q\"1 report.html"`,
+		"isid/báo cáo.txt":     `attachment; filename*=utf-8''b%C3%A1o%20c%C3%A1o.txt`,
+	} {
+		if err := fileRepo.Persist(context.Background(), []byte("content"), key); err != nil {
+			t.Fatal(err)
+		}
+
+		url, err := fileRepo.GetUrl(context.Background(), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, url, nil), rec)
+
+		if err := DownloadFile(c, fileRepo); err != nil {
+			t.Fatal(err)
+		}
+		if got := rec.Header().Get(echo.HeaderContentDisposition); got != want {
+			t.Fatalf("%v: expected %v, got %v", key, want, got)
+		}
+		if got := rec.Header().Get(echo.HeaderXContentTypeOptions); got != "nosniff" {
+			t.Fatalf("%v: expected nosniff, got %v", key, got)
+		}
+	}
+}
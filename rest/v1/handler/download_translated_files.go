@@ -58,7 +58,7 @@ func DownloadTranslatedFiles(c echo.Context, translatedFileUseCase *usecase.Tran
 		filepaths = append(filepaths, fmt.Sprintf("%s/%s", user.Isid, m.Filename))
 	}
 
-	data, errors := fileUseCase.GetMany(filepaths)
+	data, errors := fileUseCase.GetMany(c.Request().Context(), filepaths)
 	if len(errors) > 0 {
 		c.Logger().Errorf("failed to get files: %v", errors)
 		return echo.ErrInternalServerError
@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"doc-translate-go/pkg/glossary/entity"
+	"doc-translate-go/pkg/glossary/usecase"
+	userEntity "doc-translate-go/pkg/user/entity"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CreateGlossaryRequest struct {
+	Name       string `json:"name"`
+	SourceLang string `json:"source_lang"`
+	TargetLang string `json:"target_lang"`
+	// Team shares the glossary with the members of the user's team
+	Team  bool          `json:"team"`
+	Terms []entity.Term `json:"terms"`
+}
+
+type UpdateGlossaryRequest struct {
+	// Name is left as it is when empty
+	Name  string        `json:"name"`
+	Terms []entity.Term `json:"terms"`
+}
+
+// ListGlossaries - List glossaries
+//
+// @Summary List glossaries
+// @Description List the glossaries the user created or shares with their team.
+// @Tags Glossaries
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Authorization"
+// @Success 200 {array} entity.Glossary
+// @Router /glossaries [get]
+func ListGlossaries(c echo.Context, glossaryUseCase *usecase.GlossaryUseCase) error {
+	userProfile, ok := c.Get("userProfile").(*userEntity.UserProfile)
+	if !ok {
+		return echo.ErrBadRequest
+	}
+
+	glossaries, err := glossaryUseCase.List(userProfile.Isid)
+	if err != nil {
+		return glossaryError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, glossaries)
+}
+
+// CreateGlossary - Create a glossary
+//
+// @Summary Create a glossary
+// @Description Create a glossary of terms for one language pair, personal or shared with the user's team. Terms are required unless set otherwise.
+// @Tags Glossaries
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Authorization"
+// @Param glossary_request body CreateGlossaryRequest true "Glossary"
+// @Success 201 {object} entity.Glossary
+// @Failure 400 {string} string "Bad request"
+// @Router /glossaries [post]
+func CreateGlossary(c echo.Context, glossaryUseCase *usecase.GlossaryUseCase) error {
+	userProfile, ok := c.Get("userProfile").(*userEntity.UserProfile)
+	if !ok {
+		return echo.ErrBadRequest
+	}
+
+	var req CreateGlossaryRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	g := &entity.Glossary{
+		Name:       req.Name,
+		SourceLang: req.SourceLang,
+		TargetLang: req.TargetLang,
+		Terms:      req.Terms,
+	}
+
+	id, err := glossaryUseCase.Create(g, userProfile.Isid, req.Team)
+	if err != nil {
+		return glossaryError(c, err)
+	}
+	g.Id = id
+
+	return c.JSON(http.StatusCreated, g)
+}
+
+// GetGlossary - Get a glossary
+//
+// @Summary Get a glossary
+// @Description Get a glossary the user created or shares with their team, with its terms.
+// @Tags Glossaries
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Authorization"
+// @Param id path int true "Glossary id"
+// @Success 200 {object} entity.Glossary
+// @Failure 404 {string} string "Not found"
+// @Router /glossaries/{id} [get]
+func GetGlossary(c echo.Context, glossaryUseCase *usecase.GlossaryUseCase) error {
+	userProfile, ok := c.Get("userProfile").(*userEntity.UserProfile)
+	if !ok {
+		return echo.ErrBadRequest
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.ErrBadRequest
+	}
+
+	g, err := glossaryUseCase.Get(id, userProfile.Isid)
+	if err != nil {
+		return glossaryError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, g)
+}
+
+// UpdateGlossary - Update a glossary
+//
+// @Summary Update a glossary
+// @Description Rename a glossary and replace its terms. Its languages and team stay as they are.
+// @Tags Glossaries
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Authorization"
+// @Param id path int true "Glossary id"
+// @Param glossary_request body UpdateGlossaryRequest true "Glossary"
+// @Success 200 {object} entity.Glossary
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Not found"
+// @Router /glossaries/{id} [put]
+func UpdateGlossary(c echo.Context, glossaryUseCase *usecase.GlossaryUseCase) error {
+	userProfile, ok := c.Get("userProfile").(*userEntity.UserProfile)
+	if !ok {
+		return echo.ErrBadRequest
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.ErrBadRequest
+	}
+
+	var req UpdateGlossaryRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	g, err := glossaryUseCase.Update(id, userProfile.Isid, req.Name, req.Terms)
+	if err != nil {
+		return glossaryError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, g)
+}
+
+// DeleteGlossary - Delete a glossary
+//
+// @Summary Delete a glossary
+// @Description Delete a glossary. Files already translated with it keep their terminology flags.
+// @Tags Glossaries
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Authorization"
+// @Param id path int true "Glossary id"
+// @Success 200 {string} string "ok"
+// @Failure 404 {string} string "Not found"
+// @Router /glossaries/{id} [delete]
+func DeleteGlossary(c echo.Context, glossaryUseCase *usecase.GlossaryUseCase) error {
+	userProfile, ok := c.Get("userProfile").(*userEntity.UserProfile)
+	if !ok {
+		return echo.ErrBadRequest
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.ErrBadRequest
+	}
+
+	if err := glossaryUseCase.Delete(id, userProfile.Isid); err != nil {
+		return glossaryError(c, err)
+	}
+
+	return c.String(http.StatusOK, "ok")
+}
+
+// ImportGlossary - Import terms into a glossary
+//
+// @Summary Import terms into a glossary
+// @Description Add the terms of a CSV or TBX file to a glossary, replacing those with the same source. CSV rows are source, target and, optionally, whether the term is required. TBX terms are all required.
+// @Tags Glossaries
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Authorization"
+// @Param id path int true "Glossary id"
+// @Param file formData file true "CSV or TBX file"
+// @Success 200 {object} entity.Glossary
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Not found"
+// @Router /glossaries/{id}/import [post]
+func ImportGlossary(c echo.Context, glossaryUseCase *usecase.GlossaryUseCase) error {
+	userProfile, ok := c.Get("userProfile").(*userEntity.UserProfile)
+	if !ok {
+		return echo.ErrBadRequest
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.ErrBadRequest
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return echo.ErrBadRequest
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+	defer src.Close()
+
+	b, err := io.ReadAll(src)
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	g, err := glossaryUseCase.Import(id, userProfile.Isid, b, file.Filename)
+	if err != nil {
+		return glossaryError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, g)
+}
+
+// glossaryError answers with the status matching a glossary use case error.
+func glossaryError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalid), errors.Is(err, usecase.ErrNoTeam), errors.Is(err, usecase.ErrLanguageMismatch), errors.Is(err, usecase.ErrImportFormat):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("glossary request failed: %v", err)
+	return echo.ErrInternalServerError
+}
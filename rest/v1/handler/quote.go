@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"doc-translate-go/pkg/file/usecase"
+	"doc-translate-go/pkg/ooxml"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Quote - Estimate the cost of translating a document
+//
+// @Summary Estimate the cost of translating a document
+// @Description Count the words, characters and tokens of a file and estimate the cost and duration of translating it into each target language. Nothing is stored or queued.
+// @Tags Files
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Authorization"
+// @Param file formData file true "Upload file"
+// @Param sourceLang formData string true "Source Language"
+// @Param targetLang formData []string true "Target Languages, repeated or comma separated" collectionFormat(multi)
+// @Success 200 {object} usecase.FileQuote
+// @Failure 400 {string} string "Bad request"
+// @Failure 413 {string} string "Document decompresses past TRANSLATE_MAX_PART_BYTES"
+// @Router /quote [post]
+func Quote(c echo.Context, quoteUseCase *usecase.QuoteUseCase) error {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return echo.ErrBadRequest
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return echo.ErrBadRequest
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+	defer src.Close()
+
+	b, err := io.ReadAll(src)
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	sourceLang := c.FormValue("sourceLang")
+	targetLangs := parseTargetLangs(form.Value["targetLang"])
+	if len(targetLangs) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": usecase.ErrNoTargetLanguage.Error()})
+	}
+
+	// Unreadable files and unpriced language pairs are the caller's to fix
+	quote, err := quoteUseCase.Quote(b, file.Filename, sourceLang, targetLangs)
+	if errors.Is(err, ooxml.ErrPartTooLarge) {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, quote)
+}
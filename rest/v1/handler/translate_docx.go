@@ -1,27 +1,40 @@
 package handler
 
 import (
+	"context"
 	"doc-translate-go/pkg/file/usecase"
+	"doc-translate-go/pkg/format"
+	glossary "doc-translate-go/pkg/glossary/usecase"
+	"doc-translate-go/pkg/ooxml"
 	"doc-translate-go/pkg/user/entity"
+	"errors"
 	"io"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/labstack/echo/v4"
 )
 
-// Translate multiple DOCX files
+// Translate multiple documents
 //
-// @Summary Translate multiple DOCX files
-// @Description Send multiple files to the gRPC server for translation along with source and target language selections.
+// @Summary Translate multiple documents
+// @Description Send multiple files to the gRPC server for translation along with source and target language selections. DOCX, PPTX, XLSX, plain text, Markdown, HTML, SRT and WebVTT files are detected by content and must be enabled by TRANSLATE_FORMATS.
 // @Tags Files
 // @Accept multipart/form-data
 // @Security ApiKeyAuth
 // @Param Authorization header string true "Authorization"
 // @Param file formData []file true "Upload files"
 // @Param sourceLang formData string true "Source Language"
-// @Param targetLang formData string true "Target Language"
+// @Param targetLang formData []string true "Target Languages, repeated or comma separated" collectionFormat(multi)
+// @Param glossaryId formData int false "Glossary to translate with, for the source and every target language"
 // @Success 200 {string} string "Files sent successfully"
+// @Failure 400 {string} string "No target language, file extension doesn't match its content, or glossary for other languages"
+// @Failure 404 {string} string "Glossary not found"
+// @Failure 413 {string} string "Document decompresses past TRANSLATE_MAX_PART_BYTES"
+// @Failure 415 {string} string "Unsupported or disallowed format"
 // @Router /translate-docx [post]
 func TranslateDocx(c echo.Context, translateUseCase *usecase.TranslateUseCase) error {
 	userProfileValue := c.Get("userProfile")
@@ -37,9 +50,20 @@ func TranslateDocx(c echo.Context, translateUseCase *usecase.TranslateUseCase) e
 
 	files := form.File["file"]
 	sourceLang := c.FormValue("sourceLang")
-	targetLang := c.FormValue("targetLang")
+	targetLangs := parseTargetLangs(form.Value["targetLang"])
+	if len(targetLangs) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": usecase.ErrNoTargetLanguage.Error()})
+	}
 
-	errChan := make(chan error)
+	glossaryId := 0
+	if v := c.FormValue("glossaryId"); v != "" {
+		glossaryId, err = strconv.Atoi(v)
+		if err != nil {
+			return echo.ErrBadRequest
+		}
+	}
+
+	errChan := make(chan error, len(files))
 
 	wg := sync.WaitGroup{}
 
@@ -58,36 +82,73 @@ func TranslateDocx(c echo.Context, translateUseCase *usecase.TranslateUseCase) e
 		}
 
 		wg.Add(1)
-		go translateFile(&wg, b, file.Filename, int(file.Size), userProfile.Isid, sourceLang, targetLang, translateUseCase, errChan)
+		go translateFile(c.Request().Context(), &wg, b, file.Filename, int(file.Size), userProfile.Isid, sourceLang, targetLangs, glossaryId, translateUseCase, errChan)
 	}
 
 	wg.Wait()
 
 	close(errChan)
 
+	// Files are queued independently of each other, the first failure is reported
 	for e := range errChan {
-		c.JSON(http.StatusInternalServerError, map[string]string{"error": e.Error()})
+		return translateError(c, e)
 	}
 
 	return nil
 }
 
+// translateError answers a file TranslateAsync refused with a client error,
+// and any other failure with a server error.
+func translateError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrFormatNotAllowed), errors.Is(err, format.ErrUnsupported):
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{"error": err.Error()})
+	case errors.Is(err, format.ErrExtensionMismatch), errors.Is(err, glossary.ErrLanguageMismatch), errors.Is(err, usecase.ErrNoTargetLanguage):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, glossary.ErrNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ooxml.ErrPartTooLarge):
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
+
 func translateFile(
+	ctx context.Context,
 	wg *sync.WaitGroup,
 	b []byte,
 	filename string,
 	filesize int,
 	isid string,
 	sourceLang string,
-	targetLang string,
+	targetLangs []string,
+	glossaryId int,
 	translateUseCase *usecase.TranslateUseCase,
 	errChan chan error,
 ) {
 	defer wg.Done()
 
-	err := translateUseCase.TranslateAsync(b, filename, filesize, isid, sourceLang, targetLang)
+	err := translateUseCase.TranslateAsync(ctx, b, filename, filesize, isid, sourceLang, targetLangs, glossaryId)
 	if err != nil {
 		errChan <- err
 		return
 	}
 }
+
+// parseTargetLangs accepts the targetLang field repeated, comma separated or both,
+// and drops duplicates.
+func parseTargetLangs(values []string) []string {
+	var targetLangs []string
+
+	for _, v := range values {
+		for _, lang := range strings.Split(v, ",") {
+			lang = strings.TrimSpace(lang)
+			if lang != "" && !slices.Contains(targetLangs, lang) {
+				targetLangs = append(targetLangs, lang)
+			}
+		}
+	}
+
+	return targetLangs
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"doc-translate-go/pkg/file/usecase"
+	"doc-translate-go/pkg/format"
+	glossary "doc-translate-go/pkg/glossary/usecase"
+	"doc-translate-go/pkg/ooxml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestParseTargetLangs(t *testing.T) {
+	for _, test := range []struct {
+		values []string
+		want   []string
+	}{
+		{nil, nil},
+		{[]string{"vi"}, []string{"vi"}},
+		{[]string{"vi", "ja"}, []string{"vi", "ja"}},
+		{[]string{"vi, ja,,fr "}, []string{"vi", "ja", "fr"}},
+		// Duplicates are dropped, whichever way they're given
+		{[]string{"vi,ja", "ja", " vi"}, []string{"vi", "ja"}},
+		{[]string{"", " , "}, nil},
+	} {
+		if got := parseTargetLangs(test.values); !reflect.DeepEqual(test.want, got) {
+			t.Fatalf("%q: expected %v, got %v", test.values, test.want, got)
+		}
+	}
+}
+
+func TestTranslateError(t *testing.T) {
+	for err, want := range map[error]int{
+		fmt.Errorf("%w: txt", usecase.ErrFormatNotAllowed):                       http.StatusUnsupportedMediaType,
+		format.ErrUnsupported:                                                    http.StatusUnsupportedMediaType,
+		format.ErrExtensionMismatch:                                              http.StatusBadRequest,
+		usecase.ErrNoTargetLanguage:                                              http.StatusBadRequest,
+		glossary.ErrNotFound:                                                     http.StatusNotFound,
+		fmt.Errorf("%w: word/document.xml", ooxml.ErrPartTooLarge):               http.StatusRequestEntityTooLarge,
+		fmt.Errorf("%w: Products is for en to ja", glossary.ErrLanguageMismatch): http.StatusBadRequest,
+		errors.New("failed to persist file"):                                     http.StatusInternalServerError,
+	} {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/translate-docx", nil), rec)
+
+		if err := translateError(c, err); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != want {
+			t.Fatalf("%v: expected %v, got %v", err, want, rec.Code)
+		}
+	}
+}
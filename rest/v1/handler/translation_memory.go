@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"doc-translate-go/pkg/memory/usecase"
+	userEntity "doc-translate-go/pkg/user/entity"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ImportTranslationMemoryResponse struct {
+	// Imported is how many translations were stored
+	Imported int `json:"imported"`
+}
+
+// ImportTranslationMemory - Import a TMX file into the translation memory
+//
+// @Summary Import a TMX file into the translation memory
+// @Description Store the translations of a TMX 1.4 file from one language into another as approved, replacing those stored for the same segments. Regional variants of the languages, such as en-US for en, are imported too. Admin only.
+// @Tags Admin
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Authorization"
+// @Param file formData file true "TMX file"
+// @Param source_lang formData string true "Source language"
+// @Param target_lang formData string true "Target language"
+// @Success 200 {object} ImportTranslationMemoryResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 403 {string} string "Forbidden"
+// @Router /translation-memory/import [post]
+func ImportTranslationMemory(c echo.Context, memoryUseCase *usecase.TranslationMemoryUseCase) error {
+	userProfile, ok := c.Get("userProfile").(*userEntity.UserProfile)
+	if !ok {
+		return echo.ErrBadRequest
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return echo.ErrBadRequest
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+	defer src.Close()
+
+	b, err := io.ReadAll(src)
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	n, err := memoryUseCase.Import(b, c.FormValue("source_lang"), c.FormValue("target_lang"), userProfile.Isid)
+	if err != nil {
+		return translationMemoryError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, ImportTranslationMemoryResponse{n})
+}
+
+// ExportTranslationMemory - Export the translation memory as a TMX file
+//
+// @Summary Export the translation memory as a TMX file
+// @Description Download the translations from one language into another as a TMX 1.4 file. Admin only.
+// @Tags Admin
+// @Produce xml
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Authorization"
+// @Param source_lang query string true "Source language"
+// @Param target_lang query string true "Target language"
+// @Success 200 {file} binary
+// @Failure 400 {string} string "Bad request"
+// @Failure 403 {string} string "Forbidden"
+// @Router /translation-memory/export [get]
+func ExportTranslationMemory(c echo.Context, memoryUseCase *usecase.TranslationMemoryUseCase) error {
+	sourceLang, targetLang := c.QueryParam("source_lang"), c.QueryParam("target_lang")
+
+	b, err := memoryUseCase.Export(sourceLang, targetLang)
+	if err != nil {
+		return translationMemoryError(c, err)
+	}
+
+	filename := fmt.Sprintf("translation-memory-%s-to-%s.tmx", sourceLang, targetLang)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	return c.Blob(http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, b)
+}
+
+// translationMemoryError answers with the status matching a translation
+// memory use case error.
+func translationMemoryError(c echo.Context, err error) error {
+	if errors.Is(err, usecase.ErrInvalid) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("translation memory request failed: %v", err)
+	return echo.ErrInternalServerError
+}
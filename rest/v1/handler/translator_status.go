@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"doc-translate-go/pkg/translator"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TranslatorStatus - Show translator circuit breaker states
+//
+// @Summary Show translator circuit breaker states
+// @Description List the circuit breaker of every translator backend in the order they are tried. Admin only.
+// @Tags Monitoring
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Authorization"
+// @Success 200 {array} translator.BreakerState
+// @Failure 403 {string} string "Forbidden"
+// @Router /translator-status [get]
+func TranslatorStatus(c echo.Context, translatorChain *translator.FallbackTranslator) error {
+	return c.JSON(http.StatusOK, translatorChain.BreakerStates())
+}
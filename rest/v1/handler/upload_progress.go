@@ -2,8 +2,11 @@ package handler
 
 import (
 	"doc-translate-go/pkg/file/usecase"
+	"doc-translate-go/pkg/tracker"
 	"doc-translate-go/pkg/user/entity"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -12,11 +15,13 @@ import (
 )
 
 type Status struct {
-	File       string    `json:"file"`
-	Status     string    `json:"status"`
-	SourceLang string    `json:"source_lang"`
-	TargetLang string    `json:"target_lang"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	File       string        `json:"file"`
+	Status     string        `json:"status"`
+	Stage      tracker.Stage `json:"stage" enums:"queued,downloading,translating,uploading,done,failed"`
+	Progress   int           `json:"progress"`
+	SourceLang string        `json:"source_lang"`
+	TargetLang string        `json:"target_lang"`
+	UpdatedAt  time.Time     `json:"updated_at"`
 }
 
 type UploadProgressResponse struct {
@@ -41,7 +46,7 @@ func UploadProgress(c echo.Context, progressUseCase *usecase.ProgressUseCase) er
 		return echo.ErrBadRequest
 	}
 
-	statuses, err := progressUseCase.ListByIsid(user.Isid)
+	statuses, err := progressUseCase.ListByIsid(c.Request().Context(), user.Isid)
 	if err != nil {
 		c.Logger().Errorf("unable to get file status: %v", err)
 		return echo.ErrInternalServerError
@@ -50,16 +55,105 @@ func UploadProgress(c echo.Context, progressUseCase *usecase.ProgressUseCase) er
 	resp := UploadProgressResponse{Isid: user.Isid}
 
 	for _, s := range statuses {
-		stt := &Status{
-			File:       strings.Replace(s.Key, fmt.Sprintf("%s_", user.Isid), "", 1),
-			Status:     strings.Replace(s.Status, "fail:", "", 1),
-			UpdatedAt:  s.UpdatedAt,
-			SourceLang: s.SourceLang,
-			TargetLang: s.TargetLang,
+		resp.Statuses = append(resp.Statuses, toStatus(user.Isid, s))
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Stream file upload progress
+// @Description Push the user's file statuses as Server-Sent Events. The current statuses are sent first, then a "status" event whenever a file is in progress, failed or done, and a "removed" event when its status is cleared.
+// @Tags Files
+// @Produce text/event-stream
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Authorization"
+// @Success 200 {object} Status
+// @Router /upload-progress/stream [get]
+func UploadProgressStream(c echo.Context, progressUseCase *usecase.ProgressUseCase) error {
+	userProfileValue := c.Get("userProfile")
+	user, ok := userProfileValue.(*entity.UserProfile)
+	if !ok {
+		c.Logger().Error("user profile not found")
+		return echo.ErrBadRequest
+	}
+
+	ctx := c.Request().Context()
+
+	// Subscribe before listing so nothing in between is missed
+	updates, err := progressUseCase.Subscribe(ctx, user.Isid)
+	if err != nil {
+		c.Logger().Errorf("unable to subscribe to file status: %v", err)
+		return echo.ErrInternalServerError
+	}
+
+	statuses, err := progressUseCase.ListByIsid(ctx, user.Isid)
+	if err != nil {
+		c.Logger().Errorf("unable to get file status: %v", err)
+		return echo.ErrInternalServerError
+	}
+
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set(echo.HeaderConnection, "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	for _, s := range statuses {
+		if err := writeStatusEvent(w, user.Isid, s); err != nil {
+			return nil
+		}
+	}
+	w.Flush()
+
+	// Comments keep proxies from closing an idle stream
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+		case s, ok := <-updates:
+			if !ok {
+				return nil
+			}
+
+			if err := writeStatusEvent(w, user.Isid, s); err != nil {
+				return nil
+			}
 		}
 
-		resp.Statuses = append(resp.Statuses, stt)
+		w.Flush()
 	}
+}
 
-	return c.JSON(http.StatusOK, resp)
+func writeStatusEvent(w io.Writer, isid string, s *tracker.FileStatus) error {
+	event := "status"
+	if s.Status == tracker.StatusRemoved {
+		event = "removed"
+	}
+
+	data, err := json.Marshal(toStatus(isid, s))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
+
+func toStatus(isid string, s *tracker.FileStatus) *Status {
+	return &Status{
+		File:       strings.TrimPrefix(s.Key, fmt.Sprintf("%s_%s_", isid, s.TargetLang)),
+		Status:     strings.Replace(s.Status, "fail:", "", 1),
+		Stage:      s.Stage,
+		Progress:   s.Progress,
+		UpdatedAt:  s.UpdatedAt,
+		SourceLang: s.SourceLang,
+		TargetLang: s.TargetLang,
+	}
 }
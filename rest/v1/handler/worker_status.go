@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"doc-translate-go/pkg/file/usecase"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// WorkerStatus - Show translate worker states
+//
+// @Summary Show translate worker states
+// @Description List whether each translate worker is busy translating or idle waiting for a task. Admin only.
+// @Tags Monitoring
+// @Produce json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Authorization"
+// @Success 200 {array} usecase.WorkerState
+// @Failure 403 {string} string "Forbidden"
+// @Router /worker-status [get]
+func WorkerStatus(c echo.Context, translateUseCase *usecase.TranslateUseCase) error {
+	return c.JSON(http.StatusOK, translateUseCase.WorkerStates())
+}
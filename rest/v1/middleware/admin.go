@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"doc-translate-go/pkg/user/entity"
+	"doc-translate-go/pkg/user/usecase"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminMiddleware only lets users with the admin role through.
+// It must run after AuthMiddleware.
+func AdminMiddleware(userUseCase *usecase.UserUseCase) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userProfile, ok := c.Get("userProfile").(*entity.UserProfile)
+			if !ok {
+				c.Logger().Error("user profile not found")
+				return echo.ErrUnauthorized
+			}
+
+			user, err := userUseCase.GetByIsid(userProfile.Isid)
+			if err != nil || user == nil || user.Role != "admin" {
+				return echo.ErrForbidden
+			}
+
+			return next(c)
+		}
+	}
+}
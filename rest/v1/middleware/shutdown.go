@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ShutdownMiddleware cancels the request's context once ctx is done. Shutting
+// the server down waits for open requests, so long-lived ones such as event
+// streams must be told to end.
+func ShutdownMiddleware(ctx context.Context) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			reqCtx, cancel := context.WithCancel(c.Request().Context())
+			defer cancel()
+
+			stop := context.AfterFunc(ctx, cancel)
+			defer stop()
+
+			c.SetRequest(c.Request().WithContext(reqCtx))
+
+			return next(c)
+		}
+	}
+}